@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/iinuma0710/react-go-blog/backend/clock"
+	"github.com/iinuma0710/react-go-blog/backend/entity"
+	"github.com/iinuma0710/react-go-blog/backend/service"
+	"github.com/iinuma0710/react-go-blog/backend/store"
+)
+
+// パスワードの最小の長さ
+const minPasswordLength = 8
+
+func setupCreateAdmin(fs *flag.FlagSet) func(ctx context.Context, e *cmdEnv, args []string) error {
+	name := fs.String("name", "", "ユーザ名 (必須、20 文字以内)")
+	email := fs.String("email", "", "メールアドレス (必須)")
+	stdin := fs.Bool("password-stdin", false, "パスワードを標準入力の 1 行目から読み込む (省略した場合は生成して表示する)")
+
+	return func(ctx context.Context, e *cmdEnv, args []string) error {
+		if len(args) != 0 || *name == "" || *email == "" || len([]rune(*name)) > 20 {
+			return errUsage
+		}
+
+		password, generated := "", false
+		if *stdin {
+			line, err := bufio.NewReader(e.stdin).ReadString('\n')
+			if err != nil && line == "" {
+				return fmt.Errorf("cannot read password from stdin: %w", err)
+			}
+			password = strings.TrimRight(line, "\r\n")
+		} else {
+			b := make([]byte, 18)
+			if _, err := rand.Read(b); err != nil {
+				return err
+			}
+			password, generated = base64.RawURLEncoding.EncodeToString(b), true
+		}
+		if len(password) < minPasswordLength {
+			return fmt.Errorf("password must be at least %d characters", minPasswordLength)
+		}
+
+		ctx, _, err := e.setupLogger(ctx, e.stderr)
+		if err != nil {
+			return err
+		}
+		db, cleanup, err := e.openDB(ctx)
+		defer cleanup()
+		if err != nil {
+			return err
+		}
+
+		ru := &service.RegisterUser{DB: db, Repo: &store.Repository{Clocker: clock.RealClocker{}}}
+		u, err := ru.RegisterUser(ctx, *name, *email, password, entity.RoleAdmin)
+		if errors.Is(err, store.ErrAlreadyExists) {
+			return fmt.Errorf("user %q already exists", *name)
+		}
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(e.stdout, "created admin user %q (id: %d)\n", u.Name, u.ID)
+		if generated {
+			fmt.Fprintf(e.stdout, "password: %s\n", password)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/iinuma0710/react-go-blog/backend/backup"
+	"github.com/iinuma0710/react-go-blog/backend/clock"
+	"github.com/iinuma0710/react-go-blog/backend/media"
+	"github.com/iinuma0710/react-go-blog/backend/migration"
+	"github.com/iinuma0710/react-go-blog/backend/store"
+)
+
+func setupBackup(fs *flag.FlagSet) func(ctx context.Context, e *cmdEnv, args []string) error {
+	out := fs.String("out", "", "バックアップの書き出し先のファイル (必須、.jsonl.gz を推奨)")
+	blobs := fs.String("blobs", "", "メディアのファイルをまとめる tar ファイル (省略した場合はメタデータだけをバックアップする)")
+
+	return func(ctx context.Context, e *cmdEnv, args []string) error {
+		if len(args) != 0 || *out == "" {
+			return errUsage
+		}
+
+		ctx, _, err := e.setupLogger(ctx, e.stderr)
+		if err != nil {
+			return err
+		}
+		db, cleanup, err := e.openDB(ctx)
+		defer cleanup()
+		if err != nil {
+			return err
+		}
+		mg, err := migration.New(db.DB, db.DriverName())
+		if err != nil {
+			return err
+		}
+		schema, err := mg.Current(ctx)
+		if err != nil {
+			return err
+		}
+
+		// 書き出し中の更新が混ざらないよう、1 つのトランザクションで読み出す
+		tx, err := db.BeginTxx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		repo := &store.Repository{Clocker: clock.RealClocker{}}
+		var sum backup.Summary
+		err = writeFile(*out, func(w io.Writer) error {
+			sum, err = backup.Dump(ctx, tx, repo, w, schema, clock.RealClocker{}.Now())
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		printBackupSummary(e.stdout, "backed up", sum)
+
+		if *blobs == "" {
+			return nil
+		}
+		ms, err := repo.ListMedia(ctx, tx)
+		if err != nil {
+			return err
+		}
+		var rep *backup.BlobReport
+		err = writeFile(*blobs, func(w io.Writer) error {
+			rep, err = backup.DumpBlobs(ctx, w, media.Dir(e.cfg.MediaDir), ms)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		printBlobReport(e.stdout, "backed up", rep)
+		return nil
+	}
+}
+
+func setupRestore(fs *flag.FlagSet) func(ctx context.Context, e *cmdEnv, args []string) error {
+	blobs := fs.String("blobs", "", "backup -blobs で書き出した tar ファイル (メディアのファイルも復元する場合)")
+
+	return func(ctx context.Context, e *cmdEnv, args []string) error {
+		if len(args) != 1 {
+			return errUsage
+		}
+		f, err := os.Open(args[0])
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		var bf *os.File
+		if *blobs != "" {
+			if bf, err = os.Open(*blobs); err != nil {
+				return err
+			}
+			defer bf.Close()
+		}
+
+		ctx, _, err = e.setupLogger(ctx, e.stderr)
+		if err != nil {
+			return err
+		}
+		// 復元先は `migrate up` で最新のスキーマにした空のデータベースにする
+		db, cleanup, err := e.openDB(ctx)
+		defer cleanup()
+		if err != nil {
+			return err
+		}
+		mg, err := migration.New(db.DB, db.DriverName())
+		if err != nil {
+			return err
+		}
+		schema, err := mg.Current(ctx)
+		if err != nil {
+			return err
+		}
+
+		tx, err := db.BeginTxx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		repo := &store.Repository{Clocker: clock.RealClocker{}}
+		h, sum, err := backup.Restore(ctx, tx, repo, f, schema)
+		if err != nil {
+			return err
+		}
+		// ファイルの復元に失敗した場合もデータベースへの復元を取り消す
+		var rep *backup.BlobReport
+		if bf != nil {
+			ms, err := repo.ListMedia(ctx, tx)
+			if err != nil {
+				return err
+			}
+			if rep, err = backup.RestoreBlobs(ctx, bf, media.Dir(e.cfg.MediaDir), ms); err != nil {
+				return err
+			}
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+
+		fmt.Fprintf(e.stdout, "backup created at %s (schema version %d)\n", h.CreatedAt.Format("2006-01-02 15:04:05 MST"), h.SchemaVersion)
+		printBackupSummary(e.stdout, "restored", sum)
+		if rep != nil {
+			printBlobReport(e.stdout, "restored", rep)
+		}
+		return nil
+	}
+}
+
+// path に書き出し、失敗した場合は書きかけのファイルを残さない
+func writeFile(path string, fn func(w io.Writer) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := fn(f); err != nil {
+		f.Close()
+		return errors.Join(err, os.Remove(path))
+	}
+	return f.Close()
+}
+
+func printBackupSummary(w io.Writer, verb string, sum backup.Summary) {
+	counts := make([]string, 0, len(sum))
+	for _, t := range backup.Tables() {
+		counts = append(counts, fmt.Sprintf("%s %d", t, sum[t]))
+	}
+	fmt.Fprintf(w, "%s: %s\n", verb, strings.Join(counts, ", "))
+}
+
+func printBlobReport(w io.Writer, verb string, rep *backup.BlobReport) {
+	fmt.Fprintf(w, "%s %d media files (%d bytes)\n", verb, rep.Files, rep.Bytes)
+	for _, key := range rep.Missing {
+		fmt.Fprintf(w, "missing media: %s\n", key)
+	}
+}
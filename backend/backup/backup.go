@@ -0,0 +1,307 @@
+// データベースに依存しない形式でデータをバックアップし、空のデータベースへ復元する
+//
+// アーカイブは gzip で圧縮した JSON Lines で、1 行目がヘッダ、最終行がテーブルごとの件数になる
+//
+//	{"type":"header","data":{"format":"react-go-blog-backup","version":1,"schema_version":5,...}}
+//	{"type":"user","data":{"id":1,"name":"admin",...}}
+//	...
+//	{"type":"end","data":{"user":1,"article":10,...}}
+package backup
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/iinuma0710/react-go-blog/backend/entity"
+	"github.com/iinuma0710/react-go-blog/backend/store"
+)
+
+const (
+	// アーカイブの形式の識別子
+	Format = "react-go-blog-backup"
+	// アーカイブの形式のバージョン (レコードの形式を変えたときに上げる)
+	Version = 1
+)
+
+var (
+	ErrFormat = errors.New("not a backup archive")
+	// アーカイブの形式のバージョンがこのバイナリより新しい
+	ErrUnsupportedVersion = errors.New("unsupported backup version")
+	// アーカイブを作成したデータベースのスキーマが復元先より新しい
+	ErrSchemaTooNew = errors.New("backup schema is newer than the database")
+	// 復元先のデータベースにレコードが存在する
+	ErrNotEmpty = errors.New("database is not empty")
+	// 最終行の件数がない、または件数が一致しない
+	ErrTruncated = errors.New("backup archive is truncated")
+)
+
+//go:generate go run github.com/matryer/moq -out moq_test.go . Repository
+type Repository interface {
+	ListUsers(ctx context.Context, db store.Queryer) ([]*entity.User, error)
+	ListTags(ctx context.Context, db store.Queryer) ([]*entity.Tag, error)
+	ListArticles(ctx context.Context, db store.Queryer) (entity.Articles, error)
+	ListArticleTagIDs(ctx context.Context, db store.Queryer) ([]entity.ArticleTag, error)
+	ListComments(ctx context.Context, db store.Queryer) ([]*entity.Comment, error)
+	ListMedia(ctx context.Context, db store.Queryer) ([]*entity.Media, error)
+	ListArticleSources(ctx context.Context, db store.Queryer) ([]*entity.ArticleSource, error)
+
+	HasBackupData(ctx context.Context, db store.Queryer) (bool, error)
+	RestoreUser(ctx context.Context, db store.Execer, u *entity.User) error
+	RestoreTag(ctx context.Context, db store.Execer, t *entity.Tag) error
+	RestoreArticle(ctx context.Context, db store.Execer, a *entity.Article) error
+	AddArticleTag(ctx context.Context, db store.Execer, articleID entity.ArticleID, tagID entity.TagID) error
+	RestoreComment(ctx context.Context, db store.Execer, c *entity.Comment) error
+	RestoreMedia(ctx context.Context, db store.Execer, m *entity.Media) error
+	RestoreArticleSource(ctx context.Context, db store.Execer, s *entity.ArticleSource) error
+}
+
+type Header struct {
+	Format  string `json:"format"`
+	Version int    `json:"version"`
+	// アーカイブを作成したデータベースに適用済みのマイグレーションのバージョン
+	SchemaVersion int64     `json:"schema_version"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// テーブルごとのレコードの件数
+type Summary map[string]int
+
+const (
+	headerType = "header"
+	endType    = "end"
+)
+
+type line struct {
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
+// テーブルごとのレコードの読み書き
+// 参照先のテーブルが先になる順に並べる
+var tables = []struct {
+	name    string
+	dump    func(ctx context.Context, db store.Queryer, repo Repository, emit func(v any) error) error
+	restore func(ctx context.Context, db store.Execer, repo Repository, data []byte) error
+}{
+	{
+		name: "user",
+		dump: func(ctx context.Context, db store.Queryer, repo Repository, emit func(v any) error) error {
+			return dumpAll(repo.ListUsers(ctx, db))(func(u *entity.User) error { return emit(user(*u)) })
+		},
+		restore: func(ctx context.Context, db store.Execer, repo Repository, data []byte) error {
+			return restoreOne(data, func(u *user) error { return repo.RestoreUser(ctx, db, (*entity.User)(u)) })
+		},
+	},
+	{
+		name: "tag",
+		dump: func(ctx context.Context, db store.Queryer, repo Repository, emit func(v any) error) error {
+			return dumpAll(repo.ListTags(ctx, db))(func(t *entity.Tag) error { return emit(tag(*t)) })
+		},
+		restore: func(ctx context.Context, db store.Execer, repo Repository, data []byte) error {
+			return restoreOne(data, func(t *tag) error { return repo.RestoreTag(ctx, db, (*entity.Tag)(t)) })
+		},
+	},
+	{
+		name: "article",
+		dump: func(ctx context.Context, db store.Queryer, repo Repository, emit func(v any) error) error {
+			return dumpAll(repo.ListArticles(ctx, db))(func(a *entity.Article) error { return emit(article(*a)) })
+		},
+		restore: func(ctx context.Context, db store.Execer, repo Repository, data []byte) error {
+			return restoreOne(data, func(a *article) error { return repo.RestoreArticle(ctx, db, (*entity.Article)(a)) })
+		},
+	},
+	{
+		name: "article_tag",
+		dump: func(ctx context.Context, db store.Queryer, repo Repository, emit func(v any) error) error {
+			return dumpAll(repo.ListArticleTagIDs(ctx, db))(func(at entity.ArticleTag) error { return emit(articleTag(at)) })
+		},
+		restore: func(ctx context.Context, db store.Execer, repo Repository, data []byte) error {
+			return restoreOne(data, func(at *articleTag) error { return repo.AddArticleTag(ctx, db, at.ArticleID, at.TagID) })
+		},
+	},
+	{
+		name: "comment",
+		dump: func(ctx context.Context, db store.Queryer, repo Repository, emit func(v any) error) error {
+			return dumpAll(repo.ListComments(ctx, db))(func(c *entity.Comment) error { return emit(comment(*c)) })
+		},
+		restore: func(ctx context.Context, db store.Execer, repo Repository, data []byte) error {
+			return restoreOne(data, func(c *comment) error { return repo.RestoreComment(ctx, db, (*entity.Comment)(c)) })
+		},
+	},
+	{
+		name: "media",
+		dump: func(ctx context.Context, db store.Queryer, repo Repository, emit func(v any) error) error {
+			return dumpAll(repo.ListMedia(ctx, db))(func(m *entity.Media) error { return emit(mediaFile(*m)) })
+		},
+		restore: func(ctx context.Context, db store.Execer, repo Repository, data []byte) error {
+			return restoreOne(data, func(m *mediaFile) error { return repo.RestoreMedia(ctx, db, (*entity.Media)(m)) })
+		},
+	},
+	{
+		name: "article_source",
+		dump: func(ctx context.Context, db store.Queryer, repo Repository, emit func(v any) error) error {
+			return dumpAll(repo.ListArticleSources(ctx, db))(func(s *entity.ArticleSource) error { return emit(articleSource(*s)) })
+		},
+		restore: func(ctx context.Context, db store.Execer, repo Repository, data []byte) error {
+			return restoreOne(data, func(s *articleSource) error { return repo.RestoreArticleSource(ctx, db, (*entity.ArticleSource)(s)) })
+		},
+	},
+}
+
+// 一覧の取得に失敗した場合はエラーを返し、成功した場合はレコードごとに fn を呼ぶ
+func dumpAll[T any](records []T, err error) func(fn func(T) error) error {
+	return func(fn func(T) error) error {
+		if err != nil {
+			return err
+		}
+		for _, r := range records {
+			if err := fn(r); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func restoreOne[T any](data []byte, fn func(*T) error) error {
+	v := new(T)
+	if err := json.Unmarshal(data, v); err != nil {
+		return err
+	}
+	return fn(v)
+}
+
+// すべてのテーブルのレコードをアーカイブとして w に書き出す
+// 途中で書き換えられないよう、db は読み取り専用のトランザクションにしておく
+func Dump(ctx context.Context, db store.Queryer, repo Repository, w io.Writer, schemaVersion int64, now time.Time) (Summary, error) {
+	zw := gzip.NewWriter(w)
+	enc := json.NewEncoder(zw)
+	write := func(typ string, v any) error {
+		b, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		return enc.Encode(line{Type: typ, Data: b})
+	}
+
+	h := Header{Format: Format, Version: Version, SchemaVersion: schemaVersion, CreatedAt: now}
+	if err := write(headerType, h); err != nil {
+		return nil, err
+	}
+	sum := Summary{}
+	for _, t := range tables {
+		err := t.dump(ctx, db, repo, func(v any) error {
+			sum[t.name]++
+			return write(t.name, v)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to dump %s: %w", t.name, err)
+		}
+	}
+	if err := write(endType, sum); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return sum, nil
+}
+
+// アーカイブを読み込んで、レコードが存在しないデータベースへ復元する
+// schemaVersion は復元先のデータベースに適用済みのマイグレーションのバージョンで、
+// それより新しいスキーマで作成したアーカイブは、列が足りない可能性があるので復元しない
+// 途中で失敗した場合に一部だけが復元されないよう、db はトランザクションにしておく
+func Restore(ctx context.Context, db store.ExecQueryer, repo Repository, r io.Reader, schemaVersion int64) (*Header, Summary, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrFormat, err)
+	}
+	defer zr.Close()
+	dec := json.NewDecoder(zr)
+
+	var (
+		l line
+		h Header
+	)
+	if err := dec.Decode(&l); err != nil || l.Type != headerType {
+		return nil, nil, ErrFormat
+	}
+	if err := json.Unmarshal(l.Data, &h); err != nil || h.Format != Format {
+		return nil, nil, ErrFormat
+	}
+	if h.Version > Version {
+		return &h, nil, fmt.Errorf("%w: %d (supported up to %d)", ErrUnsupportedVersion, h.Version, Version)
+	}
+	if h.SchemaVersion > schemaVersion {
+		return &h, nil, fmt.Errorf("%w: backup is at %d but database is at %d", ErrSchemaTooNew, h.SchemaVersion, schemaVersion)
+	}
+
+	found, err := repo.HasBackupData(ctx, db)
+	if err != nil {
+		return &h, nil, err
+	}
+	if found {
+		return &h, nil, ErrNotEmpty
+	}
+
+	restorers := make(map[string]func(ctx context.Context, db store.Execer, repo Repository, data []byte) error, len(tables))
+	for _, t := range tables {
+		restorers[t.name] = t.restore
+	}
+	sum := Summary{}
+	for {
+		l = line{}
+		if err := dec.Decode(&l); errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return &h, sum, ErrTruncated
+		} else if err != nil {
+			return &h, sum, fmt.Errorf("failed to read record %d: %w", sum.total()+1, err)
+		}
+		if l.Type == endType {
+			break
+		}
+		restore, ok := restorers[l.Type]
+		if !ok {
+			return &h, sum, fmt.Errorf("unknown record type %q", l.Type)
+		}
+		if err := restore(ctx, db, repo, l.Data); err != nil {
+			return &h, sum, fmt.Errorf("failed to restore %s #%d: %w", l.Type, sum[l.Type]+1, err)
+		}
+		sum[l.Type]++
+	}
+
+	var want Summary
+	if err := json.Unmarshal(l.Data, &want); err != nil {
+		return &h, sum, fmt.Errorf("%w: %v", ErrTruncated, err)
+	}
+	for _, t := range tables {
+		if sum[t.name] != want[t.name] {
+			return &h, sum, fmt.Errorf("%w: %s has %d records but want %d", ErrTruncated, t.name, sum[t.name], want[t.name])
+		}
+	}
+	if dec.More() {
+		return &h, sum, fmt.Errorf("%w: unexpected data after the end", ErrFormat)
+	}
+	return &h, sum, nil
+}
+
+func (s Summary) total() int {
+	var n int
+	for _, c := range s {
+		n += c
+	}
+	return n
+}
+
+// テーブルの名前の一覧 (表示用)
+func Tables() []string {
+	names := make([]string, len(tables))
+	for i, t := range tables {
+		names[i] = t.name
+	}
+	return names
+}
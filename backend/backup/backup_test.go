@@ -0,0 +1,284 @@
+package backup
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/iinuma0710/react-go-blog/backend/entity"
+	"github.com/iinuma0710/react-go-blog/backend/media"
+	"github.com/iinuma0710/react-go-blog/backend/store"
+)
+
+var now = time.Date(2024, 8, 1, 9, 0, 0, 0, time.UTC)
+
+// バックアップの対象のすべてのテーブルのデータ
+type data struct {
+	Users    []*entity.User
+	Tags     []*entity.Tag
+	Articles entity.Articles
+	ATs      []entity.ArticleTag
+	Comments []*entity.Comment
+	Media    []*entity.Media
+	Sources  []*entity.ArticleSource
+}
+
+// d を読み出し、復元したレコードを d に追加するリポジトリ
+func repo(d *data) *RepositoryMock {
+	return &RepositoryMock{
+		ListUsersFunc: func(ctx context.Context, db store.Queryer) ([]*entity.User, error) { return d.Users, nil },
+		ListTagsFunc:  func(ctx context.Context, db store.Queryer) ([]*entity.Tag, error) { return d.Tags, nil },
+		ListArticlesFunc: func(ctx context.Context, db store.Queryer) (entity.Articles, error) {
+			return d.Articles, nil
+		},
+		ListArticleTagIDsFunc: func(ctx context.Context, db store.Queryer) ([]entity.ArticleTag, error) { return d.ATs, nil },
+		ListCommentsFunc:      func(ctx context.Context, db store.Queryer) ([]*entity.Comment, error) { return d.Comments, nil },
+		ListMediaFunc:         func(ctx context.Context, db store.Queryer) ([]*entity.Media, error) { return d.Media, nil },
+		ListArticleSourcesFunc: func(ctx context.Context, db store.Queryer) ([]*entity.ArticleSource, error) {
+			return d.Sources, nil
+		},
+		HasBackupDataFunc: func(ctx context.Context, db store.Queryer) (bool, error) { return len(d.Users) > 0, nil },
+		RestoreUserFunc: func(ctx context.Context, db store.Execer, u *entity.User) error {
+			d.Users = append(d.Users, u)
+			return nil
+		},
+		RestoreTagFunc: func(ctx context.Context, db store.Execer, t *entity.Tag) error {
+			d.Tags = append(d.Tags, t)
+			return nil
+		},
+		RestoreArticleFunc: func(ctx context.Context, db store.Execer, a *entity.Article) error {
+			d.Articles = append(d.Articles, a)
+			return nil
+		},
+		AddArticleTagFunc: func(ctx context.Context, db store.Execer, articleID entity.ArticleID, tagID entity.TagID) error {
+			d.ATs = append(d.ATs, entity.ArticleTag{ArticleID: articleID, TagID: tagID})
+			return nil
+		},
+		RestoreCommentFunc: func(ctx context.Context, db store.Execer, c *entity.Comment) error {
+			d.Comments = append(d.Comments, c)
+			return nil
+		},
+		RestoreMediaFunc: func(ctx context.Context, db store.Execer, m *entity.Media) error {
+			d.Media = append(d.Media, m)
+			return nil
+		},
+		RestoreArticleSourceFunc: func(ctx context.Context, db store.Execer, s *entity.ArticleSource) error {
+			d.Sources = append(d.Sources, s)
+			return nil
+		},
+	}
+}
+
+func fixture() *data {
+	return &data{
+		Users: []*entity.User{
+			{ID: 1, Name: "admin", Email: "admin@example.com", Password: "$2a$10$hash", Role: entity.RoleAdmin, CreatedAt: now, UpdatedAt: now},
+		},
+		Tags: []*entity.Tag{{ID: 3, Name: "Go", CreatedAt: now}},
+		Articles: entity.Articles{
+			{ID: 2, Title: "記事", Content: "本文\n", Slug: "post", Status: entity.ArticlePublished, Version: 4, AuthorID: 1, CreatedAt: now, UpdatedAt: now.Add(time.Hour)},
+			{ID: 5, Title: "下書き", Status: entity.ArticleDraft, Version: 1, CreatedAt: now, UpdatedAt: now},
+		},
+		ATs: []entity.ArticleTag{{ArticleID: 2, TagID: 3}},
+		Comments: []*entity.Comment{
+			{ID: 1, ArticleID: 2, UserID: 1, Body: "コメント", CreatedAt: now},
+			{ID: 2, ArticleID: 2, AuthorName: "読者", Body: "ゲスト", CreatedAt: now},
+		},
+		Media:   []*entity.Media{{Key: "0123456789abcdef/top.png", ContentType: "image/png", Size: 4, SHA256: sha("\x89PNG"), CreatedAt: now}},
+		Sources: []*entity.ArticleSource{{Source: "memo/post.md", ArticleID: 2, Checksum: "abc", CreatedAt: now, UpdatedAt: now}},
+	}
+}
+
+func sha(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestDumpRestore(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	src := fixture()
+	var buf bytes.Buffer
+	sum, err := Dump(ctx, nil, repo(src), &buf, 5, now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	wantSum := Summary{"user": 1, "tag": 1, "article": 2, "article_tag": 1, "comment": 2, "media": 1, "article_source": 1}
+	if d := cmp.Diff(wantSum, sum); d != "" {
+		t.Errorf("summary differs: (-want +got)\n%s", d)
+	}
+
+	dst := &data{}
+	h, sum, err := Restore(ctx, nil, repo(dst), bytes.NewReader(buf.Bytes()), 5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if h.SchemaVersion != 5 || h.Version != Version || !h.CreatedAt.Equal(now) {
+		t.Errorf("unexpected header: %+v", h)
+	}
+	if d := cmp.Diff(wantSum, sum); d != "" {
+		t.Errorf("summary differs: (-want +got)\n%s", d)
+	}
+	// パスワードハッシュを含めて、識別子と日時もそのまま復元する
+	if d := cmp.Diff(src, dst); d != "" {
+		t.Errorf("restored data differs: (-want +got)\n%s", d)
+	}
+}
+
+func TestRestore_Errors(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	archive := func(t *testing.T, schema int64) []byte {
+		t.Helper()
+		var buf bytes.Buffer
+		if _, err := Dump(ctx, nil, repo(fixture()), &buf, schema, now); err != nil {
+			t.Fatal(err)
+		}
+		return buf.Bytes()
+	}
+	// アーカイブを展開して f で書き換え、圧縮し直す
+	rewrite := func(t *testing.T, b []byte, f func(s string) string) []byte {
+		t.Helper()
+		zr, err := gzip.NewReader(bytes.NewReader(b))
+		if err != nil {
+			t.Fatal(err)
+		}
+		raw, err := io.ReadAll(zr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		zw.Write([]byte(f(string(raw))))
+		zw.Close()
+		return buf.Bytes()
+	}
+
+	tests := map[string]struct {
+		archive func(t *testing.T) []byte
+		target  *data
+		wantErr error
+	}{
+		"notGzip": {
+			archive: func(t *testing.T) []byte { return []byte("-- mysqldump\n") },
+			wantErr: ErrFormat,
+		},
+		"otherFormat": {
+			archive: func(t *testing.T) []byte {
+				return rewrite(t, archive(t, 5), func(s string) string { return strings.Replace(s, Format, "other", 1) })
+			},
+			wantErr: ErrFormat,
+		},
+		"newerVersion": {
+			archive: func(t *testing.T) []byte {
+				return rewrite(t, archive(t, 5), func(s string) string { return strings.Replace(s, `"version":1`, `"version":2`, 1) })
+			},
+			wantErr: ErrUnsupportedVersion,
+		},
+		"newerSchema": {
+			archive: func(t *testing.T) []byte { return archive(t, 6) },
+			wantErr: ErrSchemaTooNew,
+		},
+		"notEmpty": {
+			archive: func(t *testing.T) []byte { return archive(t, 5) },
+			target:  fixture(),
+			wantErr: ErrNotEmpty,
+		},
+		"truncated": {
+			archive: func(t *testing.T) []byte {
+				return rewrite(t, archive(t, 5), func(s string) string { return s[:strings.Index(s, `{"type":"end"`)] })
+			},
+			wantErr: ErrTruncated,
+		},
+		"missingRecord": {
+			archive: func(t *testing.T) []byte {
+				return rewrite(t, archive(t, 5), func(s string) string {
+					i := strings.Index(s, `{"type":"comment"`)
+					return s[:i] + s[i+strings.Index(s[i:], "\n")+1:]
+				})
+			},
+			wantErr: ErrTruncated,
+		},
+		// 古いスキーマのアーカイブは新しいスキーマのデータベースへ復元できる
+		"olderSchema": {
+			archive: func(t *testing.T) []byte { return archive(t, 4) },
+		},
+	}
+
+	for n, tt := range tests {
+		tt := tt
+		t.Run(n, func(t *testing.T) {
+			t.Parallel()
+			target := tt.target
+			if target == nil {
+				target = &data{}
+			}
+			_, _, err := Restore(ctx, nil, repo(target), bytes.NewReader(tt.archive(t)), 5)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("want error %v, but got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+// メモリ上のメディアストレージ
+type memMedia map[string]string
+
+func (m memMedia) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	s, ok := m[key]
+	if !ok {
+		return nil, media.ErrNotFound
+	}
+	return io.NopCloser(strings.NewReader(s)), nil
+}
+
+func (m memMedia) Put(ctx context.Context, key string, r io.Reader) error {
+	b, err := io.ReadAll(r)
+	m[key] = string(b)
+	return err
+}
+
+func TestDumpRestoreBlobs(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	ms := append(fixture().Media, &entity.Media{Key: "fedcba9876543210/gone.png", SHA256: sha("")})
+	src := memMedia{"0123456789abcdef/top.png": "\x89PNG", "unused.png": "x"}
+
+	var buf bytes.Buffer
+	rep, err := DumpBlobs(ctx, &buf, src, ms)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rep.Files != 1 || rep.Bytes != 4 || len(rep.Missing) != 1 {
+		t.Errorf("unexpected report: %+v", rep)
+	}
+
+	dst := memMedia{}
+	rep, err = RestoreBlobs(ctx, bytes.NewReader(buf.Bytes()), dst, ms)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if d := cmp.Diff(memMedia{"0123456789abcdef/top.png": "\x89PNG"}, dst); d != "" {
+		t.Errorf("restored media differs: (-want +got)\n%s", d)
+	}
+	if d := cmp.Diff([]string{"fedcba9876543210/gone.png"}, rep.Missing); d != "" {
+		t.Errorf("missing media differs: (-want +got)\n%s", d)
+	}
+
+	// 内容が書き換えられたファイルは復元しない
+	ms[0].SHA256 = sha("other")
+	if _, err := RestoreBlobs(ctx, bytes.NewReader(buf.Bytes()), memMedia{}, ms); err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Errorf("want checksum mismatch, but got %v", err)
+	}
+}
@@ -0,0 +1,109 @@
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/iinuma0710/react-go-blog/backend/entity"
+	"github.com/iinuma0710/react-go-blog/backend/media"
+)
+
+type MediaOpener interface {
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+}
+
+type MediaPutter interface {
+	Put(ctx context.Context, key string, r io.Reader) error
+}
+
+// メディアのファイルの書き出しと復元の結果
+type BlobReport struct {
+	Files int
+	Bytes int64
+	// メディアストレージまたはバンドルに存在しないファイルのキー
+	Missing []string
+}
+
+// メタデータのあるメディアのファイルを tar にまとめて w に書き出す
+// 画像などは圧縮済みのことが多いので、tar は圧縮しない
+func DumpBlobs(ctx context.Context, w io.Writer, src MediaOpener, ms []*entity.Media) (*BlobReport, error) {
+	tw := tar.NewWriter(w)
+	rep := &BlobReport{}
+	for _, m := range ms {
+		f, err := src.Open(ctx, m.Key)
+		if errors.Is(err, media.ErrNotFound) {
+			rep.Missing = append(rep.Missing, m.Key)
+			continue
+		} else if err != nil {
+			return rep, err
+		}
+		// サイズはヘッダに先に書く必要があるので、メタデータではなく実際の内容から求める
+		b, err := io.ReadAll(f)
+		f.Close()
+		if err != nil {
+			return rep, fmt.Errorf("failed to read %s: %w", m.Key, err)
+		}
+		hdr := &tar.Header{Name: m.Key, Mode: 0o644, Size: int64(len(b)), ModTime: m.CreatedAt}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return rep, err
+		}
+		if _, err := tw.Write(b); err != nil {
+			return rep, err
+		}
+		rep.Files++
+		rep.Bytes += hdr.Size
+	}
+	return rep, tw.Close()
+}
+
+// tar にまとめたファイルをメディアストレージへ書き込む
+// 復元したメタデータにないファイルとハッシュ値が一致しないファイルはエラーにする
+func RestoreBlobs(ctx context.Context, r io.Reader, dst MediaPutter, ms []*entity.Media) (*BlobReport, error) {
+	want := make(map[string]*entity.Media, len(ms))
+	for _, m := range ms {
+		want[m.Key] = m
+	}
+
+	tr := tar.NewReader(r)
+	rep := &BlobReport{}
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return rep, err
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		m, ok := want[hdr.Name]
+		if !ok {
+			return rep, fmt.Errorf("unknown media file %q", hdr.Name)
+		}
+		b, err := io.ReadAll(tr)
+		if err != nil {
+			return rep, err
+		}
+		if sum := sha256.Sum256(b); hex.EncodeToString(sum[:]) != m.SHA256 {
+			return rep, fmt.Errorf("checksum mismatch for %q", hdr.Name)
+		}
+		if err := dst.Put(ctx, m.Key, bytes.NewReader(b)); err != nil {
+			return rep, err
+		}
+		delete(want, m.Key)
+		rep.Files++
+		rep.Bytes += int64(len(b))
+	}
+	for _, m := range ms {
+		if _, ok := want[m.Key]; ok {
+			rep.Missing = append(rep.Missing, m.Key)
+		}
+	}
+	return rep, nil
+}
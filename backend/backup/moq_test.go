@@ -0,0 +1,831 @@
+// Code generated by moq; DO NOT EDIT.
+// github.com/matryer/moq
+
+package backup
+
+import (
+	"context"
+	"github.com/iinuma0710/react-go-blog/backend/entity"
+	"github.com/iinuma0710/react-go-blog/backend/store"
+	"sync"
+)
+
+// Ensure, that RepositoryMock does implement Repository.
+// If this is not the case, regenerate this file with moq.
+var _ Repository = &RepositoryMock{}
+
+// RepositoryMock is a mock implementation of Repository.
+//
+//	func TestSomethingThatUsesRepository(t *testing.T) {
+//
+//		// make and configure a mocked Repository
+//		mockedRepository := &RepositoryMock{
+//			AddArticleTagFunc: func(ctx context.Context, db store.Execer, articleID entity.ArticleID, tagID entity.TagID) error {
+//				panic("mock out the AddArticleTag method")
+//			},
+//			HasBackupDataFunc: func(ctx context.Context, db store.Queryer) (bool, error) {
+//				panic("mock out the HasBackupData method")
+//			},
+//			ListArticleSourcesFunc: func(ctx context.Context, db store.Queryer) ([]*entity.ArticleSource, error) {
+//				panic("mock out the ListArticleSources method")
+//			},
+//			ListArticleTagIDsFunc: func(ctx context.Context, db store.Queryer) ([]entity.ArticleTag, error) {
+//				panic("mock out the ListArticleTagIDs method")
+//			},
+//			ListArticlesFunc: func(ctx context.Context, db store.Queryer) (entity.Articles, error) {
+//				panic("mock out the ListArticles method")
+//			},
+//			ListCommentsFunc: func(ctx context.Context, db store.Queryer) ([]*entity.Comment, error) {
+//				panic("mock out the ListComments method")
+//			},
+//			ListMediaFunc: func(ctx context.Context, db store.Queryer) ([]*entity.Media, error) {
+//				panic("mock out the ListMedia method")
+//			},
+//			ListTagsFunc: func(ctx context.Context, db store.Queryer) ([]*entity.Tag, error) {
+//				panic("mock out the ListTags method")
+//			},
+//			ListUsersFunc: func(ctx context.Context, db store.Queryer) ([]*entity.User, error) {
+//				panic("mock out the ListUsers method")
+//			},
+//			RestoreArticleFunc: func(ctx context.Context, db store.Execer, a *entity.Article) error {
+//				panic("mock out the RestoreArticle method")
+//			},
+//			RestoreArticleSourceFunc: func(ctx context.Context, db store.Execer, s *entity.ArticleSource) error {
+//				panic("mock out the RestoreArticleSource method")
+//			},
+//			RestoreCommentFunc: func(ctx context.Context, db store.Execer, c *entity.Comment) error {
+//				panic("mock out the RestoreComment method")
+//			},
+//			RestoreMediaFunc: func(ctx context.Context, db store.Execer, m *entity.Media) error {
+//				panic("mock out the RestoreMedia method")
+//			},
+//			RestoreTagFunc: func(ctx context.Context, db store.Execer, t *entity.Tag) error {
+//				panic("mock out the RestoreTag method")
+//			},
+//			RestoreUserFunc: func(ctx context.Context, db store.Execer, u *entity.User) error {
+//				panic("mock out the RestoreUser method")
+//			},
+//		}
+//
+//		// use mockedRepository in code that requires Repository
+//		// and then make assertions.
+//
+//	}
+type RepositoryMock struct {
+	// AddArticleTagFunc mocks the AddArticleTag method.
+	AddArticleTagFunc func(ctx context.Context, db store.Execer, articleID entity.ArticleID, tagID entity.TagID) error
+
+	// HasBackupDataFunc mocks the HasBackupData method.
+	HasBackupDataFunc func(ctx context.Context, db store.Queryer) (bool, error)
+
+	// ListArticleSourcesFunc mocks the ListArticleSources method.
+	ListArticleSourcesFunc func(ctx context.Context, db store.Queryer) ([]*entity.ArticleSource, error)
+
+	// ListArticleTagIDsFunc mocks the ListArticleTagIDs method.
+	ListArticleTagIDsFunc func(ctx context.Context, db store.Queryer) ([]entity.ArticleTag, error)
+
+	// ListArticlesFunc mocks the ListArticles method.
+	ListArticlesFunc func(ctx context.Context, db store.Queryer) (entity.Articles, error)
+
+	// ListCommentsFunc mocks the ListComments method.
+	ListCommentsFunc func(ctx context.Context, db store.Queryer) ([]*entity.Comment, error)
+
+	// ListMediaFunc mocks the ListMedia method.
+	ListMediaFunc func(ctx context.Context, db store.Queryer) ([]*entity.Media, error)
+
+	// ListTagsFunc mocks the ListTags method.
+	ListTagsFunc func(ctx context.Context, db store.Queryer) ([]*entity.Tag, error)
+
+	// ListUsersFunc mocks the ListUsers method.
+	ListUsersFunc func(ctx context.Context, db store.Queryer) ([]*entity.User, error)
+
+	// RestoreArticleFunc mocks the RestoreArticle method.
+	RestoreArticleFunc func(ctx context.Context, db store.Execer, a *entity.Article) error
+
+	// RestoreArticleSourceFunc mocks the RestoreArticleSource method.
+	RestoreArticleSourceFunc func(ctx context.Context, db store.Execer, s *entity.ArticleSource) error
+
+	// RestoreCommentFunc mocks the RestoreComment method.
+	RestoreCommentFunc func(ctx context.Context, db store.Execer, c *entity.Comment) error
+
+	// RestoreMediaFunc mocks the RestoreMedia method.
+	RestoreMediaFunc func(ctx context.Context, db store.Execer, m *entity.Media) error
+
+	// RestoreTagFunc mocks the RestoreTag method.
+	RestoreTagFunc func(ctx context.Context, db store.Execer, t *entity.Tag) error
+
+	// RestoreUserFunc mocks the RestoreUser method.
+	RestoreUserFunc func(ctx context.Context, db store.Execer, u *entity.User) error
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// AddArticleTag holds details about calls to the AddArticleTag method.
+		AddArticleTag []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Db is the db argument value.
+			Db store.Execer
+			// ArticleID is the articleID argument value.
+			ArticleID entity.ArticleID
+			// TagID is the tagID argument value.
+			TagID entity.TagID
+		}
+		// HasBackupData holds details about calls to the HasBackupData method.
+		HasBackupData []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Db is the db argument value.
+			Db store.Queryer
+		}
+		// ListArticleSources holds details about calls to the ListArticleSources method.
+		ListArticleSources []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Db is the db argument value.
+			Db store.Queryer
+		}
+		// ListArticleTagIDs holds details about calls to the ListArticleTagIDs method.
+		ListArticleTagIDs []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Db is the db argument value.
+			Db store.Queryer
+		}
+		// ListArticles holds details about calls to the ListArticles method.
+		ListArticles []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Db is the db argument value.
+			Db store.Queryer
+		}
+		// ListComments holds details about calls to the ListComments method.
+		ListComments []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Db is the db argument value.
+			Db store.Queryer
+		}
+		// ListMedia holds details about calls to the ListMedia method.
+		ListMedia []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Db is the db argument value.
+			Db store.Queryer
+		}
+		// ListTags holds details about calls to the ListTags method.
+		ListTags []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Db is the db argument value.
+			Db store.Queryer
+		}
+		// ListUsers holds details about calls to the ListUsers method.
+		ListUsers []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Db is the db argument value.
+			Db store.Queryer
+		}
+		// RestoreArticle holds details about calls to the RestoreArticle method.
+		RestoreArticle []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Db is the db argument value.
+			Db store.Execer
+			// A is the a argument value.
+			A *entity.Article
+		}
+		// RestoreArticleSource holds details about calls to the RestoreArticleSource method.
+		RestoreArticleSource []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Db is the db argument value.
+			Db store.Execer
+			// S is the s argument value.
+			S *entity.ArticleSource
+		}
+		// RestoreComment holds details about calls to the RestoreComment method.
+		RestoreComment []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Db is the db argument value.
+			Db store.Execer
+			// C is the c argument value.
+			C *entity.Comment
+		}
+		// RestoreMedia holds details about calls to the RestoreMedia method.
+		RestoreMedia []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Db is the db argument value.
+			Db store.Execer
+			// M is the m argument value.
+			M *entity.Media
+		}
+		// RestoreTag holds details about calls to the RestoreTag method.
+		RestoreTag []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Db is the db argument value.
+			Db store.Execer
+			// T is the t argument value.
+			T *entity.Tag
+		}
+		// RestoreUser holds details about calls to the RestoreUser method.
+		RestoreUser []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Db is the db argument value.
+			Db store.Execer
+			// U is the u argument value.
+			U *entity.User
+		}
+	}
+	lockAddArticleTag        sync.RWMutex
+	lockHasBackupData        sync.RWMutex
+	lockListArticleSources   sync.RWMutex
+	lockListArticleTagIDs    sync.RWMutex
+	lockListArticles         sync.RWMutex
+	lockListComments         sync.RWMutex
+	lockListMedia            sync.RWMutex
+	lockListTags             sync.RWMutex
+	lockListUsers            sync.RWMutex
+	lockRestoreArticle       sync.RWMutex
+	lockRestoreArticleSource sync.RWMutex
+	lockRestoreComment       sync.RWMutex
+	lockRestoreMedia         sync.RWMutex
+	lockRestoreTag           sync.RWMutex
+	lockRestoreUser          sync.RWMutex
+}
+
+// AddArticleTag calls AddArticleTagFunc.
+func (mock *RepositoryMock) AddArticleTag(ctx context.Context, db store.Execer, articleID entity.ArticleID, tagID entity.TagID) error {
+	if mock.AddArticleTagFunc == nil {
+		panic("RepositoryMock.AddArticleTagFunc: method is nil but Repository.AddArticleTag was just called")
+	}
+	callInfo := struct {
+		Ctx       context.Context
+		Db        store.Execer
+		ArticleID entity.ArticleID
+		TagID     entity.TagID
+	}{
+		Ctx:       ctx,
+		Db:        db,
+		ArticleID: articleID,
+		TagID:     tagID,
+	}
+	mock.lockAddArticleTag.Lock()
+	mock.calls.AddArticleTag = append(mock.calls.AddArticleTag, callInfo)
+	mock.lockAddArticleTag.Unlock()
+	return mock.AddArticleTagFunc(ctx, db, articleID, tagID)
+}
+
+// AddArticleTagCalls gets all the calls that were made to AddArticleTag.
+// Check the length with:
+//
+//	len(mockedRepository.AddArticleTagCalls())
+func (mock *RepositoryMock) AddArticleTagCalls() []struct {
+	Ctx       context.Context
+	Db        store.Execer
+	ArticleID entity.ArticleID
+	TagID     entity.TagID
+} {
+	var calls []struct {
+		Ctx       context.Context
+		Db        store.Execer
+		ArticleID entity.ArticleID
+		TagID     entity.TagID
+	}
+	mock.lockAddArticleTag.RLock()
+	calls = mock.calls.AddArticleTag
+	mock.lockAddArticleTag.RUnlock()
+	return calls
+}
+
+// HasBackupData calls HasBackupDataFunc.
+func (mock *RepositoryMock) HasBackupData(ctx context.Context, db store.Queryer) (bool, error) {
+	if mock.HasBackupDataFunc == nil {
+		panic("RepositoryMock.HasBackupDataFunc: method is nil but Repository.HasBackupData was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Db  store.Queryer
+	}{
+		Ctx: ctx,
+		Db:  db,
+	}
+	mock.lockHasBackupData.Lock()
+	mock.calls.HasBackupData = append(mock.calls.HasBackupData, callInfo)
+	mock.lockHasBackupData.Unlock()
+	return mock.HasBackupDataFunc(ctx, db)
+}
+
+// HasBackupDataCalls gets all the calls that were made to HasBackupData.
+// Check the length with:
+//
+//	len(mockedRepository.HasBackupDataCalls())
+func (mock *RepositoryMock) HasBackupDataCalls() []struct {
+	Ctx context.Context
+	Db  store.Queryer
+} {
+	var calls []struct {
+		Ctx context.Context
+		Db  store.Queryer
+	}
+	mock.lockHasBackupData.RLock()
+	calls = mock.calls.HasBackupData
+	mock.lockHasBackupData.RUnlock()
+	return calls
+}
+
+// ListArticleSources calls ListArticleSourcesFunc.
+func (mock *RepositoryMock) ListArticleSources(ctx context.Context, db store.Queryer) ([]*entity.ArticleSource, error) {
+	if mock.ListArticleSourcesFunc == nil {
+		panic("RepositoryMock.ListArticleSourcesFunc: method is nil but Repository.ListArticleSources was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Db  store.Queryer
+	}{
+		Ctx: ctx,
+		Db:  db,
+	}
+	mock.lockListArticleSources.Lock()
+	mock.calls.ListArticleSources = append(mock.calls.ListArticleSources, callInfo)
+	mock.lockListArticleSources.Unlock()
+	return mock.ListArticleSourcesFunc(ctx, db)
+}
+
+// ListArticleSourcesCalls gets all the calls that were made to ListArticleSources.
+// Check the length with:
+//
+//	len(mockedRepository.ListArticleSourcesCalls())
+func (mock *RepositoryMock) ListArticleSourcesCalls() []struct {
+	Ctx context.Context
+	Db  store.Queryer
+} {
+	var calls []struct {
+		Ctx context.Context
+		Db  store.Queryer
+	}
+	mock.lockListArticleSources.RLock()
+	calls = mock.calls.ListArticleSources
+	mock.lockListArticleSources.RUnlock()
+	return calls
+}
+
+// ListArticleTagIDs calls ListArticleTagIDsFunc.
+func (mock *RepositoryMock) ListArticleTagIDs(ctx context.Context, db store.Queryer) ([]entity.ArticleTag, error) {
+	if mock.ListArticleTagIDsFunc == nil {
+		panic("RepositoryMock.ListArticleTagIDsFunc: method is nil but Repository.ListArticleTagIDs was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Db  store.Queryer
+	}{
+		Ctx: ctx,
+		Db:  db,
+	}
+	mock.lockListArticleTagIDs.Lock()
+	mock.calls.ListArticleTagIDs = append(mock.calls.ListArticleTagIDs, callInfo)
+	mock.lockListArticleTagIDs.Unlock()
+	return mock.ListArticleTagIDsFunc(ctx, db)
+}
+
+// ListArticleTagIDsCalls gets all the calls that were made to ListArticleTagIDs.
+// Check the length with:
+//
+//	len(mockedRepository.ListArticleTagIDsCalls())
+func (mock *RepositoryMock) ListArticleTagIDsCalls() []struct {
+	Ctx context.Context
+	Db  store.Queryer
+} {
+	var calls []struct {
+		Ctx context.Context
+		Db  store.Queryer
+	}
+	mock.lockListArticleTagIDs.RLock()
+	calls = mock.calls.ListArticleTagIDs
+	mock.lockListArticleTagIDs.RUnlock()
+	return calls
+}
+
+// ListArticles calls ListArticlesFunc.
+func (mock *RepositoryMock) ListArticles(ctx context.Context, db store.Queryer) (entity.Articles, error) {
+	if mock.ListArticlesFunc == nil {
+		panic("RepositoryMock.ListArticlesFunc: method is nil but Repository.ListArticles was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Db  store.Queryer
+	}{
+		Ctx: ctx,
+		Db:  db,
+	}
+	mock.lockListArticles.Lock()
+	mock.calls.ListArticles = append(mock.calls.ListArticles, callInfo)
+	mock.lockListArticles.Unlock()
+	return mock.ListArticlesFunc(ctx, db)
+}
+
+// ListArticlesCalls gets all the calls that were made to ListArticles.
+// Check the length with:
+//
+//	len(mockedRepository.ListArticlesCalls())
+func (mock *RepositoryMock) ListArticlesCalls() []struct {
+	Ctx context.Context
+	Db  store.Queryer
+} {
+	var calls []struct {
+		Ctx context.Context
+		Db  store.Queryer
+	}
+	mock.lockListArticles.RLock()
+	calls = mock.calls.ListArticles
+	mock.lockListArticles.RUnlock()
+	return calls
+}
+
+// ListComments calls ListCommentsFunc.
+func (mock *RepositoryMock) ListComments(ctx context.Context, db store.Queryer) ([]*entity.Comment, error) {
+	if mock.ListCommentsFunc == nil {
+		panic("RepositoryMock.ListCommentsFunc: method is nil but Repository.ListComments was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Db  store.Queryer
+	}{
+		Ctx: ctx,
+		Db:  db,
+	}
+	mock.lockListComments.Lock()
+	mock.calls.ListComments = append(mock.calls.ListComments, callInfo)
+	mock.lockListComments.Unlock()
+	return mock.ListCommentsFunc(ctx, db)
+}
+
+// ListCommentsCalls gets all the calls that were made to ListComments.
+// Check the length with:
+//
+//	len(mockedRepository.ListCommentsCalls())
+func (mock *RepositoryMock) ListCommentsCalls() []struct {
+	Ctx context.Context
+	Db  store.Queryer
+} {
+	var calls []struct {
+		Ctx context.Context
+		Db  store.Queryer
+	}
+	mock.lockListComments.RLock()
+	calls = mock.calls.ListComments
+	mock.lockListComments.RUnlock()
+	return calls
+}
+
+// ListMedia calls ListMediaFunc.
+func (mock *RepositoryMock) ListMedia(ctx context.Context, db store.Queryer) ([]*entity.Media, error) {
+	if mock.ListMediaFunc == nil {
+		panic("RepositoryMock.ListMediaFunc: method is nil but Repository.ListMedia was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Db  store.Queryer
+	}{
+		Ctx: ctx,
+		Db:  db,
+	}
+	mock.lockListMedia.Lock()
+	mock.calls.ListMedia = append(mock.calls.ListMedia, callInfo)
+	mock.lockListMedia.Unlock()
+	return mock.ListMediaFunc(ctx, db)
+}
+
+// ListMediaCalls gets all the calls that were made to ListMedia.
+// Check the length with:
+//
+//	len(mockedRepository.ListMediaCalls())
+func (mock *RepositoryMock) ListMediaCalls() []struct {
+	Ctx context.Context
+	Db  store.Queryer
+} {
+	var calls []struct {
+		Ctx context.Context
+		Db  store.Queryer
+	}
+	mock.lockListMedia.RLock()
+	calls = mock.calls.ListMedia
+	mock.lockListMedia.RUnlock()
+	return calls
+}
+
+// ListTags calls ListTagsFunc.
+func (mock *RepositoryMock) ListTags(ctx context.Context, db store.Queryer) ([]*entity.Tag, error) {
+	if mock.ListTagsFunc == nil {
+		panic("RepositoryMock.ListTagsFunc: method is nil but Repository.ListTags was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Db  store.Queryer
+	}{
+		Ctx: ctx,
+		Db:  db,
+	}
+	mock.lockListTags.Lock()
+	mock.calls.ListTags = append(mock.calls.ListTags, callInfo)
+	mock.lockListTags.Unlock()
+	return mock.ListTagsFunc(ctx, db)
+}
+
+// ListTagsCalls gets all the calls that were made to ListTags.
+// Check the length with:
+//
+//	len(mockedRepository.ListTagsCalls())
+func (mock *RepositoryMock) ListTagsCalls() []struct {
+	Ctx context.Context
+	Db  store.Queryer
+} {
+	var calls []struct {
+		Ctx context.Context
+		Db  store.Queryer
+	}
+	mock.lockListTags.RLock()
+	calls = mock.calls.ListTags
+	mock.lockListTags.RUnlock()
+	return calls
+}
+
+// ListUsers calls ListUsersFunc.
+func (mock *RepositoryMock) ListUsers(ctx context.Context, db store.Queryer) ([]*entity.User, error) {
+	if mock.ListUsersFunc == nil {
+		panic("RepositoryMock.ListUsersFunc: method is nil but Repository.ListUsers was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Db  store.Queryer
+	}{
+		Ctx: ctx,
+		Db:  db,
+	}
+	mock.lockListUsers.Lock()
+	mock.calls.ListUsers = append(mock.calls.ListUsers, callInfo)
+	mock.lockListUsers.Unlock()
+	return mock.ListUsersFunc(ctx, db)
+}
+
+// ListUsersCalls gets all the calls that were made to ListUsers.
+// Check the length with:
+//
+//	len(mockedRepository.ListUsersCalls())
+func (mock *RepositoryMock) ListUsersCalls() []struct {
+	Ctx context.Context
+	Db  store.Queryer
+} {
+	var calls []struct {
+		Ctx context.Context
+		Db  store.Queryer
+	}
+	mock.lockListUsers.RLock()
+	calls = mock.calls.ListUsers
+	mock.lockListUsers.RUnlock()
+	return calls
+}
+
+// RestoreArticle calls RestoreArticleFunc.
+func (mock *RepositoryMock) RestoreArticle(ctx context.Context, db store.Execer, a *entity.Article) error {
+	if mock.RestoreArticleFunc == nil {
+		panic("RepositoryMock.RestoreArticleFunc: method is nil but Repository.RestoreArticle was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Db  store.Execer
+		A   *entity.Article
+	}{
+		Ctx: ctx,
+		Db:  db,
+		A:   a,
+	}
+	mock.lockRestoreArticle.Lock()
+	mock.calls.RestoreArticle = append(mock.calls.RestoreArticle, callInfo)
+	mock.lockRestoreArticle.Unlock()
+	return mock.RestoreArticleFunc(ctx, db, a)
+}
+
+// RestoreArticleCalls gets all the calls that were made to RestoreArticle.
+// Check the length with:
+//
+//	len(mockedRepository.RestoreArticleCalls())
+func (mock *RepositoryMock) RestoreArticleCalls() []struct {
+	Ctx context.Context
+	Db  store.Execer
+	A   *entity.Article
+} {
+	var calls []struct {
+		Ctx context.Context
+		Db  store.Execer
+		A   *entity.Article
+	}
+	mock.lockRestoreArticle.RLock()
+	calls = mock.calls.RestoreArticle
+	mock.lockRestoreArticle.RUnlock()
+	return calls
+}
+
+// RestoreArticleSource calls RestoreArticleSourceFunc.
+func (mock *RepositoryMock) RestoreArticleSource(ctx context.Context, db store.Execer, s *entity.ArticleSource) error {
+	if mock.RestoreArticleSourceFunc == nil {
+		panic("RepositoryMock.RestoreArticleSourceFunc: method is nil but Repository.RestoreArticleSource was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Db  store.Execer
+		S   *entity.ArticleSource
+	}{
+		Ctx: ctx,
+		Db:  db,
+		S:   s,
+	}
+	mock.lockRestoreArticleSource.Lock()
+	mock.calls.RestoreArticleSource = append(mock.calls.RestoreArticleSource, callInfo)
+	mock.lockRestoreArticleSource.Unlock()
+	return mock.RestoreArticleSourceFunc(ctx, db, s)
+}
+
+// RestoreArticleSourceCalls gets all the calls that were made to RestoreArticleSource.
+// Check the length with:
+//
+//	len(mockedRepository.RestoreArticleSourceCalls())
+func (mock *RepositoryMock) RestoreArticleSourceCalls() []struct {
+	Ctx context.Context
+	Db  store.Execer
+	S   *entity.ArticleSource
+} {
+	var calls []struct {
+		Ctx context.Context
+		Db  store.Execer
+		S   *entity.ArticleSource
+	}
+	mock.lockRestoreArticleSource.RLock()
+	calls = mock.calls.RestoreArticleSource
+	mock.lockRestoreArticleSource.RUnlock()
+	return calls
+}
+
+// RestoreComment calls RestoreCommentFunc.
+func (mock *RepositoryMock) RestoreComment(ctx context.Context, db store.Execer, c *entity.Comment) error {
+	if mock.RestoreCommentFunc == nil {
+		panic("RepositoryMock.RestoreCommentFunc: method is nil but Repository.RestoreComment was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Db  store.Execer
+		C   *entity.Comment
+	}{
+		Ctx: ctx,
+		Db:  db,
+		C:   c,
+	}
+	mock.lockRestoreComment.Lock()
+	mock.calls.RestoreComment = append(mock.calls.RestoreComment, callInfo)
+	mock.lockRestoreComment.Unlock()
+	return mock.RestoreCommentFunc(ctx, db, c)
+}
+
+// RestoreCommentCalls gets all the calls that were made to RestoreComment.
+// Check the length with:
+//
+//	len(mockedRepository.RestoreCommentCalls())
+func (mock *RepositoryMock) RestoreCommentCalls() []struct {
+	Ctx context.Context
+	Db  store.Execer
+	C   *entity.Comment
+} {
+	var calls []struct {
+		Ctx context.Context
+		Db  store.Execer
+		C   *entity.Comment
+	}
+	mock.lockRestoreComment.RLock()
+	calls = mock.calls.RestoreComment
+	mock.lockRestoreComment.RUnlock()
+	return calls
+}
+
+// RestoreMedia calls RestoreMediaFunc.
+func (mock *RepositoryMock) RestoreMedia(ctx context.Context, db store.Execer, m *entity.Media) error {
+	if mock.RestoreMediaFunc == nil {
+		panic("RepositoryMock.RestoreMediaFunc: method is nil but Repository.RestoreMedia was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Db  store.Execer
+		M   *entity.Media
+	}{
+		Ctx: ctx,
+		Db:  db,
+		M:   m,
+	}
+	mock.lockRestoreMedia.Lock()
+	mock.calls.RestoreMedia = append(mock.calls.RestoreMedia, callInfo)
+	mock.lockRestoreMedia.Unlock()
+	return mock.RestoreMediaFunc(ctx, db, m)
+}
+
+// RestoreMediaCalls gets all the calls that were made to RestoreMedia.
+// Check the length with:
+//
+//	len(mockedRepository.RestoreMediaCalls())
+func (mock *RepositoryMock) RestoreMediaCalls() []struct {
+	Ctx context.Context
+	Db  store.Execer
+	M   *entity.Media
+} {
+	var calls []struct {
+		Ctx context.Context
+		Db  store.Execer
+		M   *entity.Media
+	}
+	mock.lockRestoreMedia.RLock()
+	calls = mock.calls.RestoreMedia
+	mock.lockRestoreMedia.RUnlock()
+	return calls
+}
+
+// RestoreTag calls RestoreTagFunc.
+func (mock *RepositoryMock) RestoreTag(ctx context.Context, db store.Execer, t *entity.Tag) error {
+	if mock.RestoreTagFunc == nil {
+		panic("RepositoryMock.RestoreTagFunc: method is nil but Repository.RestoreTag was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Db  store.Execer
+		T   *entity.Tag
+	}{
+		Ctx: ctx,
+		Db:  db,
+		T:   t,
+	}
+	mock.lockRestoreTag.Lock()
+	mock.calls.RestoreTag = append(mock.calls.RestoreTag, callInfo)
+	mock.lockRestoreTag.Unlock()
+	return mock.RestoreTagFunc(ctx, db, t)
+}
+
+// RestoreTagCalls gets all the calls that were made to RestoreTag.
+// Check the length with:
+//
+//	len(mockedRepository.RestoreTagCalls())
+func (mock *RepositoryMock) RestoreTagCalls() []struct {
+	Ctx context.Context
+	Db  store.Execer
+	T   *entity.Tag
+} {
+	var calls []struct {
+		Ctx context.Context
+		Db  store.Execer
+		T   *entity.Tag
+	}
+	mock.lockRestoreTag.RLock()
+	calls = mock.calls.RestoreTag
+	mock.lockRestoreTag.RUnlock()
+	return calls
+}
+
+// RestoreUser calls RestoreUserFunc.
+func (mock *RepositoryMock) RestoreUser(ctx context.Context, db store.Execer, u *entity.User) error {
+	if mock.RestoreUserFunc == nil {
+		panic("RepositoryMock.RestoreUserFunc: method is nil but Repository.RestoreUser was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Db  store.Execer
+		U   *entity.User
+	}{
+		Ctx: ctx,
+		Db:  db,
+		U:   u,
+	}
+	mock.lockRestoreUser.Lock()
+	mock.calls.RestoreUser = append(mock.calls.RestoreUser, callInfo)
+	mock.lockRestoreUser.Unlock()
+	return mock.RestoreUserFunc(ctx, db, u)
+}
+
+// RestoreUserCalls gets all the calls that were made to RestoreUser.
+// Check the length with:
+//
+//	len(mockedRepository.RestoreUserCalls())
+func (mock *RepositoryMock) RestoreUserCalls() []struct {
+	Ctx context.Context
+	Db  store.Execer
+	U   *entity.User
+} {
+	var calls []struct {
+		Ctx context.Context
+		Db  store.Execer
+		U   *entity.User
+	}
+	mock.lockRestoreUser.RLock()
+	calls = mock.calls.RestoreUser
+	mock.lockRestoreUser.RUnlock()
+	return calls
+}
@@ -0,0 +1,70 @@
+package backup
+
+import (
+	"time"
+
+	"github.com/iinuma0710/react-go-blog/backend/entity"
+)
+
+// アーカイブのレコードの形式
+// API のレスポンスの形式とは独立させるため、エンティティと同じフィールドを持つ型を別に定義して変換する
+// エンティティのフィールドを変えると変換がコンパイルエラーになるので、ここも合わせて変更し、
+// 既存のアーカイブを読み込めなくなる場合は Version を上げる
+
+type user struct {
+	ID        entity.UserID `json:"id"`
+	Name      string        `json:"name"`
+	Email     string        `json:"email"`
+	Password  string        `json:"password"`
+	Role      entity.Role   `json:"role"`
+	CreatedAt time.Time     `json:"created_at"`
+	UpdatedAt time.Time     `json:"updated_at"`
+}
+
+type tag struct {
+	ID        entity.TagID `json:"id"`
+	Name      string       `json:"name"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+type article struct {
+	ID        entity.ArticleID     `json:"id"`
+	Title     string               `json:"title"`
+	Content   string               `json:"content"`
+	Slug      string               `json:"slug"`
+	Status    entity.ArticleStatus `json:"status"`
+	Version   int64                `json:"version"`
+	AuthorID  entity.UserID        `json:"author_id"`
+	CreatedAt time.Time            `json:"created_at"`
+	UpdatedAt time.Time            `json:"updated_at"`
+}
+
+type articleTag struct {
+	ArticleID entity.ArticleID `json:"article_id"`
+	TagID     entity.TagID     `json:"tag_id"`
+}
+
+type comment struct {
+	ID         entity.CommentID `json:"id"`
+	ArticleID  entity.ArticleID `json:"article_id"`
+	UserID     entity.UserID    `json:"user_id"`
+	AuthorName string           `json:"author_name"`
+	Body       string           `json:"body"`
+	CreatedAt  time.Time        `json:"created_at"`
+}
+
+type mediaFile struct {
+	Key         string    `json:"key"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	SHA256      string    `json:"sha256"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type articleSource struct {
+	Source    string           `json:"source"`
+	ArticleID entity.ArticleID `json:"article_id"`
+	Checksum  string           `json:"checksum"`
+	CreatedAt time.Time        `json:"created_at"`
+	UpdatedAt time.Time        `json:"updated_at"`
+}
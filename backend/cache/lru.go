@@ -0,0 +1,158 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/iinuma0710/react-go-blog/backend/clock"
+	"golang.org/x/sync/singleflight"
+)
+
+// キャッシュの利用状況
+type Stats struct {
+	Hits      uint64 `json:"hits"`
+	Misses    uint64 `json:"misses"`
+	Evictions uint64 `json:"evictions"`
+	Size      int    `json:"size"`
+}
+
+type entry[V any] struct {
+	key       string
+	value     V
+	expiresAt time.Time
+}
+
+// 有効期限付きの LRU キャッシュ
+// 同じキーに対する同時の読み込みは 1 回にまとめる
+type LRU[V any] struct {
+	size    int
+	ttl     time.Duration
+	clocker clock.Clocker
+
+	mu    sync.Mutex
+	ll    *list.List
+	items map[string]*list.Element
+	// 削除のたびに進め、読み込み中に削除されたデータをキャッシュしないために使う
+	gen   uint64
+	stats Stats
+
+	group singleflight.Group
+}
+
+// size 件を超えると最も使われていないものから削除し、ttl を過ぎたものは読み込み直す
+func NewLRU[V any](size int, ttl time.Duration, c clock.Clocker) *LRU[V] {
+	return &LRU[V]{
+		size:    size,
+		ttl:     ttl,
+		clocker: c,
+		ll:      list.New(),
+		items:   map[string]*list.Element{},
+	}
+}
+
+func (c *LRU[V]) Get(key string) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[key]; ok {
+		e := el.Value.(*entry[V])
+		if c.clocker.Now().Before(e.expiresAt) {
+			c.ll.MoveToFront(el)
+			c.stats.Hits++
+			return e.value, true
+		}
+		c.removeElement(el)
+	}
+	c.stats.Misses++
+	var zero V
+	return zero, false
+}
+
+func (c *LRU[V]) Add(key string, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.add(key, value)
+}
+
+func (c *LRU[V]) add(key string, value V) {
+	expiresAt := c.clocker.Now().Add(c.ttl)
+	if el, ok := c.items[key]; ok {
+		c.ll.MoveToFront(el)
+		e := el.Value.(*entry[V])
+		e.value, e.expiresAt = value, expiresAt
+		return
+	}
+
+	c.items[key] = c.ll.PushFront(&entry[V]{key: key, value: value, expiresAt: expiresAt})
+	for c.ll.Len() > c.size {
+		c.removeElement(c.ll.Back())
+		c.stats.Evictions++
+	}
+}
+
+// キャッシュになければ load で読み込んで保存する
+func (c *LRU[V]) GetOrLoad(ctx context.Context, key string, load func(ctx context.Context) (V, error)) (V, error) {
+	if v, ok := c.Get(key); ok {
+		return v, nil
+	}
+
+	c.mu.Lock()
+	gen := c.gen
+	c.mu.Unlock()
+
+	v, err, _ := c.group.Do(key, func() (any, error) {
+		// 読み込みは他の呼び出し元と共有するので、最初の呼び出し元のキャンセルは引き継がない
+		v, err := load(context.WithoutCancel(ctx))
+		if err != nil {
+			return v, err
+		}
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if c.gen == gen {
+			c.add(key, v)
+		}
+		return v, nil
+	})
+	if err != nil {
+		var zero V
+		return zero, err
+	}
+	return v.(V), nil
+}
+
+func (c *LRU[V]) Remove(keys ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.gen++
+	for _, k := range keys {
+		if el, ok := c.items[k]; ok {
+			c.removeElement(el)
+		}
+	}
+}
+
+func (c *LRU[V]) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.gen++
+	c.ll.Init()
+	c.items = map[string]*list.Element{}
+}
+
+func (c *LRU[V]) Stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s := c.stats
+	s.Size = c.ll.Len()
+	return s
+}
+
+func (c *LRU[V]) removeElement(el *list.Element) {
+	c.ll.Remove(el)
+	delete(c.items, el.Value.(*entry[V]).key)
+}
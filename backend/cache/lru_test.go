@@ -0,0 +1,127 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/iinuma0710/react-go-blog/backend/clock"
+)
+
+// 任意の時刻を返せるテスト用の Clocker
+type stubClocker struct{ now time.Time }
+
+func (s *stubClocker) Now() time.Time { return s.now }
+
+func TestLRU_Eviction(t *testing.T) {
+	t.Parallel()
+
+	sut := NewLRU[int](2, time.Minute, clock.FixedClocker{})
+	sut.Add("a", 1)
+	sut.Add("b", 2)
+	// a を使うことで b が最も使われていない状態にする
+	if _, ok := sut.Get("a"); !ok {
+		t.Fatal("want a to be cached")
+	}
+	sut.Add("c", 3)
+
+	if _, ok := sut.Get("b"); ok {
+		t.Error("want b to be evicted")
+	}
+	if v, ok := sut.Get("c"); !ok || v != 3 {
+		t.Errorf("want c = 3, but got %d, %v", v, ok)
+	}
+
+	want := Stats{Hits: 2, Misses: 1, Evictions: 1, Size: 2}
+	if d := cmp.Diff(sut.Stats(), want); d != "" {
+		t.Errorf("differs: (-got +want)\n%s", d)
+	}
+}
+
+func TestLRU_TTL(t *testing.T) {
+	t.Parallel()
+
+	c := &stubClocker{now: clock.FixedClocker{}.Now()}
+	sut := NewLRU[int](10, time.Minute, c)
+	sut.Add("a", 1)
+
+	c.now = c.now.Add(59 * time.Second)
+	if _, ok := sut.Get("a"); !ok {
+		t.Error("want a to be cached")
+	}
+	c.now = c.now.Add(time.Second)
+	if _, ok := sut.Get("a"); ok {
+		t.Error("want a to be expired")
+	}
+}
+
+func TestLRU_GetOrLoad(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	sut := NewLRU[int](10, time.Minute, clock.FixedClocker{})
+
+	// 同時に読み込みを要求しても load は 1 回しか呼ばれない
+	var calls atomic.Int32
+	release := make(chan struct{})
+	load := func(ctx context.Context) (int, error) {
+		calls.Add(1)
+		<-release
+		return 42, nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if v, err := sut.GetOrLoad(ctx, "a", load); err != nil || v != 42 {
+				t.Errorf("want 42, but got %d, %v", v, err)
+			}
+		}()
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if got := calls.Load(); got != 1 {
+		t.Errorf("want load to be called once, but got %d", got)
+	}
+	if v, ok := sut.Get("a"); !ok || v != 42 {
+		t.Errorf("want a = 42 to be cached, but got %d, %v", v, ok)
+	}
+
+	// 読み込みに失敗した結果はキャッシュしない
+	wantErr := errors.New("load error")
+	if _, err := sut.GetOrLoad(ctx, "b", func(ctx context.Context) (int, error) {
+		return 0, wantErr
+	}); !errors.Is(err, wantErr) {
+		t.Errorf("want %v, but got %v", wantErr, err)
+	}
+	if _, ok := sut.Get("b"); ok {
+		t.Error("want b not to be cached")
+	}
+}
+
+func TestLRU_RemoveDuringLoad(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	sut := NewLRU[int](10, time.Minute, clock.FixedClocker{})
+
+	// 読み込み中に削除された場合、古いかもしれない値はキャッシュしない
+	v, err := sut.GetOrLoad(ctx, "a", func(ctx context.Context) (int, error) {
+		sut.Remove("a")
+		return 1, nil
+	})
+	if err != nil || v != 1 {
+		t.Fatalf("want 1, but got %d, %v", v, err)
+	}
+	if _, ok := sut.Get("a"); ok {
+		t.Error("want a not to be cached")
+	}
+}
@@ -0,0 +1,215 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"text/tabwriter"
+
+	"github.com/iinuma0710/react-go-blog/backend/config"
+	"github.com/iinuma0710/react-go-blog/backend/logger"
+	"github.com/iinuma0710/react-go-blog/backend/migration"
+	"github.com/iinuma0710/react-go-blog/backend/store"
+	"github.com/jmoiron/sqlx"
+)
+
+// 引数の誤り (使い方を表示済み)
+var errUsage = errors.New("invalid usage")
+
+// サブコマンドの定義
+type command struct {
+	name    string
+	args    string
+	summary string
+	// fs にフラグを登録し、フラグの解析後に呼び出す関数を返す
+	setup func(fs *flag.FlagSet) func(ctx context.Context, e *cmdEnv, args []string) error
+}
+
+var commands = []command{
+	{
+		name:    "serve",
+		summary: "HTTP サーバを起動する (サブコマンドを省略した場合の既定)",
+		setup: func(fs *flag.FlagSet) func(ctx context.Context, e *cmdEnv, args []string) error {
+			return func(ctx context.Context, e *cmdEnv, args []string) error { return serve(ctx, e) }
+		},
+	},
+	{
+		name:    "migrate",
+		args:    "up|down|status|to <version>",
+		summary: "スキーマのマイグレーションを実行する",
+		setup: func(fs *flag.FlagSet) func(ctx context.Context, e *cmdEnv, args []string) error {
+			return runMigrate
+		},
+	},
+	{
+		name:    "create-admin",
+		summary: "管理者ユーザを作成する",
+		setup:   setupCreateAdmin,
+	},
+	{
+		name:    "seed",
+		summary: "開発用のデータを生成して投入する",
+		setup:   setupSeed,
+	},
+	{
+		name:    "export",
+		summary: "すべての記事をフロントマター付きの Markdown として書き出す",
+		setup:   setupExport,
+	},
+	{
+		name:    "import",
+		args:    "<dir>",
+		summary: "ディレクトリの Markdown のファイルを記事として取り込む (同じファイルは重複して取り込まない)",
+		setup:   setupImport,
+	},
+	{
+		name:    "import-wordpress",
+		args:    "<export.xml>",
+		summary: "WordPress のエクスポートファイルの投稿・投稿者・コメントを取り込む",
+		setup:   setupImportWordPress,
+	},
+	{
+		name:    "backup",
+		summary: "すべてのデータをデータベースに依存しない形式でバックアップする",
+		setup:   setupBackup,
+	},
+	{
+		name:    "restore",
+		args:    "<archive>",
+		summary: "バックアップを空のデータベースに復元する",
+		setup:   setupRestore,
+	},
+	{
+		name:    "config",
+		args:    "print",
+		summary: "実際に使われる設定を、秘匿情報を伏せて表示する",
+		setup: func(fs *flag.FlagSet) func(ctx context.Context, e *cmdEnv, args []string) error {
+			return func(ctx context.Context, e *cmdEnv, args []string) error {
+				if len(args) != 1 || args[0] != "print" {
+					return errUsage
+				}
+				return e.cfg.Print(e.stdout)
+			}
+		},
+	},
+}
+
+// サブコマンドで共有する設定と入出力
+type cmdEnv struct {
+	cfg *config.Config
+	// 設定を読み込み直す (SIGHUP での再読み込みで使う)
+	load   func() (*config.Config, error)
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
+// 設定に応じたロガーを作成し、デフォルトのロガーとコンテキストに設定する
+func (e *cmdEnv) setupLogger(ctx context.Context, w io.Writer) (context.Context, *slog.LevelVar, error) {
+	lg, level, err := logger.New(e.cfg, w)
+	if err != nil {
+		return nil, nil, err
+	}
+	slog.SetDefault(lg)
+	return logger.WithContext(ctx, lg), level, nil
+}
+
+// 管理用のコマンドからデータベースに接続する
+// スキーマが古い場合はマイグレーションを促す
+func (e *cmdEnv) openDB(ctx context.Context) (*sqlx.DB, func(), error) {
+	db, cleanup, err := store.New(ctx, e.cfg, 10)
+	if err != nil {
+		return nil, cleanup, err
+	}
+	mg, err := migration.New(db.Primary.DB, db.DriverName())
+	if err != nil {
+		return nil, cleanup, err
+	}
+	if err := mg.Check(ctx); err != nil {
+		return nil, cleanup, fmt.Errorf("%w (run `migrate up` first)", err)
+	}
+	// 管理用のコマンドは書き込んだ内容をすぐに読み出すので、プライマリだけを使う
+	return db.Primary, cleanup, nil
+}
+
+// app [-config path] <command> [flags] [args] の形式で引数を解釈して実行する
+func runCLI(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("app", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	path := fs.String("config", os.Getenv(config.FileEnv), "設定ファイルのパス (YAML または TOML、環境変数 "+config.FileEnv+" でも指定できる)")
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "usage: app [-config path] <command> [flags] [args]\n\ncommands:\n")
+		tw := tabwriter.NewWriter(stderr, 0, 0, 2, ' ', 0)
+		for _, c := range commands {
+			fmt.Fprintf(tw, "  %s\t%s\n", c.name, c.summary)
+		}
+		tw.Flush()
+		fmt.Fprintf(stderr, "\nflags:\n")
+		fs.PrintDefaults()
+		fmt.Fprintf(stderr, "\n各コマンドの使い方は app <command> -help で表示する\n")
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return errUsage
+	}
+
+	name, rest := "serve", fs.Args()
+	if len(rest) > 0 {
+		name, rest = rest[0], rest[1:]
+	}
+	var cmd *command
+	for i := range commands {
+		if commands[i].name == name {
+			cmd = &commands[i]
+		}
+	}
+	if cmd == nil {
+		fmt.Fprintf(stderr, "unknown command: %q\n\n", name)
+		fs.Usage()
+		return errUsage
+	}
+
+	cfs := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+	cfs.SetOutput(stderr)
+	cfs.Usage = func() {
+		fmt.Fprintf(stderr, "usage: app %s [flags] %s\n\n%s\n", cmd.name, cmd.args, cmd.summary)
+		if hasFlags(cfs) {
+			fmt.Fprintf(stderr, "\nflags:\n")
+			cfs.PrintDefaults()
+		}
+	}
+	run := cmd.setup(cfs)
+	if err := cfs.Parse(rest); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return errUsage
+	}
+
+	// すべてのサブコマンドで同じ方法で設定を読み込む
+	load := func() (*config.Config, error) { return config.Load(*path, os.Environ()) }
+	cfg, err := load()
+	if err != nil {
+		return err
+	}
+	e := &cmdEnv{cfg: cfg, load: load, stdin: stdin, stdout: stdout, stderr: stderr}
+	if err := run(ctx, e, cfs.Args()); err != nil {
+		if errors.Is(err, errUsage) {
+			cfs.Usage()
+		}
+		return err
+	}
+	return nil
+}
+
+func hasFlags(fs *flag.FlagSet) bool {
+	var found bool
+	fs.VisitAll(func(*flag.Flag) { found = true })
+	return found
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRunCLI(t *testing.T) {
+	t.Setenv("CONFIG_FILE", "")
+
+	tests := map[string]struct {
+		args       []string
+		wantErr    error
+		wantStdout []string
+		wantStderr []string
+	}{
+		"help": {
+			args:       []string{"-help"},
+			wantStderr: []string{"usage: app", "serve", "migrate", "create-admin", "seed", "config"},
+		},
+		"commandHelp": {
+			args:       []string{"create-admin", "-help"},
+			wantStderr: []string{"usage: app create-admin", "-email", "-password-stdin"},
+		},
+		"unknownCommand": {
+			args:       []string{"deploy"},
+			wantErr:    errUsage,
+			wantStderr: []string{`unknown command: "deploy"`},
+		},
+		"configPrint": {
+			args:       []string{"-config", "config/testdata/config.yaml", "config", "print"},
+			wantStdout: []string{"backend_port: 8080\n", "blog_database_password: REDACTED\n"},
+		},
+		"configWithoutArgs": {
+			args:       []string{"config"},
+			wantErr:    errUsage,
+			wantStderr: []string{"usage: app config [flags] print"},
+		},
+		// データベースに接続する前に引数の誤りを報告する
+		"migrateUsage": {
+			args:       []string{"migrate", "sideways"},
+			wantErr:    errUsage,
+			wantStderr: []string{"usage: app migrate"},
+		},
+		"createAdminWithoutName": {
+			args:    []string{"create-admin", "-email", "admin@example.com"},
+			wantErr: errUsage,
+		},
+		"exportWithoutOut": {
+			args:       []string{"export", "-layout", "zenn"},
+			wantErr:    errUsage,
+			wantStderr: []string{"usage: app export", "-layout"},
+		},
+		"importWithoutDir": {
+			args:       []string{"import", "-dry-run"},
+			wantErr:    errUsage,
+			wantStderr: []string{"usage: app import [flags] <dir>", "-prefix"},
+		},
+		"importWordPressWithoutFile": {
+			args:       []string{"import-wordpress", "-author", "admin"},
+			wantErr:    errUsage,
+			wantStderr: []string{"usage: app import-wordpress [flags] <export.xml>", "-dry-run"},
+		},
+		"backupWithoutOut": {
+			args:       []string{"backup", "-blobs", "media.tar"},
+			wantErr:    errUsage,
+			wantStderr: []string{"usage: app backup", "-out"},
+		},
+		"restoreWithoutArchive": {
+			args:       []string{"restore"},
+			wantErr:    errUsage,
+			wantStderr: []string{"usage: app restore [flags] <archive>", "-blobs"},
+		},
+		"seedInvalidStart": {
+			args:       []string{"seed", "-start", "2023/01/01"},
+			wantErr:    errUsage,
+			wantStderr: []string{"usage: app seed", "-articles", "-seed"},
+		},
+	}
+
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			err := runCLI(context.Background(), tt.args, strings.NewReader(""), &stdout, &stderr)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("want error %v, but got %v", tt.wantErr, err)
+			}
+			for _, w := range tt.wantStdout {
+				if !strings.Contains(stdout.String(), w) {
+					t.Errorf("want stdout containing %q, but got:\n%s", w, stdout.String())
+				}
+			}
+			for _, w := range tt.wantStderr {
+				if !strings.Contains(stderr.String(), w) {
+					t.Errorf("want stderr containing %q, but got:\n%s", w, stderr.String())
+				}
+			}
+		})
+	}
+}
@@ -1,23 +1,210 @@
 package config
 
 import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
 	"github.com/caarlos0/env/v11"
+	"gopkg.in/yaml.v3"
 )
 
 type Config struct {
-	BackendEnv string `env:"BACKEND_ENV" envDefault:"dev"`
-	BckendPort int    `env:"BACKEND_PORT" envDefault:"80"`
-	DBHost     string `env:"BLOG_DATABASE_HOST" envDefault:"127.0.0.1"`
-	DBPort     int    `env:"BLOG_DATABASE_PORT" envDefault:"3306"`
-	DBUser     string `env:"BLOG_DATABASE_USER" envDefault:"blog"`
-	DBPassword string `env:"BLOG_DATABASE_PASSWORD" envDefault:"blog"`
-	DBName     string `env:"BLOG_DATABASE_DATABASE" envDefault:"blog"`
+	BackendEnv  string `env:"BACKEND_ENV" envDefault:"dev"`
+	BackendPort int    `env:"BACKEND_PORT" envDefault:"80"`
+	DBHost      string `env:"BLOG_DATABASE_HOST" envDefault:"127.0.0.1"`
+	DBPort      int    `env:"BLOG_DATABASE_PORT" envDefault:"3306"`
+	DBUser      string `env:"BLOG_DATABASE_USER" envDefault:"blog"`
+	DBPassword  string `env:"BLOG_DATABASE_PASSWORD" envDefault:"blog" secret:"true"`
+	DBName      string `env:"BLOG_DATABASE_DATABASE" envDefault:"blog"`
+	// データベースの種類 (mysql, postgres または sqlite)
+	// postgres の場合は BLOG_DATABASE_PORT に PostgreSQL のポート (通常は 5432) を指定する
+	// sqlite の場合は BLOG_DATABASE_FILE のファイルを使い、ホストなどの接続先の設定は使わない
+	DBDriver string `env:"BLOG_DATABASE_DRIVER" envDefault:"mysql"`
+	DBFile   string `env:"BLOG_DATABASE_FILE" envDefault:"blog.db"`
+	// UNIX ドメインソケットで接続する場合のソケットのパス (指定した場合はホストを使わない)
+	// PostgreSQL の場合はソケットのあるディレクトリを指定する
+	DBSocket string `env:"BLOG_DATABASE_SOCKET"`
+	// データベースとの通信の TLS (disable, require または verify-full)
+	// require は暗号化だけを行い、verify-full は証明書とホスト名も検証する
+	// サーバ名は MySQL の場合だけ BLOG_DATABASE_TLS_SERVER_NAME で上書きできる
+	DBTLSMode       string `env:"BLOG_DATABASE_TLS" envDefault:"disable"`
+	DBTLSCAFile     string `env:"BLOG_DATABASE_TLS_CA_FILE"`
+	DBTLSCertFile   string `env:"BLOG_DATABASE_TLS_CERT_FILE"`
+	DBTLSKeyFile    string `env:"BLOG_DATABASE_TLS_KEY_FILE"`
+	DBTLSServerName string `env:"BLOG_DATABASE_TLS_SERVER_NAME"`
+	// コネクションプールの上限 (0 の場合は無制限) と、コネクションを使い続ける期間
+	DBMaxOpenConns    int           `env:"BLOG_DATABASE_MAX_OPEN_CONNS" envDefault:"25"`
+	DBMaxIdleConns    int           `env:"BLOG_DATABASE_MAX_IDLE_CONNS" envDefault:"10"`
+	DBConnMaxLifetime time.Duration `env:"BLOG_DATABASE_CONN_MAX_LIFETIME" envDefault:"5m"`
+	// 読み出し用のレプリカの接続先 (host または host:port のカンマ区切り)
+	// ユーザ名などはプライマリと同じものを使い、ポートを省略した場合は BLOG_DATABASE_PORT を使う
+	// 遅延が BLOG_DATABASE_REPLICA_MAX_LAG を超えたレプリカや、疎通できないレプリカからは読み出さない
+	DBReplicaHosts         []string      `env:"BLOG_DATABASE_REPLICA_HOSTS" envSeparator:","`
+	DBReplicaMaxLag        time.Duration `env:"BLOG_DATABASE_REPLICA_MAX_LAG" envDefault:"5s"`
+	DBReplicaCheckInterval time.Duration `env:"BLOG_DATABASE_REPLICA_CHECK_INTERVAL" envDefault:"5s"`
+	// 記事などの保存先 (database または memory)
+	// memory の場合はデータベースに接続せず、保存した内容は再起動すると失われる (開発環境向け)
+	BackendStore string `env:"BACKEND_STORE" envDefault:"database"`
+	// ログの出力レベル (debug, info, warn, error) と形式 (json, text)
+	// 形式を省略すると本番環境では json、それ以外では text になる
+	// reload タグの付いた項目は SIGHUP で再起動せずに変更できる
+	LogLevel  string `env:"LOG_LEVEL" envDefault:"info" reload:"true"`
+	LogFormat string `env:"LOG_FORMAT"`
+	// HTTPS で配信する場合の証明書と秘密鍵のファイル (どちらも空の場合は HTTP で配信する)
+	// ファイルが更新されると再起動せずに読み込み直す
+	TLSCertFile   string `env:"TLS_CERT_FILE"`
+	TLSKeyFile    string `env:"TLS_KEY_FILE"`
+	TLSMinVersion string `env:"TLS_MIN_VERSION" envDefault:"1.2"`
+	// TLS を使わない場合に HTTP/2 (h2c) のリクエストも受け付けるか
+	// 内部のプロキシから HTTP/2 で転送される構成で使う
+	H2C bool `env:"H2C" envDefault:"false"`
+	// HTTPS へリダイレクトするための HTTP のポート (0 の場合はリダイレクトしない)
+	HTTPRedirectPort int `env:"HTTP_REDIRECT_PORT" envDefault:"0"`
+	// /metrics を公開する管理用のポート (0 の場合は BACKEND_PORT で公開する)
+	// /admin/export と /debug/cache はこのポートでのみ公開するので、0 の場合は使えない
+	MetricsPort int `env:"METRICS_PORT" envDefault:"0"`
+	// シャットダウンの期限 (過ぎた場合は残りの処理を待たずに終了する)
+	ShutdownTimeout time.Duration `env:"SHUTDOWN_TIMEOUT" envDefault:"30s"`
+	// シャットダウンの開始から接続を閉じ始めるまでの待ち時間
+	// この間は /readyz が失敗するので、ロードバランサが振り分け対象から外す時間を確保できる
+	ShutdownDrainDelay time.Duration `env:"SHUTDOWN_DRAIN_DELAY" envDefault:"5s"`
+	// トレースの出力先 (none, stdout, file, otlp) とサンプリングする割合
+	// otlp の送信先は OTEL_EXPORTER_OTLP_ENDPOINT などの標準の環境変数で指定する
+	TraceExporter    string  `env:"TRACE_EXPORTER" envDefault:"none"`
+	TraceFile        string  `env:"TRACE_FILE" envDefault:"traces.jsonl"`
+	TraceSampleRatio float64 `env:"TRACE_SAMPLE_RATIO" envDefault:"1"`
+	// Idempotency-Key の保存先 (sql または memory) と保存期間、期限切れのキーを削除する間隔
+	// sql の場合は BLOG_DATABASE_DRIVER のデータベースに保存する (以前の名前の mysql も非推奨の別名として使える)
+	IdempotencyStore         string        `env:"IDEMPOTENCY_STORE" envDefault:"sql"`
+	IdempotencyTTL           time.Duration `env:"IDEMPOTENCY_TTL" envDefault:"24h"`
+	IdempotencyPurgeInterval time.Duration `env:"IDEMPOTENCY_PURGE_INTERVAL" envDefault:"1m"`
+	// 記事の読み出し結果をキャッシュする件数 (0 でキャッシュしない) と有効期間
+	ArticleCacheSize int           `env:"ARTICLE_CACHE_SIZE" envDefault:"1000"`
+	ArticleCacheTTL  time.Duration `env:"ARTICLE_CACHE_TTL" envDefault:"30s"`
+	// X-Forwarded-For ヘッダを信頼するプロキシのアドレス (CIDR 表記のカンマ区切り)
+	TrustedProxies []string `env:"TRUSTED_PROXIES" envSeparator:","`
+	// 記事から参照する画像などのファイルを保存するディレクトリ
+	MediaDir string `env:"MEDIA_DIR" envDefault:"media"`
+	// ルートのグループごとのレート制限 (1 秒あたりのリクエスト数とバースト数、0 で制限しない)
+	RateLimitReadRate   float64 `env:"RATE_LIMIT_READ_RATE" envDefault:"20" reload:"true"`
+	RateLimitReadBurst  int     `env:"RATE_LIMIT_READ_BURST" envDefault:"40" reload:"true"`
+	RateLimitWriteRate  float64 `env:"RATE_LIMIT_WRITE_RATE" envDefault:"1" reload:"true"`
+	RateLimitWriteBurst int     `env:"RATE_LIMIT_WRITE_BURST" envDefault:"5" reload:"true"`
 }
 
+// 設定ファイルのパスを指定する環境変数
+const FileEnv = "CONFIG_FILE"
+
+// 環境変数 CONFIG_FILE で指定されたファイルと環境変数から設定を読み込む
 func New() (*Config, error) {
-	cfg := &Config{}
-	if err := env.Parse(cfg); err != nil {
+	return Load(os.Getenv(FileEnv), os.Environ())
+}
+
+// 設定を次の順に読み込み、後のものほど優先する
+//  1. フィールドの envDefault タグの既定値
+//  2. path で指定された YAML または TOML のファイル (キーは環境変数名の小文字)
+//  3. 環境変数
+//  4. 環境変数名に _FILE を付けた環境変数で指定されたファイルの内容 (パスワードなどの秘匿情報向け)
+//
+// 読み込みと検証で見つかった問題はまとめて返す
+func Load(path string, environ []string) (*Config, error) {
+	params, err := env.GetFieldParams(&Config{})
+	if err != nil {
 		return nil, err
 	}
+	keys := make(map[string]bool, len(params))
+	for _, p := range params {
+		keys[p.Key] = true
+	}
+
+	var errs []error
+	vars := map[string]string{}
+	if path != "" {
+		fvars, err := readFile(path)
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range fvars {
+			if !keys[k] {
+				errs = append(errs, fmt.Errorf("%s: unknown key %q", path, strings.ToLower(k)))
+				continue
+			}
+			vars[k] = v
+		}
+	}
+
+	envs := env.ToMap(environ)
+	for k := range keys {
+		v, ok := envs[k]
+		if ok {
+			vars[k] = v
+		}
+		f, fok := envs[k+"_FILE"]
+		if !fok {
+			continue
+		}
+		if ok {
+			errs = append(errs, fmt.Errorf("both %s and %s_FILE are set", k, k))
+			continue
+		}
+		b, err := os.ReadFile(f)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s_FILE: %w", k, err))
+			continue
+		}
+		vars[k] = strings.TrimRight(string(b), "\r\n")
+	}
+
+	cfg := &Config{}
+	if err := env.ParseWithOptions(cfg, env.Options{Environment: vars}); err != nil {
+		errs = append(errs, err)
+	}
+	if err := cfg.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("invalid config:\n%w", errors.Join(errs...))
+	}
 	return cfg, nil
 }
+
+// 設定ファイルを読み込み、キーを環境変数名に変換して返す
+// 配列は環境変数と同じくカンマ区切りの文字列にする
+func readFile(path string) (map[string]string, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	raw := map[string]any{}
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(b, &raw)
+	case ".toml":
+		err = toml.Unmarshal(b, &raw)
+	default:
+		return nil, fmt.Errorf("unsupported config file extension: %q", ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	vars := make(map[string]string, len(raw))
+	for k, v := range raw {
+		if vs, ok := v.([]any); ok {
+			ss := make([]string, len(vs))
+			for i, v := range vs {
+				ss[i] = fmt.Sprint(v)
+			}
+			vars[strings.ToUpper(k)] = strings.Join(ss, ",")
+			continue
+		}
+		vars[strings.ToUpper(k)] = fmt.Sprint(v)
+	}
+	return vars, nil
+}
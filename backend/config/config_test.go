@@ -1,8 +1,12 @@
 package config
 
 import (
+	"bytes"
 	"fmt"
+	"os"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestNew(t *testing.T) {
@@ -13,8 +17,8 @@ func TestNew(t *testing.T) {
 	if err != nil {
 		t.Fatalf("cannot create config: %v", err)
 	}
-	if got.BckendPort != wantPort {
-		t.Errorf("want %d, but %d", wantPort, got.BckendPort)
+	if got.BackendPort != wantPort {
+		t.Errorf("want %d, but %d", wantPort, got.BackendPort)
 	}
 
 	wantEnv := "dev"
@@ -22,3 +26,201 @@ func TestNew(t *testing.T) {
 		t.Errorf("want %s, but %s", wantEnv, got.BackendEnv)
 	}
 }
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		path    string
+		environ []string
+		check   func(t *testing.T, cfg *Config)
+	}{
+		"yaml": {
+			path: "testdata/config.yaml",
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.BackendEnv != "prod" || cfg.BackendPort != 8080 || cfg.DBHost != "db.internal" {
+					t.Errorf("unexpected config: %+v", cfg)
+				}
+				if cfg.ShutdownTimeout != time.Minute {
+					t.Errorf("want shutdown timeout 1m, but got %s", cfg.ShutdownTimeout)
+				}
+				if got := strings.Join(cfg.TrustedProxies, ","); got != "10.0.0.0/8,192.0.2.1" {
+					t.Errorf("unexpected trusted proxies: %q", got)
+				}
+				// ファイルに書かれていない値は既定値になる
+				if cfg.DBPort != 3306 {
+					t.Errorf("want default db port 3306, but got %d", cfg.DBPort)
+				}
+			},
+		},
+		"toml": {
+			path: "testdata/config.toml",
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.BackendEnv != "prod" || cfg.BackendPort != 8080 || cfg.ShutdownTimeout != time.Minute {
+					t.Errorf("unexpected config: %+v", cfg)
+				}
+				if got := strings.Join(cfg.TrustedProxies, ","); got != "10.0.0.0/8,192.0.2.1" {
+					t.Errorf("unexpected trusted proxies: %q", got)
+				}
+			},
+		},
+		"envOverridesFile": {
+			path:    "testdata/config.yaml",
+			environ: []string{"BACKEND_PORT=9090", "BLOG_DATABASE_PASSWORD_FILE=testdata/password"},
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.BackendPort != 9090 {
+					t.Errorf("want port 9090, but got %d", cfg.BackendPort)
+				}
+				if cfg.DBPassword != "s3cr3t" {
+					t.Errorf("want password from file, but got %q", cfg.DBPassword)
+				}
+				if cfg.LogLevel != "debug" {
+					t.Errorf("want log level from file, but got %q", cfg.LogLevel)
+				}
+			},
+		},
+		// 以前の名前も受け付ける
+		"deprecatedIdempotencyStore": {
+			environ: []string{"IDEMPOTENCY_STORE=mysql"},
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.IdempotencyStore != "mysql" {
+					t.Errorf("want idempotency store mysql, but got %q", cfg.IdempotencyStore)
+				}
+			},
+		},
+	}
+
+	for n, tt := range tests {
+		tt := tt
+		t.Run(n, func(t *testing.T) {
+			t.Parallel()
+
+			cfg, err := Load(tt.path, tt.environ)
+			if err != nil {
+				t.Fatalf("cannot load config: %v", err)
+			}
+			tt.check(t, cfg)
+		})
+	}
+}
+
+func TestLoad_Errors(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		path    string
+		environ []string
+		want    []string
+	}{
+		"unknownKey": {
+			path: "testdata/unknown.yaml",
+			want: []string{`unknown key "backend_prot"`},
+		},
+		"bothSecretSources": {
+			environ: []string{"BLOG_DATABASE_PASSWORD=blog", "BLOG_DATABASE_PASSWORD_FILE=testdata/password"},
+			want:    []string{"both BLOG_DATABASE_PASSWORD and BLOG_DATABASE_PASSWORD_FILE are set"},
+		},
+		"unknownDriver": {
+			environ: []string{"BLOG_DATABASE_DRIVER=oracle"},
+			want:    []string{`BLOG_DATABASE_DRIVER must be one of ["mysql" "postgres" "sqlite"]`},
+		},
+		"unknownStore": {
+			environ: []string{"BACKEND_STORE=redis"},
+			want:    []string{`BACKEND_STORE must be one of ["database" "memory"]`},
+		},
+		"unknownIdempotencyStore": {
+			environ: []string{"IDEMPOTENCY_STORE=redis"},
+			want:    []string{`IDEMPOTENCY_STORE must be one of ["sql" "memory" "mysql"]`},
+		},
+		"database": {
+			environ: []string{
+				"BLOG_DATABASE_TLS=on",
+				"BLOG_DATABASE_TLS_CERT_FILE=client.pem",
+				"BLOG_DATABASE_MAX_OPEN_CONNS=10",
+				"BLOG_DATABASE_MAX_IDLE_CONNS=20",
+			},
+			want: []string{
+				`BLOG_DATABASE_TLS must be one of ["disable" "require" "verify-full"]`,
+				"BLOG_DATABASE_TLS_CERT_FILE and BLOG_DATABASE_TLS_KEY_FILE must be set together",
+				"BLOG_DATABASE_MAX_IDLE_CONNS (20) must not exceed BLOG_DATABASE_MAX_OPEN_CONNS (10)",
+			},
+		},
+		// ソケットで接続する場合は、証明書を検証するホスト名を指定する
+		"databaseSocket": {
+			environ: []string{
+				"BLOG_DATABASE_SOCKET=/var/run/mysqld/mysqld.sock",
+				"BLOG_DATABASE_TLS=verify-full",
+			},
+			want: []string{"BLOG_DATABASE_TLS_SERVER_NAME is required to verify the server certificate over BLOG_DATABASE_SOCKET"},
+		},
+		// 問題はまとめて報告される
+		"multiple": {
+			environ: []string{
+				"BACKEND_PORT=x",
+				"LOG_LEVEL=loud",
+				"TRACE_SAMPLE_RATIO=2",
+				"TLS_CERT_FILE=cert.pem",
+				"SHUTDOWN_DRAIN_DELAY=1m",
+			},
+			want: []string{
+				`"BackendPort"`,
+				"LOG_LEVEL must be one of",
+				"TRACE_SAMPLE_RATIO must be between 0 and 1",
+				"TLS_CERT_FILE and TLS_KEY_FILE must be set together",
+				"SHUTDOWN_DRAIN_DELAY (1m0s) must be shorter than SHUTDOWN_TIMEOUT (30s)",
+			},
+		},
+	}
+
+	for n, tt := range tests {
+		tt := tt
+		t.Run(n, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := Load(tt.path, tt.environ)
+			if err == nil {
+				t.Fatal("want error, but got nil")
+			}
+			for _, w := range tt.want {
+				if !strings.Contains(err.Error(), w) {
+					t.Errorf("want error containing %q, but got:\n%v", w, err)
+				}
+			}
+		})
+	}
+}
+
+func TestConfig_Print(t *testing.T) {
+	t.Parallel()
+
+	cfg, err := Load("testdata/config.yaml", []string{"BLOG_DATABASE_PASSWORD=s3cr3t"})
+	if err != nil {
+		t.Fatalf("cannot load config: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := cfg.Print(&buf); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	if strings.Contains(out, "s3cr3t") {
+		t.Errorf("secret is not redacted:\n%s", out)
+	}
+	for _, w := range []string{"blog_database_password: REDACTED\n", "backend_port: 8080\n", "shutdown_timeout: 1m0s\n"} {
+		if !strings.Contains(out, w) {
+			t.Errorf("want output containing %q, but got:\n%s", w, out)
+		}
+	}
+
+	// 出力はそのまま設定ファイルとして読み込める
+	path := t.TempDir() + "/config.yaml"
+	if err := os.WriteFile(path, buf.Bytes(), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	got, err := Load(path, nil)
+	if err != nil {
+		t.Fatalf("cannot load printed config: %v", err)
+	}
+	if got.BackendPort != cfg.BackendPort || got.ShutdownTimeout != cfg.ShutdownTimeout {
+		t.Errorf("printed config does not round-trip: %+v", got)
+	}
+}
@@ -0,0 +1,49 @@
+package config
+
+import (
+	"io"
+	"reflect"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// 秘匿情報を表示する代わりに使う文字列
+const redacted = "REDACTED"
+
+// 設定を読み込み可能な YAML の形式で書き出す
+// secret タグの付いたフィールドの値は伏せる
+func (c *Config) Print(w io.Writer) error {
+	doc := &yaml.Node{Kind: yaml.MappingNode}
+	rv, rt := reflect.ValueOf(c).Elem(), reflect.TypeOf(c).Elem()
+	for i := 0; i < rt.NumField(); i++ {
+		name, _, _ := strings.Cut(rt.Field(i).Tag.Get("env"), ",")
+		if name == "" {
+			continue
+		}
+		var v any = rv.Field(i).Interface()
+		switch fv := v.(type) {
+		case time.Duration:
+			v = fv.String()
+		case string:
+			if rt.Field(i).Tag.Get("secret") == "true" && fv != "" {
+				v = redacted
+			}
+		}
+
+		val := &yaml.Node{}
+		if err := val.Encode(v); err != nil {
+			return err
+		}
+		key := &yaml.Node{Kind: yaml.ScalarNode, Value: strings.ToLower(name)}
+		doc.Content = append(doc.Content, key, val)
+	}
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	return enc.Close()
+}
@@ -0,0 +1,88 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// 再読み込みの結果
+// いずれも変更された項目の環境変数名
+type ReloadResult struct {
+	// 新しい値に入れ替えた項目
+	Applied []string
+	// 再起動しないと反映できないため、元の値のままにした項目
+	Ignored []string
+}
+
+// 実行中の設定を保持し、再読み込みできる項目だけを入れ替える
+type Reloader struct {
+	load func() (*Config, error)
+	cur  atomic.Pointer[Config]
+
+	mu   sync.Mutex
+	subs []func(cfg *Config)
+}
+
+// cfg を現在の設定とし、再読み込みには load を使う
+func NewReloader(cfg *Config, load func() (*Config, error)) *Reloader {
+	r := &Reloader{load: load}
+	r.cur.Store(cfg)
+	return r
+}
+
+// 現在の設定を返す
+// 返した値は入れ替え後も変更されないので、呼び出し側で書き換えてはいけない
+func (r *Reloader) Current() *Config {
+	return r.cur.Load()
+}
+
+// 設定が入れ替わるたびに fn を呼び出す
+func (r *Reloader) Subscribe(fn func(cfg *Config)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.subs = append(r.subs, fn)
+}
+
+// 設定を読み込み直し、reload タグの付いた項目だけを入れ替える
+// 読み込みに失敗した場合は現在の設定をそのまま使い続ける
+func (r *Reloader) Reload() (ReloadResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	next, err := r.load()
+	if err != nil {
+		return ReloadResult{}, err
+	}
+
+	old := r.cur.Load()
+	merged := *old
+	var res ReloadResult
+	ov, nv, mv := reflect.ValueOf(old).Elem(), reflect.ValueOf(next).Elem(), reflect.ValueOf(&merged).Elem()
+	t := ov.Type()
+	for i := 0; i < t.NumField(); i++ {
+		if reflect.DeepEqual(ov.Field(i).Interface(), nv.Field(i).Interface()) {
+			continue
+		}
+		key, _, _ := strings.Cut(t.Field(i).Tag.Get("env"), ",")
+		if t.Field(i).Tag.Get("reload") != "true" {
+			res.Ignored = append(res.Ignored, key)
+			continue
+		}
+		mv.Field(i).Set(nv.Field(i))
+		res.Applied = append(res.Applied, key)
+	}
+	if len(res.Applied) == 0 {
+		return res, nil
+	}
+	if err := merged.Validate(); err != nil {
+		return ReloadResult{}, err
+	}
+
+	r.cur.Store(&merged)
+	for _, fn := range r.subs {
+		fn(&merged)
+	}
+	return res, nil
+}
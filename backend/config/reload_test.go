@@ -0,0 +1,77 @@
+package config
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestReloader_Reload(t *testing.T) {
+	t.Parallel()
+
+	cfg, err := Load("", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var environ []string
+	r := NewReloader(cfg, func() (*Config, error) { return Load("", environ) })
+	var notified []string
+	r.Subscribe(func(cfg *Config) { notified = append(notified, cfg.LogLevel) })
+
+	// 再読み込みできる項目だけが入れ替わり、それ以外は警告のために返される
+	environ = []string{"LOG_LEVEL=debug", "RATE_LIMIT_WRITE_BURST=10", "BLOG_DATABASE_HOST=db.internal"}
+	res, err := r.Reload()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := ReloadResult{
+		Applied: []string{"LOG_LEVEL", "RATE_LIMIT_WRITE_BURST"},
+		Ignored: []string{"BLOG_DATABASE_HOST"},
+	}
+	if !reflect.DeepEqual(res, want) {
+		t.Errorf("want %+v, but got %+v", want, res)
+	}
+	got := r.Current()
+	if got.LogLevel != "debug" || got.RateLimitWriteBurst != 10 || got.DBHost != cfg.DBHost {
+		t.Errorf("unexpected config after reload: %+v", got)
+	}
+	if cfg.LogLevel != "info" {
+		t.Errorf("previous config must not be modified: %+v", cfg)
+	}
+	if !reflect.DeepEqual(notified, []string{"debug"}) {
+		t.Errorf("want subscribers notified once, but got %v", notified)
+	}
+
+	// 読み込みに失敗した場合は現在の設定を使い続ける
+	environ = []string{"LOG_LEVEL=loud"}
+	if _, err := r.Reload(); err == nil {
+		t.Error("want error for invalid config")
+	}
+	if r.Current() != got {
+		t.Error("config must not change when reload fails")
+	}
+
+	// 変更がなければ通知しない
+	environ = []string{"LOG_LEVEL=debug", "RATE_LIMIT_WRITE_BURST=10"}
+	if _, err := r.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if len(notified) != 1 {
+		t.Errorf("want no notification without changes, but got %v", notified)
+	}
+}
+
+func TestReloader_Reload_LoadError(t *testing.T) {
+	t.Parallel()
+
+	cfg := &Config{LogLevel: "info"}
+	errLoad := errors.New("error from load")
+	r := NewReloader(cfg, func() (*Config, error) { return nil, errLoad })
+	if _, err := r.Reload(); !errors.Is(err, errLoad) {
+		t.Errorf("want %v, but got %v", errLoad, err)
+	}
+	if r.Current() != cfg {
+		t.Error("config must not change when load fails")
+	}
+}
@@ -0,0 +1,81 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// 設定値の組み合わせを含めて検証し、見つかった問題をすべて返す
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+	oneOf := func(key, v string, allowed ...string) {
+		check(slices.Contains(allowed, v), "%s must be one of %q, but got %q", key, allowed, v)
+	}
+	port := func(key string, v int, optional bool) {
+		min := 1
+		if optional {
+			min = 0
+		}
+		check(v >= min && v <= 65535, "%s must be between %d and 65535, but got %d", key, min, v)
+	}
+
+	port("BACKEND_PORT", c.BackendPort, false)
+	port("BLOG_DATABASE_PORT", c.DBPort, false)
+	port("METRICS_PORT", c.MetricsPort, true)
+	port("HTTP_REDIRECT_PORT", c.HTTPRedirectPort, true)
+	check(c.MetricsPort == 0 || c.MetricsPort != c.BackendPort, "METRICS_PORT must differ from BACKEND_PORT")
+	check(c.HTTPRedirectPort == 0 || c.HTTPRedirectPort != c.BackendPort, "HTTP_REDIRECT_PORT must differ from BACKEND_PORT")
+	oneOf("BLOG_DATABASE_DRIVER", c.DBDriver, "mysql", "postgres", "sqlite")
+	oneOf("BACKEND_STORE", c.BackendStore, "database", "memory")
+	check(c.DBHost != "" || c.DBSocket != "", "BLOG_DATABASE_HOST or BLOG_DATABASE_SOCKET is required")
+	check(c.DBName != "", "BLOG_DATABASE_DATABASE is required")
+	check(len(c.DBReplicaHosts) == 0 || c.DBDriver != "sqlite", "BLOG_DATABASE_REPLICA_HOSTS cannot be used with sqlite")
+	check(c.DBReplicaMaxLag >= 0, "BLOG_DATABASE_REPLICA_MAX_LAG must not be negative, but got %s", c.DBReplicaMaxLag)
+	check(c.DBReplicaCheckInterval > 0, "BLOG_DATABASE_REPLICA_CHECK_INTERVAL must be positive, but got %s", c.DBReplicaCheckInterval)
+	oneOf("BLOG_DATABASE_TLS", c.DBTLSMode, "disable", "require", "verify-full")
+	check((c.DBTLSCertFile == "") == (c.DBTLSKeyFile == ""), "BLOG_DATABASE_TLS_CERT_FILE and BLOG_DATABASE_TLS_KEY_FILE must be set together")
+	// MySQL に UNIX ドメインソケットで接続する場合は、証明書と照合するホスト名がない (PostgreSQL はソケットでは TLS を使わない)
+	check(c.DBDriver != "mysql" || c.DBTLSMode != "verify-full" || c.DBSocket == "" || c.DBTLSServerName != "",
+		"BLOG_DATABASE_TLS_SERVER_NAME is required to verify the server certificate over BLOG_DATABASE_SOCKET")
+	check(c.DBMaxOpenConns >= 0, "BLOG_DATABASE_MAX_OPEN_CONNS must not be negative, but got %d", c.DBMaxOpenConns)
+	check(c.DBMaxIdleConns >= 0, "BLOG_DATABASE_MAX_IDLE_CONNS must not be negative, but got %d", c.DBMaxIdleConns)
+	check(c.DBMaxOpenConns == 0 || c.DBMaxIdleConns <= c.DBMaxOpenConns, "BLOG_DATABASE_MAX_IDLE_CONNS (%d) must not exceed BLOG_DATABASE_MAX_OPEN_CONNS (%d)", c.DBMaxIdleConns, c.DBMaxOpenConns)
+	check(c.DBConnMaxLifetime >= 0, "BLOG_DATABASE_CONN_MAX_LIFETIME must not be negative, but got %s", c.DBConnMaxLifetime)
+
+	oneOf("LOG_LEVEL", c.LogLevel, "debug", "info", "warn", "error")
+	oneOf("LOG_FORMAT", c.LogFormat, "", "json", "text")
+
+	check(c.ShutdownTimeout > 0, "SHUTDOWN_TIMEOUT must be positive, but got %s", c.ShutdownTimeout)
+	check(c.ShutdownDrainDelay >= 0, "SHUTDOWN_DRAIN_DELAY must not be negative, but got %s", c.ShutdownDrainDelay)
+	check(c.ShutdownDrainDelay < c.ShutdownTimeout, "SHUTDOWN_DRAIN_DELAY (%s) must be shorter than SHUTDOWN_TIMEOUT (%s)", c.ShutdownDrainDelay, c.ShutdownTimeout)
+
+	tls := c.TLSCertFile != "" || c.TLSKeyFile != ""
+	check(!tls || (c.TLSCertFile != "" && c.TLSKeyFile != ""), "TLS_CERT_FILE and TLS_KEY_FILE must be set together")
+	oneOf("TLS_MIN_VERSION", c.TLSMinVersion, "1.0", "1.1", "1.2", "1.3")
+	check(c.HTTPRedirectPort == 0 || tls, "HTTP_REDIRECT_PORT requires TLS_CERT_FILE and TLS_KEY_FILE")
+
+	oneOf("TRACE_EXPORTER", c.TraceExporter, "", "none", "stdout", "file", "otlp")
+	check(c.TraceExporter != "file" || c.TraceFile != "", "TRACE_FILE is required when TRACE_EXPORTER is file")
+	check(c.TraceSampleRatio >= 0 && c.TraceSampleRatio <= 1, "TRACE_SAMPLE_RATIO must be between 0 and 1, but got %v", c.TraceSampleRatio)
+
+	// mysql は sql の以前の名前 (互換性のために受け付ける)
+	oneOf("IDEMPOTENCY_STORE", c.IdempotencyStore, "sql", "memory", "mysql")
+	check(c.IdempotencyTTL > 0, "IDEMPOTENCY_TTL must be positive, but got %s", c.IdempotencyTTL)
+	check(c.IdempotencyPurgeInterval > 0, "IDEMPOTENCY_PURGE_INTERVAL must be positive, but got %s", c.IdempotencyPurgeInterval)
+	check(c.ArticleCacheSize >= 0, "ARTICLE_CACHE_SIZE must not be negative, but got %d", c.ArticleCacheSize)
+	check(c.ArticleCacheSize == 0 || c.ArticleCacheTTL > 0, "ARTICLE_CACHE_TTL must be positive, but got %s", c.ArticleCacheTTL)
+	check(c.MediaDir != "", "MEDIA_DIR must not be empty")
+
+	check(c.RateLimitReadRate >= 0, "RATE_LIMIT_READ_RATE must not be negative, but got %v", c.RateLimitReadRate)
+	check(c.RateLimitReadBurst >= 0, "RATE_LIMIT_READ_BURST must not be negative, but got %d", c.RateLimitReadBurst)
+	check(c.RateLimitWriteRate >= 0, "RATE_LIMIT_WRITE_RATE must not be negative, but got %v", c.RateLimitWriteRate)
+	check(c.RateLimitWriteBurst >= 0, "RATE_LIMIT_WRITE_BURST must not be negative, but got %d", c.RateLimitWriteBurst)
+
+	return errors.Join(errs...)
+}
@@ -11,11 +11,21 @@ const (
 	ArticleWithdrawn ArticleStatus = "withdrawn"
 )
 
+// 記事が取りうるすべてのステータス
+var ArticleStatuses = []ArticleStatus{ArticleDraft, ArticlePublished, ArticleWithdrawn}
+
 type Article struct {
-	ID        ArticleID     `json:"id" db:"id"`
-	Title     string        `json:"title" db:"title"`
-	Status    ArticleStatus `json:"status" db:"status"`
-	CreatedAt time.Time     `json:"crated_at" db:"created_at"`
+	ID      ArticleID `json:"id" db:"id"`
+	Title   string    `json:"title" db:"title"`
+	Content string    `json:"content" db:"content"`
+	// 空の場合は未設定
+	Slug    string        `json:"slug,omitempty" db:"slug"`
+	Status  ArticleStatus `json:"status" db:"status"`
+	Version int64         `json:"version" db:"version"`
+	// 0 の場合は作成者が不明 (作成者の導入前に書かれた記事)
+	AuthorID  UserID    `json:"author_id,omitempty" db:"author_id"`
+	CreatedAt time.Time `json:"crated_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
 }
 
 type Articles []*Article
@@ -0,0 +1,14 @@
+package entity
+
+import "time"
+
+// 外部から取り込んだ記事と取り込み元の対応
+// 同じ取り込み元を再度取り込んだときに、記事を重複して作らないために使う
+type ArticleSource struct {
+	Source    string    `json:"source" db:"source"`
+	ArticleID ArticleID `json:"article_id" db:"article_id"`
+	// 取り込んだ内容のハッシュ値 (変更がなければ更新しない)
+	Checksum  string    `json:"checksum" db:"checksum"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
@@ -0,0 +1,15 @@
+package entity
+
+import "time"
+
+type CommentID int64
+
+type Comment struct {
+	ID        CommentID `json:"id" db:"id"`
+	ArticleID ArticleID `json:"article_id" db:"article_id"`
+	// 0 の場合はユーザとして登録されていない投稿者 (AuthorName に名前を持つ)
+	UserID     UserID    `json:"user_id,omitempty" db:"user_id"`
+	AuthorName string    `json:"author_name,omitempty" db:"author_name"`
+	Body       string    `json:"body" db:"body"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+}
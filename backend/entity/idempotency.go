@@ -0,0 +1,22 @@
+package entity
+
+import "time"
+
+// Idempotency-Key ヘッダ付きのリクエストに対するレスポンスを再送用に保存する
+type IdempotencyRecord struct {
+	// 呼び出し元の識別子と Idempotency-Key ヘッダの値から作るハッシュ値
+	Key string `db:"idempotency_key"`
+	// 同じキーで異なるリクエストが送られてきたことを検出するためのハッシュ値
+	RequestHash string `db:"request_hash"`
+	// 処理中は 0 で、レスポンスが確定したらステータスコードが入る
+	Status      int       `db:"status"`
+	ContentType string    `db:"content_type"`
+	Body        []byte    `db:"body"`
+	ExpiresAt   time.Time `db:"expires_at"`
+	CreatedAt   time.Time `db:"created_at"`
+}
+
+// レスポンスが保存済みかどうか
+func (r *IdempotencyRecord) Completed() bool {
+	return r.Status != 0
+}
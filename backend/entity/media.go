@@ -0,0 +1,12 @@
+package entity
+
+import "time"
+
+// メディアストレージに保存したファイルのメタデータ
+type Media struct {
+	Key         string    `json:"key" db:"storage_key"`
+	ContentType string    `json:"content_type" db:"content_type"`
+	Size        int64     `json:"size" db:"size"`
+	SHA256      string    `json:"sha256" db:"sha256"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
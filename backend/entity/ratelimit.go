@@ -0,0 +1,22 @@
+package entity
+
+import "time"
+
+// トークンバケット方式のレート制限の設定
+type RateLimit struct {
+	// 1 秒あたりに補充されるトークンの数
+	Rate float64
+	// バケットに貯められるトークンの最大数
+	Burst int
+}
+
+// トークンを 1 つ取り出そうとした結果
+type RateLimitResult struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// 次にトークンを取り出せるようになるまでの時間 (Allowed が false の場合のみ)
+	RetryAfter time.Duration
+	// バケットが満杯に戻るまでの時間
+	Reset time.Duration
+}
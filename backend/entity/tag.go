@@ -0,0 +1,17 @@
+package entity
+
+import "time"
+
+type TagID int64
+
+type Tag struct {
+	ID        TagID     `json:"id" db:"id"`
+	Name      string    `json:"name" db:"name"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// 記事とタグの対応
+type ArticleTag struct {
+	ArticleID ArticleID `json:"article_id" db:"article_id"`
+	TagID     TagID     `json:"tag_id" db:"tag_id"`
+}
@@ -0,0 +1,21 @@
+package entity
+
+import "time"
+
+type UserID int64
+type Role string
+
+const (
+	RoleAdmin  Role = "admin"
+	RoleAuthor Role = "author"
+)
+
+type User struct {
+	ID        UserID    `json:"id" db:"id"`
+	Name      string    `json:"name" db:"name"`
+	Email     string    `json:"email" db:"email"`
+	Password  string    `json:"-" db:"password"`
+	Role      Role      `json:"role" db:"role"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"archive/zip"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/iinuma0710/react-go-blog/backend/markdown"
+	"github.com/iinuma0710/react-go-blog/backend/media"
+	"github.com/iinuma0710/react-go-blog/backend/service"
+	"github.com/iinuma0710/react-go-blog/backend/store"
+)
+
+func setupExport(fs *flag.FlagSet) func(ctx context.Context, e *cmdEnv, args []string) error {
+	out := fs.String("out", "", "書き出し先のディレクトリ (必須、.zip で終わる場合は ZIP アーカイブ)")
+	layout := fs.String("layout", string(markdown.LayoutHugo), "ディレクトリ構成 (hugo または zenn)")
+
+	return func(ctx context.Context, e *cmdEnv, args []string) error {
+		if len(args) != 0 || *out == "" {
+			return errUsage
+		}
+		l, err := markdown.ParseLayout(*layout)
+		if err != nil {
+			fmt.Fprintln(e.stderr, err)
+			return errUsage
+		}
+
+		ctx, _, err = e.setupLogger(ctx, e.stderr)
+		if err != nil {
+			return err
+		}
+		db, cleanup, err := e.openDB(ctx)
+		defer cleanup()
+		if err != nil {
+			return err
+		}
+
+		var (
+			dst    markdown.Dest = markdown.DirDest(*out)
+			finish               = func() error { return nil }
+		)
+		if strings.HasSuffix(*out, ".zip") {
+			f, err := os.Create(*out)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			zw := zip.NewWriter(f)
+			dst = markdown.ZipDest{W: zw}
+			finish = func() error { return errors.Join(zw.Close(), f.Close()) }
+		}
+
+		ea := &service.ExportArticles{DB: db, Repo: &store.Repository{}, Media: media.Dir(e.cfg.MediaDir)}
+		rep, err := ea.ExportArticles(ctx, l, dst)
+		if err != nil {
+			return err
+		}
+		if err := finish(); err != nil {
+			return err
+		}
+
+		fmt.Fprintf(e.stdout, "exported %d articles and %d media files to %s\n", rep.Articles, rep.Media, *out)
+		for _, key := range rep.MissingMedia {
+			fmt.Fprintf(e.stdout, "missing media: %s\n", key)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+type clientIPKey struct{}
+
+// X-Forwarded-For ヘッダを信頼するプロキシのアドレス範囲
+type TrustedProxies []netip.Prefix
+
+// CIDR 表記または単一の IP アドレスの一覧から TrustedProxies を作成する
+func ParseTrustedProxies(cidrs []string) (TrustedProxies, error) {
+	tp := make(TrustedProxies, 0, len(cidrs))
+	for _, c := range cidrs {
+		c = strings.TrimSpace(c)
+		if c == "" {
+			continue
+		}
+		if !strings.Contains(c, "/") {
+			addr, err := netip.ParseAddr(c)
+			if err != nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q: %w", c, err)
+			}
+			tp = append(tp, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+		p, err := netip.ParsePrefix(c)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", c, err)
+		}
+		tp = append(tp, p.Masked())
+	}
+	return tp, nil
+}
+
+func (tp TrustedProxies) contains(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	for _, p := range tp {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// 接続元が信頼できるプロキシの場合に限り X-Forwarded-For ヘッダを参照して、クライアントの IP アドレスを返す
+// ヘッダは右から順に読み、信頼できるプロキシではない最初のアドレスをクライアントとみなす
+func (tp TrustedProxies) ClientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	remote, err := netip.ParseAddr(host)
+	if err != nil || !tp.contains(remote) {
+		return host
+	}
+
+	var hops []string
+	for _, h := range r.Header.Values("X-Forwarded-For") {
+		hops = append(hops, strings.Split(h, ",")...)
+	}
+	client := remote
+	for i := len(hops) - 1; i >= 0; i-- {
+		addr, err := netip.ParseAddr(strings.TrimSpace(hops[i]))
+		if err != nil {
+			// 不正な値より左側は偽装されている可能性があるので信頼しない
+			break
+		}
+		client = addr
+		if !tp.contains(addr) {
+			break
+		}
+	}
+	return client.Unmap().String()
+}
+
+// クライアントの IP アドレスを解決してコンテキストに格納するミドルウェア
+func ClientIPMiddleware(tp TrustedProxies) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := context.WithValue(r.Context(), clientIPKey{}, tp.ClientIP(r))
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// リクエストの呼び出し元を識別する文字列を返す
+// 認証の仕組みがまだないので、クライアントの IP アドレスで識別する
+func callerIdentity(r *http.Request) string {
+	if ip, ok := r.Context().Value(clientIPKey{}).(string); ok {
+		return "ip:" + ip
+	}
+	return "ip:" + TrustedProxies(nil).ClientIP(r)
+}
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTrustedProxies_ClientIP(t *testing.T) {
+	t.Parallel()
+
+	tp, err := ParseTrustedProxies([]string{"10.0.0.0/8", "192.0.2.1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		remoteAddr string
+		xff        []string
+		want       string
+	}{
+		"direct": {
+			remoteAddr: "198.51.100.7:1234",
+			want:       "198.51.100.7",
+		},
+		"untrustedRemoteIgnoresHeader": {
+			remoteAddr: "198.51.100.7:1234",
+			xff:        []string{"203.0.113.9"},
+			want:       "198.51.100.7",
+		},
+		"trustedProxy": {
+			remoteAddr: "10.1.2.3:1234",
+			xff:        []string{"203.0.113.9"},
+			want:       "203.0.113.9",
+		},
+		"spoofedLeftmost": {
+			remoteAddr: "10.1.2.3:1234",
+			xff:        []string{"1.1.1.1, 203.0.113.9, 192.0.2.1"},
+			want:       "203.0.113.9",
+		},
+		"multipleHeaders": {
+			remoteAddr: "10.1.2.3:1234",
+			xff:        []string{"1.1.1.1", "203.0.113.9"},
+			want:       "203.0.113.9",
+		},
+		"allTrusted": {
+			remoteAddr: "10.1.2.3:1234",
+			xff:        []string{"10.9.9.9"},
+			want:       "10.9.9.9",
+		},
+		"invalidHop": {
+			remoteAddr: "10.1.2.3:1234",
+			xff:        []string{"203.0.113.9, unknown"},
+			want:       "10.1.2.3",
+		},
+	}
+
+	for n, tt := range tests {
+		tt := tt
+		t.Run(n, func(t *testing.T) {
+			t.Parallel()
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.RemoteAddr = tt.remoteAddr
+			for _, h := range tt.xff {
+				r.Header.Add("X-Forwarded-For", h)
+			}
+			if got := tp.ClientIP(r); got != tt.want {
+				t.Errorf("want %q, but got %q", tt.want, got)
+			}
+		})
+	}
+}
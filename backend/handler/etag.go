@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/iinuma0710/react-go-blog/backend/entity"
+)
+
+var (
+	errNoIfMatch      = errors.New("If-Match header is required")
+	errInvalidIfMatch = errors.New("If-Match header must be a strong ETag")
+)
+
+// 記事のバージョンを強い ETag として表現する
+func formatETag(version int64) string {
+	return fmt.Sprintf(`"%d"`, version)
+}
+
+// If-Match ヘッダに指定された ETag からバージョンを取り出す
+// 楽観的排他制御に使うので、強い ETag が 1 つだけ指定された場合のみ受け付ける
+func parseIfMatch(r *http.Request) (int64, error) {
+	h := strings.TrimSpace(r.Header.Get("If-Match"))
+	if h == "" {
+		return 0, errNoIfMatch
+	}
+	if len(h) < 2 || h[0] != '"' || h[len(h)-1] != '"' {
+		return 0, errInvalidIfMatch
+	}
+	v, err := strconv.ParseInt(h[1:len(h)-1], 10, 64)
+	if err != nil || v < 1 {
+		return 0, errInvalidIfMatch
+	}
+	return v, nil
+}
+
+// URL パスの {id} から記事の ID を取り出す
+func articleIDParam(r *http.Request) (entity.ArticleID, error) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid article id: %w", err)
+	}
+	return entity.ArticleID(id), nil
+}
@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"archive/zip"
+	"fmt"
+	"net/http"
+
+	"github.com/iinuma0710/react-go-blog/backend/logger"
+	"github.com/iinuma0710/react-go-blog/backend/markdown"
+)
+
+// すべての記事を Markdown に変換し、ZIP アーカイブとして返す
+// 下書きも含むので、管理用のポートでのみ公開する
+type ExportArticles struct {
+	Service ExportArticlesService
+}
+
+func (ea *ExportArticles) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	layout := markdown.LayoutHugo
+	if s := r.URL.Query().Get("layout"); s != "" {
+		l, err := markdown.ParseLayout(s)
+		if err != nil {
+			RespondJSON(ctx, w, &ErrResponse{
+				Message: err.Error(),
+			}, http.StatusBadRequest)
+			return
+		}
+		layout = l
+	}
+
+	// 書き出しを始める前のエラーはエラーレスポンスとして返す
+	cw := &countingWriter{w: w}
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="blog-%s.zip"`, layout))
+	zw := zip.NewWriter(cw)
+	rep, err := ea.Service.ExportArticles(ctx, layout, markdown.ZipDest{W: zw})
+	if err == nil {
+		err = zw.Close()
+	}
+	if err != nil {
+		if cw.n == 0 {
+			w.Header().Del("Content-Disposition")
+			RespondJSON(ctx, w, &ErrResponse{
+				Message: err.Error(),
+			}, http.StatusInternalServerError)
+			return
+		}
+		// 途中まで送ったアーカイブは壊れているので、接続を切って失敗を伝える
+		logger.FromContext(ctx).Error("failed to export articles", "error", err)
+		panic(http.ErrAbortHandler)
+	}
+
+	if len(rep.MissingMedia) > 0 {
+		logger.FromContext(ctx).Warn("referenced media not found", "keys", rep.MissingMedia)
+	}
+}
+
+type countingWriter struct {
+	w http.ResponseWriter
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/iinuma0710/react-go-blog/backend/markdown"
+	"github.com/iinuma0710/react-go-blog/backend/testutil"
+)
+
+func TestExportArticles(t *testing.T) {
+	t.Parallel()
+
+	type want struct {
+		status  int
+		layout  markdown.Layout
+		rspFile string
+	}
+	tests := map[string]struct {
+		query string
+		err   error
+		want  want
+	}{
+		"default": {
+			want: want{status: http.StatusOK, layout: markdown.LayoutHugo},
+		},
+		"zenn": {
+			query: "?layout=zenn",
+			want:  want{status: http.StatusOK, layout: markdown.LayoutZenn},
+		},
+		"badLayout": {
+			query: "?layout=jekyll",
+			want: want{
+				status:  http.StatusBadRequest,
+				rspFile: "testdata/export_articles/bad_layout_rsp.json.golden",
+			},
+		},
+		"error": {
+			err: errors.New("db error"),
+			want: want{
+				status:  http.StatusInternalServerError,
+				layout:  markdown.LayoutHugo,
+				rspFile: "testdata/export_articles/error_rsp.json.golden",
+			},
+		},
+	}
+
+	for n, tt := range tests {
+		tt := tt
+		t.Run(n, func(t *testing.T) {
+			t.Parallel()
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/admin/export"+tt.query, nil)
+
+			moq := &ExportArticlesServiceMock{
+				ExportArticlesFunc: func(ctx context.Context, layout markdown.Layout, dst markdown.Dest) (*markdown.Report, error) {
+					if layout != tt.want.layout {
+						t.Errorf("want layout %q, but got %q", tt.want.layout, layout)
+					}
+					if tt.err != nil {
+						return nil, fmt.Errorf("failed to list: %w", tt.err)
+					}
+					f, err := dst.Create("content/posts/hello.md")
+					if err != nil {
+						return nil, err
+					}
+					io.WriteString(f, "# hello\n")
+					return &markdown.Report{Articles: 1}, f.Close()
+				},
+			}
+			sut := &ExportArticles{Service: moq}
+			sut.ServeHTTP(w, r)
+
+			rsp := w.Result()
+			if tt.want.rspFile != "" {
+				testutil.AssertResponse(t, rsp, tt.want.status, testutil.LoadFile(t, tt.want.rspFile))
+				return
+			}
+			if rsp.StatusCode != tt.want.status {
+				t.Fatalf("want status %d, but got %d", tt.want.status, rsp.StatusCode)
+			}
+			if got := rsp.Header.Get("Content-Type"); got != "application/zip" {
+				t.Errorf("want Content-Type application/zip, but got %q", got)
+			}
+			body, _ := io.ReadAll(rsp.Body)
+			zr, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+			if err != nil {
+				t.Fatalf("cannot read zip: %v", err)
+			}
+			if len(zr.File) != 1 || zr.File[0].Name != "content/posts/hello.md" {
+				t.Errorf("unexpected files in zip: %v", zr.File)
+			}
+		})
+	}
+}
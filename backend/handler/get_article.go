@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/iinuma0710/react-go-blog/backend/store"
+)
+
+type GetArticle struct {
+	Service GetArticleService
+}
+
+func (ga *GetArticle) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := articleIDParam(r)
+	if err != nil {
+		RespondJSON(ctx, w, &ErrResponse{
+			Message: err.Error(),
+		}, http.StatusBadRequest)
+		return
+	}
+
+	a, err := ga.Service.GetArticle(ctx, id)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, store.ErrNotFound) {
+			status = http.StatusNotFound
+		}
+		RespondJSON(ctx, w, &ErrResponse{
+			Message: err.Error(),
+		}, status)
+		return
+	}
+
+	// 更新時に If-Match ヘッダで送り返してもらうため、バージョンを ETag として返す
+	w.Header().Set("ETag", formatETag(a.Version))
+	RespondJSON(ctx, w, article{
+		ID:     a.ID,
+		Title:  a.Title,
+		Status: a.Status,
+	}, http.StatusOK)
+}
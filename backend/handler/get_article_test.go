@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/iinuma0710/react-go-blog/backend/entity"
+	"github.com/iinuma0710/react-go-blog/backend/store"
+	"github.com/iinuma0710/react-go-blog/backend/testutil"
+)
+
+// chi のルーティングを経由せずに URL パラメータを設定する
+func withArticleID(r *http.Request, id string) *http.Request {
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", id)
+	return r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+}
+
+func TestGetArticle(t *testing.T) {
+	t.Parallel()
+
+	type want struct {
+		status  int
+		etag    string
+		rspFile string
+	}
+
+	tests := map[string]struct {
+		err  error
+		want want
+	}{
+		"ok": {
+			want: want{
+				status:  http.StatusOK,
+				etag:    `"3"`,
+				rspFile: "testdata/get_article/ok_rsp.json.golden",
+			},
+		},
+		"notFound": {
+			err: store.ErrNotFound,
+			want: want{
+				status:  http.StatusNotFound,
+				rspFile: "testdata/get_article/not_found_rsp.json.golden",
+			},
+		},
+	}
+
+	for n, tt := range tests {
+		tt := tt
+		t.Run(n, func(t *testing.T) {
+			t.Parallel()
+
+			w := httptest.NewRecorder()
+			r := withArticleID(httptest.NewRequest(http.MethodGet, "/articles/1", nil), "1")
+
+			moq := &GetArticleServiceMock{}
+			moq.GetArticleFunc = func(ctx context.Context, id entity.ArticleID) (*entity.Article, error) {
+				if tt.err != nil {
+					return nil, fmt.Errorf("failed to get: %w", tt.err)
+				}
+				return &entity.Article{ID: id, Title: "test1", Status: entity.ArticlePublished, Version: 3}, nil
+			}
+			sut := GetArticle{Service: moq}
+			sut.ServeHTTP(w, r)
+
+			rsp := w.Result()
+			if got := rsp.Header.Get("ETag"); got != tt.want.etag {
+				t.Errorf("want ETag %q, but got %q", tt.want.etag, got)
+			}
+			testutil.AssertResponse(t, rsp, tt.want.status, testutil.LoadFile(t, tt.want.rspFile))
+		})
+	}
+}
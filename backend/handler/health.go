@@ -0,0 +1,22 @@
+package handler
+
+import (
+	"net/http"
+)
+
+// 登録されたチェックを実行し、チェックごとの結果を返すハンドラ
+// いずれかのチェックに失敗した場合は 503 を返す
+type Health struct {
+	Checker HealthChecker
+}
+
+func (h *Health) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	rep := h.Checker.Check(ctx)
+	status := http.StatusOK
+	if !rep.OK() {
+		status = http.StatusServiceUnavailable
+	}
+	RespondJSON(ctx, w, rep, status)
+}
@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/iinuma0710/react-go-blog/backend/health"
+	"github.com/iinuma0710/react-go-blog/backend/testutil"
+)
+
+func TestHealth(t *testing.T) {
+	t.Parallel()
+
+	type want struct {
+		status  int
+		rspFile string
+	}
+
+	tests := map[string]struct {
+		rep  health.Report
+		want want
+	}{
+		"ok": {
+			rep: health.Report{
+				Status: health.StatusOK,
+				Checks: map[string]health.CheckResult{
+					"database": {Status: health.StatusOK, Latency: "1ms"},
+				},
+			},
+			want: want{
+				status:  http.StatusOK,
+				rspFile: "testdata/health/ok_rsp.json.golden",
+			},
+		},
+		"unavailable": {
+			rep: health.Report{
+				Status: health.StatusUnavailable,
+				Checks: map[string]health.CheckResult{
+					"database": {Status: health.StatusUnavailable, Latency: "2s", Error: "context deadline exceeded"},
+				},
+			},
+			want: want{
+				status:  http.StatusServiceUnavailable,
+				rspFile: "testdata/health/unavailable_rsp.json.golden",
+			},
+		},
+	}
+
+	for n, tt := range tests {
+		tt := tt
+		t.Run(n, func(t *testing.T) {
+			t.Parallel()
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/readyz", nil)
+
+			moq := &HealthCheckerMock{}
+			moq.CheckFunc = func(ctx context.Context) health.Report {
+				return tt.rep
+			}
+			sut := Health{Checker: moq}
+			sut.ServeHTTP(w, r)
+
+			testutil.AssertResponse(t, w.Result(), tt.want.status, testutil.LoadFile(t, tt.want.rspFile))
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/iinuma0710/react-go-blog/backend/entity"
+	"github.com/iinuma0710/react-go-blog/backend/store"
+)
+
+// Idempotency-Key ヘッダに指定できる値の最大長
+const maxIdempotencyKeyLength = 255
+
+// Idempotency-Key ヘッダ付きのリクエストのレスポンスを保存し、
+// 同じキーで再送されたリクエストには保存しておいたレスポンスをそのまま返すミドルウェア
+func IdempotencyMiddleware(s IdempotencyStore, ttl time.Duration) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+
+			key := r.Header.Get("Idempotency-Key")
+			if key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if len(key) > maxIdempotencyKeyLength {
+				RespondJSON(ctx, w, &ErrResponse{
+					Message: "Idempotency-Key header is too long",
+				}, http.StatusBadRequest)
+				return
+			}
+
+			// リクエストボディを読み出してハッシュ値を計算し、後続のハンドラ用に戻しておく
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				RespondJSON(ctx, w, &ErrResponse{
+					Message: err.Error(),
+				}, http.StatusBadRequest)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			rec := &entity.IdempotencyRecord{
+				Key:         hashHex(callerIdentity(r), key),
+				RequestHash: hashHex(r.Method, r.URL.Path, string(body)),
+			}
+			err = s.Reserve(ctx, rec, ttl)
+			if errors.Is(err, store.ErrAlreadyExists) {
+				replayIdempotentResponse(ctx, w, s, rec)
+				return
+			}
+			if err != nil {
+				RespondJSON(ctx, w, &ErrResponse{
+					Message: err.Error(),
+				}, http.StatusInternalServerError)
+				return
+			}
+
+			// レスポンスを書き込みつつ、保存用にバッファにも書き写す
+			var buf bytes.Buffer
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			ww.Tee(&buf)
+			defer func() {
+				// クライアントが切断していても保存処理は完了させる
+				ctx := context.WithoutCancel(ctx)
+				if p := recover(); p != nil {
+					// パニックした場合もキーが処理中のまま残らないよう削除してから伝播させる
+					_ = s.Delete(ctx, rec.Key)
+					panic(p)
+				}
+				status := ww.Status()
+				if status == 0 {
+					// WriteHeader を呼ばずに戻った場合は net/http が 200 を返す
+					status = http.StatusOK
+				}
+				if status >= http.StatusInternalServerError {
+					// サーバ側のエラーは再試行で成功する可能性があるので保存しない
+					_ = s.Delete(ctx, rec.Key)
+					return
+				}
+				rec.Status = status
+				rec.ContentType = ww.Header().Get("Content-Type")
+				rec.Body = buf.Bytes()
+				_ = s.Complete(ctx, rec)
+			}()
+			next.ServeHTTP(ww, r)
+		})
+	}
+}
+
+// 保存済みのレスポンスを返す
+func replayIdempotentResponse(ctx context.Context, w http.ResponseWriter, s IdempotencyStore, rec *entity.IdempotencyRecord) {
+	prev, err := s.Get(ctx, rec.Key)
+	if errors.Is(err, store.ErrNotFound) {
+		// 予約と取得の間に有効期限が切れた場合
+		RespondJSON(ctx, w, &ErrResponse{
+			Message: "request with the same Idempotency-Key has expired, retry the request",
+		}, http.StatusConflict)
+		return
+	}
+	if err != nil {
+		RespondJSON(ctx, w, &ErrResponse{
+			Message: err.Error(),
+		}, http.StatusInternalServerError)
+		return
+	}
+
+	if prev.RequestHash != rec.RequestHash {
+		RespondJSON(ctx, w, &ErrResponse{
+			Message: "Idempotency-Key is already used for a different request",
+		}, http.StatusUnprocessableEntity)
+		return
+	}
+	if !prev.Completed() {
+		RespondJSON(ctx, w, &ErrResponse{
+			Message: "request with the same Idempotency-Key is still in progress",
+		}, http.StatusConflict)
+		return
+	}
+
+	if prev.ContentType != "" {
+		w.Header().Set("Content-Type", prev.ContentType)
+	}
+	w.Header().Set("Idempotent-Replayed", "true")
+	w.WriteHeader(prev.Status)
+	_, _ = w.Write(prev.Body)
+}
+
+// 値を区切り文字で連結した SHA-256 ハッシュ値を返す
+func hashHex(values ...string) string {
+	h := sha256.New()
+	for _, v := range values {
+		h.Write([]byte(v))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,229 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/iinuma0710/react-go-blog/backend/clock"
+	"github.com/iinuma0710/react-go-blog/backend/entity"
+	"github.com/iinuma0710/react-go-blog/backend/store"
+)
+
+func TestIdempotencyMiddleware(t *testing.T) {
+	t.Parallel()
+
+	type req struct {
+		key  string
+		body string
+	}
+	type want struct {
+		status   int
+		body     string
+		replayed bool
+	}
+
+	tests := map[string]struct {
+		reqs  []req
+		wants []want
+		calls int
+	}{
+		"replay": {
+			reqs: []req{{key: "k1", body: `{"title":"a"}`}, {key: "k1", body: `{"title":"a"}`}},
+			wants: []want{
+				{status: http.StatusOK, body: `{"id":1}`},
+				{status: http.StatusOK, body: `{"id":1}`, replayed: true},
+			},
+			calls: 1,
+		},
+		"differentBody": {
+			reqs: []req{{key: "k1", body: `{"title":"a"}`}, {key: "k1", body: `{"title":"b"}`}},
+			wants: []want{
+				{status: http.StatusOK, body: `{"id":1}`},
+				{status: http.StatusUnprocessableEntity},
+			},
+			calls: 1,
+		},
+		"differentKey": {
+			reqs: []req{{key: "k1", body: `{"title":"a"}`}, {key: "k2", body: `{"title":"a"}`}},
+			wants: []want{
+				{status: http.StatusOK, body: `{"id":1}`},
+				{status: http.StatusOK, body: `{"id":2}`},
+			},
+			calls: 2,
+		},
+		"noKey": {
+			reqs: []req{{body: `{"title":"a"}`}, {body: `{"title":"a"}`}},
+			wants: []want{
+				{status: http.StatusOK, body: `{"id":1}`},
+				{status: http.StatusOK, body: `{"id":2}`},
+			},
+			calls: 2,
+		},
+	}
+
+	for n, tt := range tests {
+		tt := tt
+		t.Run(n, func(t *testing.T) {
+			t.Parallel()
+
+			calls := 0
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				RespondJSON(r.Context(), w, struct {
+					ID int `json:"id"`
+				}{ID: calls}, http.StatusOK)
+			})
+			sut := IdempotencyMiddleware(store.NewIdempotencyMemory(clock.FixedClocker{}), time.Hour)(next)
+
+			for i, rq := range tt.reqs {
+				w := httptest.NewRecorder()
+				r := httptest.NewRequest(http.MethodPost, "/articles", strings.NewReader(rq.body))
+				if rq.key != "" {
+					r.Header.Set("Idempotency-Key", rq.key)
+				}
+				sut.ServeHTTP(w, r)
+
+				rsp := w.Result()
+				got, _ := io.ReadAll(rsp.Body)
+				if rsp.StatusCode != tt.wants[i].status {
+					t.Errorf("request %d: want status %d, but got %d", i, tt.wants[i].status, rsp.StatusCode)
+				}
+				if tt.wants[i].body != "" && string(got) != tt.wants[i].body {
+					t.Errorf("request %d: want body %s, but got %s", i, tt.wants[i].body, got)
+				}
+				if replayed := rsp.Header.Get("Idempotent-Replayed") == "true"; replayed != tt.wants[i].replayed {
+					t.Errorf("request %d: want replayed %v, but got %v", i, tt.wants[i].replayed, replayed)
+				}
+			}
+			if calls != tt.calls {
+				t.Errorf("want %d calls, but got %d", tt.calls, calls)
+			}
+		})
+	}
+}
+
+func TestIdempotencyMiddleware_serverError(t *testing.T) {
+	t.Parallel()
+
+	moq := &IdempotencyStoreMock{
+		ReserveFunc: func(ctx context.Context, rec *entity.IdempotencyRecord, ttl time.Duration) error {
+			return nil
+		},
+		DeleteFunc: func(ctx context.Context, key string) error {
+			return nil
+		},
+	}
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		RespondJSON(r.Context(), w, &ErrResponse{Message: "error"}, http.StatusInternalServerError)
+	})
+	sut := IdempotencyMiddleware(moq, time.Hour)(next)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/articles", strings.NewReader(`{}`))
+	r.Header.Set("Idempotency-Key", "k1")
+	sut.ServeHTTP(w, r)
+
+	// 5xx のレスポンスは保存せず、再試行できるようにキーを削除する
+	if got := len(moq.DeleteCalls()); got != 1 {
+		t.Errorf("want Delete to be called once, but got %d", got)
+	}
+	if got := len(moq.CompleteCalls()); got != 0 {
+		t.Errorf("want Complete not to be called, but got %d", got)
+	}
+}
+
+func TestIdempotencyMiddleware_panic(t *testing.T) {
+	t.Parallel()
+
+	moq := &IdempotencyStoreMock{
+		ReserveFunc: func(ctx context.Context, rec *entity.IdempotencyRecord, ttl time.Duration) error {
+			return nil
+		},
+		DeleteFunc: func(ctx context.Context, key string) error {
+			return nil
+		},
+	}
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("panic from handler")
+	})
+	sut := IdempotencyMiddleware(moq, time.Hour)(next)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/articles", strings.NewReader(`{}`))
+	r.Header.Set("Idempotency-Key", "k1")
+	func() {
+		// パニックは後段の Recoverer などで処理できるよう伝播させる
+		defer func() {
+			if p := recover(); p != "panic from handler" {
+				t.Errorf("want the panic to be propagated, but got %v", p)
+			}
+		}()
+		sut.ServeHTTP(w, r)
+	}()
+
+	// 処理中のまま残ると有効期限まで再試行できなくなるので、キーを削除する
+	if got := len(moq.DeleteCalls()); got != 1 {
+		t.Errorf("want Delete to be called once, but got %d", got)
+	}
+	if got := len(moq.CompleteCalls()); got != 0 {
+		t.Errorf("want Complete not to be called, but got %d", got)
+	}
+}
+
+// WriteHeader を呼ばずにボディだけを書き込んだ場合は 200 として保存する
+func TestIdempotencyMiddleware_implicitStatus(t *testing.T) {
+	t.Parallel()
+
+	moq := &IdempotencyStoreMock{
+		ReserveFunc: func(ctx context.Context, rec *entity.IdempotencyRecord, ttl time.Duration) error {
+			return nil
+		},
+		CompleteFunc: func(ctx context.Context, rec *entity.IdempotencyRecord) error {
+			return nil
+		},
+	}
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	sut := IdempotencyMiddleware(moq, time.Hour)(next)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/articles", strings.NewReader(`{}`))
+	r.Header.Set("Idempotency-Key", "k1")
+	sut.ServeHTTP(w, r)
+
+	calls := moq.CompleteCalls()
+	if len(calls) != 1 {
+		t.Fatalf("want Complete to be called once, but got %d", len(calls))
+	}
+	if got := calls[0].Rec.Status; got != http.StatusOK {
+		t.Errorf("want status %d, but got %d", http.StatusOK, got)
+	}
+}
+
+func TestIdempotencyMiddleware_storeError(t *testing.T) {
+	t.Parallel()
+
+	moq := &IdempotencyStoreMock{
+		ReserveFunc: func(ctx context.Context, rec *entity.IdempotencyRecord, ttl time.Duration) error {
+			return errors.New("error from mock")
+		},
+	}
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("next handler must not be called")
+	})
+	sut := IdempotencyMiddleware(moq, time.Hour)(next)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/articles", strings.NewReader(`{}`))
+	r.Header.Set("Idempotency-Key", "k1")
+	sut.ServeHTTP(w, r)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("want status %d, but got %d", http.StatusInternalServerError, w.Code)
+	}
+}
@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/iinuma0710/react-go-blog/backend/logger"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// 外部から受け取るリクエスト ID の最大長
+const maxRequestIDLength = 128
+
+// X-Request-ID ヘッダを引き継ぐか新たに採番し、リクエスト ID 付きのロガーをコンテキストに格納するミドルウェア
+func RequestIDMiddleware(base *slog.Logger) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := r.Header.Get("X-Request-ID")
+			if !validRequestID(id) {
+				id = newRequestID()
+			}
+			w.Header().Set("X-Request-ID", id)
+
+			l := base.With("request_id", id)
+			// トレースと突き合わせられるよう、トレース ID もログに含める
+			if sc := trace.SpanContextFromContext(r.Context()); sc.HasTraceID() {
+				l = l.With("trace_id", sc.TraceID().String())
+			}
+			ctx := logger.WithContext(r.Context(), l)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// リクエストごとにステータスコード、レスポンスサイズ、処理時間を 1 行のログに出力するミドルウェア
+func AccessLogMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		next.ServeHTTP(ww, r)
+
+		status := ww.Status()
+		if status == 0 {
+			// ハンドラが何も書き込まなかった場合は net/http が 200 を返す
+			status = http.StatusOK
+		}
+		logger.FromContext(r.Context()).LogAttrs(r.Context(), slog.LevelInfo, "access",
+			slog.String("method", r.Method),
+			slog.String("path", r.URL.Path),
+			slog.Int("status", status),
+			slog.Int("bytes", ww.BytesWritten()),
+			slog.Duration("latency", time.Since(start)),
+			slog.String("remote", callerIdentity(r)),
+		)
+	})
+}
+
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, c := range id {
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+		case c == '-', c == '_', c == '.', c == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRequestIDAndAccessLog(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		reqID  string
+		wantID string
+	}{
+		"propagate": {reqID: "abc-123", wantID: "abc-123"},
+		"generate":  {reqID: ""},
+		"invalid":   {reqID: "bad id\n"},
+	}
+
+	for n, tt := range tests {
+		tt := tt
+		t.Run(n, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			base := slog.New(slog.NewJSONHandler(&buf, nil))
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				RespondJSON(r.Context(), w, &ErrResponse{Message: "failed"}, http.StatusInternalServerError)
+			})
+			sut := RequestIDMiddleware(base)(AccessLogMiddleware(next))
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/articles", nil)
+			if tt.reqID != "" {
+				r.Header.Set("X-Request-ID", tt.reqID)
+			}
+			sut.ServeHTTP(w, r)
+
+			id := w.Result().Header.Get("X-Request-ID")
+			if tt.wantID != "" && id != tt.wantID {
+				t.Errorf("want request id %q, but got %q", tt.wantID, id)
+			}
+			if tt.wantID == "" && len(id) != 32 {
+				t.Errorf("want generated request id, but got %q", id)
+			}
+
+			// エラーログとアクセスログの 2 行が同じリクエスト ID で出力される
+			lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+			if len(lines) != 2 {
+				t.Fatalf("want 2 log lines, but got %q", buf.String())
+			}
+			for _, l := range lines {
+				var got map[string]any
+				if err := json.Unmarshal(l, &got); err != nil {
+					t.Fatal(err)
+				}
+				if got["request_id"] != id {
+					t.Errorf("want request_id %q, but got %v", id, got["request_id"])
+				}
+			}
+
+			var access map[string]any
+			_ = json.Unmarshal(lines[1], &access)
+			if access["msg"] != "access" || access["status"] != float64(500) || access["bytes"] != float64(len(w.Body.Bytes())) {
+				t.Errorf("unexpected access log: %v", access)
+			}
+			if _, ok := access["latency"]; !ok {
+				t.Errorf("want latency in access log: %v", access)
+			}
+		})
+	}
+}
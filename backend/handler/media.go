@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/iinuma0710/react-go-blog/backend/logger"
+	"github.com/iinuma0710/react-go-blog/backend/media"
+)
+
+// 記事から /media/<key> で参照するファイルを返す
+type Media struct {
+	Storage MediaOpener
+}
+
+func (m *Media) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	key := chi.URLParam(r, "*")
+	rc, err := m.Storage.Open(ctx, key)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, media.ErrNotFound) {
+			status = http.StatusNotFound
+		}
+		RespondJSON(ctx, w, &ErrResponse{
+			Message: err.Error(),
+		}, status)
+		return
+	}
+	defer rc.Close()
+
+	if ct := mime.TypeByExtension(path.Ext(key)); ct != "" {
+		w.Header().Set("Content-Type", ct)
+	}
+	// キーにはファイルの内容のハッシュ値が含まれ、同じキーの内容は変わらない
+	w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	if _, err := io.Copy(w, rc); err != nil {
+		logger.FromContext(ctx).Error("failed to write media", "key", key, "error", err)
+	}
+}
@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/iinuma0710/react-go-blog/backend/media"
+	"github.com/iinuma0710/react-go-blog/backend/testutil"
+)
+
+func TestMedia(t *testing.T) {
+	t.Parallel()
+
+	moq := &MediaOpenerMock{
+		OpenFunc: func(ctx context.Context, key string) (io.ReadCloser, error) {
+			if key != "0123456789abcdef/top.png" {
+				return nil, fmt.Errorf("%w: %s", media.ErrNotFound, key)
+			}
+			return io.NopCloser(strings.NewReader("\x89PNG")), nil
+		},
+	}
+	mux := chi.NewRouter()
+	mux.Handle("/media/*", &Media{Storage: moq})
+
+	t.Run("ok", func(t *testing.T) {
+		t.Parallel()
+
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/media/0123456789abcdef/top.png", nil))
+		rsp := w.Result()
+		if rsp.StatusCode != http.StatusOK {
+			t.Fatalf("want status %d, but got %d", http.StatusOK, rsp.StatusCode)
+		}
+		if got := rsp.Header.Get("Content-Type"); got != "image/png" {
+			t.Errorf("want Content-Type image/png, but got %q", got)
+		}
+		if b, _ := io.ReadAll(rsp.Body); string(b) != "\x89PNG" {
+			t.Errorf("unexpected body: %q", b)
+		}
+	})
+
+	t.Run("notFound", func(t *testing.T) {
+		t.Parallel()
+
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/media/0123456789abcdef/missing.png", nil))
+		testutil.AssertResponse(t, w.Result(), http.StatusNotFound, testutil.LoadFile(t, "testdata/media/not_found_rsp.json.golden"))
+	})
+}
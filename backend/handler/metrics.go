@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// ルーティングに一致しなかったリクエストのラベル
+const unmatchedRoute = "unmatched"
+
+// リクエスト数と処理時間を chi のルートパターンとステータスコードごとに記録するミドルウェア
+// パス中の ID などでラベルが増え続けないよう、実際のパスではなくパターンを使う
+func MetricsMiddleware(o RequestObserver) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			next.ServeHTTP(ww, r)
+
+			route := unmatchedRoute
+			if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+				route = rctx.RoutePattern()
+			}
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+			o.ObserveRequest(r.Method, route, status, time.Since(start))
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func TestMetricsMiddleware(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		path      string
+		wantRoute string
+		wantCode  int
+	}{
+		"pattern":   {path: "/articles/42", wantRoute: "/articles/{id}", wantCode: http.StatusOK},
+		"unmatched": {path: "/unknown", wantRoute: unmatchedRoute, wantCode: http.StatusNotFound},
+	}
+
+	for n, tt := range tests {
+		tt := tt
+		t.Run(n, func(t *testing.T) {
+			t.Parallel()
+
+			moq := &RequestObserverMock{
+				ObserveRequestFunc: func(method, route string, status int, d time.Duration) {},
+			}
+			mux := chi.NewRouter()
+			mux.Use(MetricsMiddleware(moq))
+			mux.Get("/articles/{id}", func(w http.ResponseWriter, r *http.Request) {})
+
+			mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			calls := moq.ObserveRequestCalls()
+			if len(calls) != 1 {
+				t.Fatalf("want 1 call, but got %d", len(calls))
+			}
+			if calls[0].Route != tt.wantRoute || calls[0].Status != tt.wantCode {
+				t.Errorf("want %s %d, but got %s %d", tt.wantRoute, tt.wantCode, calls[0].Route, calls[0].Status)
+			}
+		})
+	}
+}
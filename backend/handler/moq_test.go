@@ -6,7 +6,11 @@ package handler
 import (
 	"context"
 	"github.com/iinuma0710/react-go-blog/backend/entity"
+	"github.com/iinuma0710/react-go-blog/backend/health"
+	"github.com/iinuma0710/react-go-blog/backend/markdown"
+	"io"
 	"sync"
+	"time"
 )
 
 // Ensure, that ListArticlesServiceMock does implement ListArticlesService.
@@ -146,3 +150,771 @@ func (mock *AddArticleServiceMock) AddArticleCalls() []struct {
 	mock.lockAddArticle.RUnlock()
 	return calls
 }
+
+// Ensure, that GetArticleServiceMock does implement GetArticleService.
+// If this is not the case, regenerate this file with moq.
+var _ GetArticleService = &GetArticleServiceMock{}
+
+// GetArticleServiceMock is a mock implementation of GetArticleService.
+//
+//	func TestSomethingThatUsesGetArticleService(t *testing.T) {
+//
+//		// make and configure a mocked GetArticleService
+//		mockedGetArticleService := &GetArticleServiceMock{
+//			GetArticleFunc: func(ctx context.Context, id entity.ArticleID) (*entity.Article, error) {
+//				panic("mock out the GetArticle method")
+//			},
+//		}
+//
+//		// use mockedGetArticleService in code that requires GetArticleService
+//		// and then make assertions.
+//
+//	}
+type GetArticleServiceMock struct {
+	// GetArticleFunc mocks the GetArticle method.
+	GetArticleFunc func(ctx context.Context, id entity.ArticleID) (*entity.Article, error)
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// GetArticle holds details about calls to the GetArticle method.
+		GetArticle []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the id argument value.
+			ID entity.ArticleID
+		}
+	}
+	lockGetArticle sync.RWMutex
+}
+
+// GetArticle calls GetArticleFunc.
+func (mock *GetArticleServiceMock) GetArticle(ctx context.Context, id entity.ArticleID) (*entity.Article, error) {
+	if mock.GetArticleFunc == nil {
+		panic("GetArticleServiceMock.GetArticleFunc: method is nil but GetArticleService.GetArticle was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		ID  entity.ArticleID
+	}{
+		Ctx: ctx,
+		ID:  id,
+	}
+	mock.lockGetArticle.Lock()
+	mock.calls.GetArticle = append(mock.calls.GetArticle, callInfo)
+	mock.lockGetArticle.Unlock()
+	return mock.GetArticleFunc(ctx, id)
+}
+
+// GetArticleCalls gets all the calls that were made to GetArticle.
+// Check the length with:
+//
+//	len(mockedGetArticleService.GetArticleCalls())
+func (mock *GetArticleServiceMock) GetArticleCalls() []struct {
+	Ctx context.Context
+	ID  entity.ArticleID
+} {
+	var calls []struct {
+		Ctx context.Context
+		ID  entity.ArticleID
+	}
+	mock.lockGetArticle.RLock()
+	calls = mock.calls.GetArticle
+	mock.lockGetArticle.RUnlock()
+	return calls
+}
+
+// Ensure, that UpdateArticleServiceMock does implement UpdateArticleService.
+// If this is not the case, regenerate this file with moq.
+var _ UpdateArticleService = &UpdateArticleServiceMock{}
+
+// UpdateArticleServiceMock is a mock implementation of UpdateArticleService.
+//
+//	func TestSomethingThatUsesUpdateArticleService(t *testing.T) {
+//
+//		// make and configure a mocked UpdateArticleService
+//		mockedUpdateArticleService := &UpdateArticleServiceMock{
+//			UpdateArticleFunc: func(ctx context.Context, id entity.ArticleID, version int64, title string, status entity.ArticleStatus) (*entity.Article, error) {
+//				panic("mock out the UpdateArticle method")
+//			},
+//		}
+//
+//		// use mockedUpdateArticleService in code that requires UpdateArticleService
+//		// and then make assertions.
+//
+//	}
+type UpdateArticleServiceMock struct {
+	// UpdateArticleFunc mocks the UpdateArticle method.
+	UpdateArticleFunc func(ctx context.Context, id entity.ArticleID, version int64, title string, status entity.ArticleStatus) (*entity.Article, error)
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// UpdateArticle holds details about calls to the UpdateArticle method.
+		UpdateArticle []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the id argument value.
+			ID entity.ArticleID
+			// Version is the version argument value.
+			Version int64
+			// Title is the title argument value.
+			Title string
+			// Status is the status argument value.
+			Status entity.ArticleStatus
+		}
+	}
+	lockUpdateArticle sync.RWMutex
+}
+
+// UpdateArticle calls UpdateArticleFunc.
+func (mock *UpdateArticleServiceMock) UpdateArticle(ctx context.Context, id entity.ArticleID, version int64, title string, status entity.ArticleStatus) (*entity.Article, error) {
+	if mock.UpdateArticleFunc == nil {
+		panic("UpdateArticleServiceMock.UpdateArticleFunc: method is nil but UpdateArticleService.UpdateArticle was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		ID      entity.ArticleID
+		Version int64
+		Title   string
+		Status  entity.ArticleStatus
+	}{
+		Ctx:     ctx,
+		ID:      id,
+		Version: version,
+		Title:   title,
+		Status:  status,
+	}
+	mock.lockUpdateArticle.Lock()
+	mock.calls.UpdateArticle = append(mock.calls.UpdateArticle, callInfo)
+	mock.lockUpdateArticle.Unlock()
+	return mock.UpdateArticleFunc(ctx, id, version, title, status)
+}
+
+// UpdateArticleCalls gets all the calls that were made to UpdateArticle.
+// Check the length with:
+//
+//	len(mockedUpdateArticleService.UpdateArticleCalls())
+func (mock *UpdateArticleServiceMock) UpdateArticleCalls() []struct {
+	Ctx     context.Context
+	ID      entity.ArticleID
+	Version int64
+	Title   string
+	Status  entity.ArticleStatus
+} {
+	var calls []struct {
+		Ctx     context.Context
+		ID      entity.ArticleID
+		Version int64
+		Title   string
+		Status  entity.ArticleStatus
+	}
+	mock.lockUpdateArticle.RLock()
+	calls = mock.calls.UpdateArticle
+	mock.lockUpdateArticle.RUnlock()
+	return calls
+}
+
+// Ensure, that IdempotencyStoreMock does implement IdempotencyStore.
+// If this is not the case, regenerate this file with moq.
+var _ IdempotencyStore = &IdempotencyStoreMock{}
+
+// IdempotencyStoreMock is a mock implementation of IdempotencyStore.
+//
+//	func TestSomethingThatUsesIdempotencyStore(t *testing.T) {
+//
+//		// make and configure a mocked IdempotencyStore
+//		mockedIdempotencyStore := &IdempotencyStoreMock{
+//			CompleteFunc: func(ctx context.Context, rec *entity.IdempotencyRecord) error {
+//				panic("mock out the Complete method")
+//			},
+//			DeleteFunc: func(ctx context.Context, key string) error {
+//				panic("mock out the Delete method")
+//			},
+//			GetFunc: func(ctx context.Context, key string) (*entity.IdempotencyRecord, error) {
+//				panic("mock out the Get method")
+//			},
+//			ReserveFunc: func(ctx context.Context, rec *entity.IdempotencyRecord, ttl time.Duration) error {
+//				panic("mock out the Reserve method")
+//			},
+//		}
+//
+//		// use mockedIdempotencyStore in code that requires IdempotencyStore
+//		// and then make assertions.
+//
+//	}
+type IdempotencyStoreMock struct {
+	// CompleteFunc mocks the Complete method.
+	CompleteFunc func(ctx context.Context, rec *entity.IdempotencyRecord) error
+
+	// DeleteFunc mocks the Delete method.
+	DeleteFunc func(ctx context.Context, key string) error
+
+	// GetFunc mocks the Get method.
+	GetFunc func(ctx context.Context, key string) (*entity.IdempotencyRecord, error)
+
+	// ReserveFunc mocks the Reserve method.
+	ReserveFunc func(ctx context.Context, rec *entity.IdempotencyRecord, ttl time.Duration) error
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// Complete holds details about calls to the Complete method.
+		Complete []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Rec is the rec argument value.
+			Rec *entity.IdempotencyRecord
+		}
+		// Delete holds details about calls to the Delete method.
+		Delete []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Key is the key argument value.
+			Key string
+		}
+		// Get holds details about calls to the Get method.
+		Get []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Key is the key argument value.
+			Key string
+		}
+		// Reserve holds details about calls to the Reserve method.
+		Reserve []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Rec is the rec argument value.
+			Rec *entity.IdempotencyRecord
+			// TTL is the ttl argument value.
+			TTL time.Duration
+		}
+	}
+	lockComplete sync.RWMutex
+	lockDelete   sync.RWMutex
+	lockGet      sync.RWMutex
+	lockReserve  sync.RWMutex
+}
+
+// Complete calls CompleteFunc.
+func (mock *IdempotencyStoreMock) Complete(ctx context.Context, rec *entity.IdempotencyRecord) error {
+	if mock.CompleteFunc == nil {
+		panic("IdempotencyStoreMock.CompleteFunc: method is nil but IdempotencyStore.Complete was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Rec *entity.IdempotencyRecord
+	}{
+		Ctx: ctx,
+		Rec: rec,
+	}
+	mock.lockComplete.Lock()
+	mock.calls.Complete = append(mock.calls.Complete, callInfo)
+	mock.lockComplete.Unlock()
+	return mock.CompleteFunc(ctx, rec)
+}
+
+// CompleteCalls gets all the calls that were made to Complete.
+// Check the length with:
+//
+//	len(mockedIdempotencyStore.CompleteCalls())
+func (mock *IdempotencyStoreMock) CompleteCalls() []struct {
+	Ctx context.Context
+	Rec *entity.IdempotencyRecord
+} {
+	var calls []struct {
+		Ctx context.Context
+		Rec *entity.IdempotencyRecord
+	}
+	mock.lockComplete.RLock()
+	calls = mock.calls.Complete
+	mock.lockComplete.RUnlock()
+	return calls
+}
+
+// Delete calls DeleteFunc.
+func (mock *IdempotencyStoreMock) Delete(ctx context.Context, key string) error {
+	if mock.DeleteFunc == nil {
+		panic("IdempotencyStoreMock.DeleteFunc: method is nil but IdempotencyStore.Delete was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Key string
+	}{
+		Ctx: ctx,
+		Key: key,
+	}
+	mock.lockDelete.Lock()
+	mock.calls.Delete = append(mock.calls.Delete, callInfo)
+	mock.lockDelete.Unlock()
+	return mock.DeleteFunc(ctx, key)
+}
+
+// DeleteCalls gets all the calls that were made to Delete.
+// Check the length with:
+//
+//	len(mockedIdempotencyStore.DeleteCalls())
+func (mock *IdempotencyStoreMock) DeleteCalls() []struct {
+	Ctx context.Context
+	Key string
+} {
+	var calls []struct {
+		Ctx context.Context
+		Key string
+	}
+	mock.lockDelete.RLock()
+	calls = mock.calls.Delete
+	mock.lockDelete.RUnlock()
+	return calls
+}
+
+// Get calls GetFunc.
+func (mock *IdempotencyStoreMock) Get(ctx context.Context, key string) (*entity.IdempotencyRecord, error) {
+	if mock.GetFunc == nil {
+		panic("IdempotencyStoreMock.GetFunc: method is nil but IdempotencyStore.Get was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Key string
+	}{
+		Ctx: ctx,
+		Key: key,
+	}
+	mock.lockGet.Lock()
+	mock.calls.Get = append(mock.calls.Get, callInfo)
+	mock.lockGet.Unlock()
+	return mock.GetFunc(ctx, key)
+}
+
+// GetCalls gets all the calls that were made to Get.
+// Check the length with:
+//
+//	len(mockedIdempotencyStore.GetCalls())
+func (mock *IdempotencyStoreMock) GetCalls() []struct {
+	Ctx context.Context
+	Key string
+} {
+	var calls []struct {
+		Ctx context.Context
+		Key string
+	}
+	mock.lockGet.RLock()
+	calls = mock.calls.Get
+	mock.lockGet.RUnlock()
+	return calls
+}
+
+// Reserve calls ReserveFunc.
+func (mock *IdempotencyStoreMock) Reserve(ctx context.Context, rec *entity.IdempotencyRecord, ttl time.Duration) error {
+	if mock.ReserveFunc == nil {
+		panic("IdempotencyStoreMock.ReserveFunc: method is nil but IdempotencyStore.Reserve was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Rec *entity.IdempotencyRecord
+		TTL time.Duration
+	}{
+		Ctx: ctx,
+		Rec: rec,
+		TTL: ttl,
+	}
+	mock.lockReserve.Lock()
+	mock.calls.Reserve = append(mock.calls.Reserve, callInfo)
+	mock.lockReserve.Unlock()
+	return mock.ReserveFunc(ctx, rec, ttl)
+}
+
+// ReserveCalls gets all the calls that were made to Reserve.
+// Check the length with:
+//
+//	len(mockedIdempotencyStore.ReserveCalls())
+func (mock *IdempotencyStoreMock) ReserveCalls() []struct {
+	Ctx context.Context
+	Rec *entity.IdempotencyRecord
+	TTL time.Duration
+} {
+	var calls []struct {
+		Ctx context.Context
+		Rec *entity.IdempotencyRecord
+		TTL time.Duration
+	}
+	mock.lockReserve.RLock()
+	calls = mock.calls.Reserve
+	mock.lockReserve.RUnlock()
+	return calls
+}
+
+// Ensure, that RateLimiterMock does implement RateLimiter.
+// If this is not the case, regenerate this file with moq.
+var _ RateLimiter = &RateLimiterMock{}
+
+// RateLimiterMock is a mock implementation of RateLimiter.
+//
+//	func TestSomethingThatUsesRateLimiter(t *testing.T) {
+//
+//		// make and configure a mocked RateLimiter
+//		mockedRateLimiter := &RateLimiterMock{
+//			TakeFunc: func(ctx context.Context, key string, limit entity.RateLimit) (entity.RateLimitResult, error) {
+//				panic("mock out the Take method")
+//			},
+//		}
+//
+//		// use mockedRateLimiter in code that requires RateLimiter
+//		// and then make assertions.
+//
+//	}
+type RateLimiterMock struct {
+	// TakeFunc mocks the Take method.
+	TakeFunc func(ctx context.Context, key string, limit entity.RateLimit) (entity.RateLimitResult, error)
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// Take holds details about calls to the Take method.
+		Take []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Key is the key argument value.
+			Key string
+			// Limit is the limit argument value.
+			Limit entity.RateLimit
+		}
+	}
+	lockTake sync.RWMutex
+}
+
+// Take calls TakeFunc.
+func (mock *RateLimiterMock) Take(ctx context.Context, key string, limit entity.RateLimit) (entity.RateLimitResult, error) {
+	if mock.TakeFunc == nil {
+		panic("RateLimiterMock.TakeFunc: method is nil but RateLimiter.Take was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		Key   string
+		Limit entity.RateLimit
+	}{
+		Ctx:   ctx,
+		Key:   key,
+		Limit: limit,
+	}
+	mock.lockTake.Lock()
+	mock.calls.Take = append(mock.calls.Take, callInfo)
+	mock.lockTake.Unlock()
+	return mock.TakeFunc(ctx, key, limit)
+}
+
+// TakeCalls gets all the calls that were made to Take.
+// Check the length with:
+//
+//	len(mockedRateLimiter.TakeCalls())
+func (mock *RateLimiterMock) TakeCalls() []struct {
+	Ctx   context.Context
+	Key   string
+	Limit entity.RateLimit
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Key   string
+		Limit entity.RateLimit
+	}
+	mock.lockTake.RLock()
+	calls = mock.calls.Take
+	mock.lockTake.RUnlock()
+	return calls
+}
+
+// Ensure, that RequestObserverMock does implement RequestObserver.
+// If this is not the case, regenerate this file with moq.
+var _ RequestObserver = &RequestObserverMock{}
+
+// RequestObserverMock is a mock implementation of RequestObserver.
+//
+//	func TestSomethingThatUsesRequestObserver(t *testing.T) {
+//
+//		// make and configure a mocked RequestObserver
+//		mockedRequestObserver := &RequestObserverMock{
+//			ObserveRequestFunc: func(method string, route string, status int, d time.Duration)  {
+//				panic("mock out the ObserveRequest method")
+//			},
+//		}
+//
+//		// use mockedRequestObserver in code that requires RequestObserver
+//		// and then make assertions.
+//
+//	}
+type RequestObserverMock struct {
+	// ObserveRequestFunc mocks the ObserveRequest method.
+	ObserveRequestFunc func(method string, route string, status int, d time.Duration)
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// ObserveRequest holds details about calls to the ObserveRequest method.
+		ObserveRequest []struct {
+			// Method is the method argument value.
+			Method string
+			// Route is the route argument value.
+			Route string
+			// Status is the status argument value.
+			Status int
+			// D is the d argument value.
+			D time.Duration
+		}
+	}
+	lockObserveRequest sync.RWMutex
+}
+
+// ObserveRequest calls ObserveRequestFunc.
+func (mock *RequestObserverMock) ObserveRequest(method string, route string, status int, d time.Duration) {
+	if mock.ObserveRequestFunc == nil {
+		panic("RequestObserverMock.ObserveRequestFunc: method is nil but RequestObserver.ObserveRequest was just called")
+	}
+	callInfo := struct {
+		Method string
+		Route  string
+		Status int
+		D      time.Duration
+	}{
+		Method: method,
+		Route:  route,
+		Status: status,
+		D:      d,
+	}
+	mock.lockObserveRequest.Lock()
+	mock.calls.ObserveRequest = append(mock.calls.ObserveRequest, callInfo)
+	mock.lockObserveRequest.Unlock()
+	mock.ObserveRequestFunc(method, route, status, d)
+}
+
+// ObserveRequestCalls gets all the calls that were made to ObserveRequest.
+// Check the length with:
+//
+//	len(mockedRequestObserver.ObserveRequestCalls())
+func (mock *RequestObserverMock) ObserveRequestCalls() []struct {
+	Method string
+	Route  string
+	Status int
+	D      time.Duration
+} {
+	var calls []struct {
+		Method string
+		Route  string
+		Status int
+		D      time.Duration
+	}
+	mock.lockObserveRequest.RLock()
+	calls = mock.calls.ObserveRequest
+	mock.lockObserveRequest.RUnlock()
+	return calls
+}
+
+// Ensure, that HealthCheckerMock does implement HealthChecker.
+// If this is not the case, regenerate this file with moq.
+var _ HealthChecker = &HealthCheckerMock{}
+
+// HealthCheckerMock is a mock implementation of HealthChecker.
+//
+//	func TestSomethingThatUsesHealthChecker(t *testing.T) {
+//
+//		// make and configure a mocked HealthChecker
+//		mockedHealthChecker := &HealthCheckerMock{
+//			CheckFunc: func(ctx context.Context) health.Report {
+//				panic("mock out the Check method")
+//			},
+//		}
+//
+//		// use mockedHealthChecker in code that requires HealthChecker
+//		// and then make assertions.
+//
+//	}
+type HealthCheckerMock struct {
+	// CheckFunc mocks the Check method.
+	CheckFunc func(ctx context.Context) health.Report
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// Check holds details about calls to the Check method.
+		Check []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
+	}
+	lockCheck sync.RWMutex
+}
+
+// Check calls CheckFunc.
+func (mock *HealthCheckerMock) Check(ctx context.Context) health.Report {
+	if mock.CheckFunc == nil {
+		panic("HealthCheckerMock.CheckFunc: method is nil but HealthChecker.Check was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	mock.lockCheck.Lock()
+	mock.calls.Check = append(mock.calls.Check, callInfo)
+	mock.lockCheck.Unlock()
+	return mock.CheckFunc(ctx)
+}
+
+// CheckCalls gets all the calls that were made to Check.
+// Check the length with:
+//
+//	len(mockedHealthChecker.CheckCalls())
+func (mock *HealthCheckerMock) CheckCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	mock.lockCheck.RLock()
+	calls = mock.calls.Check
+	mock.lockCheck.RUnlock()
+	return calls
+}
+
+// Ensure, that ExportArticlesServiceMock does implement ExportArticlesService.
+// If this is not the case, regenerate this file with moq.
+var _ ExportArticlesService = &ExportArticlesServiceMock{}
+
+// ExportArticlesServiceMock is a mock implementation of ExportArticlesService.
+//
+//	func TestSomethingThatUsesExportArticlesService(t *testing.T) {
+//
+//		// make and configure a mocked ExportArticlesService
+//		mockedExportArticlesService := &ExportArticlesServiceMock{
+//			ExportArticlesFunc: func(ctx context.Context, layout markdown.Layout, dst markdown.Dest) (*markdown.Report, error) {
+//				panic("mock out the ExportArticles method")
+//			},
+//		}
+//
+//		// use mockedExportArticlesService in code that requires ExportArticlesService
+//		// and then make assertions.
+//
+//	}
+type ExportArticlesServiceMock struct {
+	// ExportArticlesFunc mocks the ExportArticles method.
+	ExportArticlesFunc func(ctx context.Context, layout markdown.Layout, dst markdown.Dest) (*markdown.Report, error)
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// ExportArticles holds details about calls to the ExportArticles method.
+		ExportArticles []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Layout is the layout argument value.
+			Layout markdown.Layout
+			// Dst is the dst argument value.
+			Dst markdown.Dest
+		}
+	}
+	lockExportArticles sync.RWMutex
+}
+
+// ExportArticles calls ExportArticlesFunc.
+func (mock *ExportArticlesServiceMock) ExportArticles(ctx context.Context, layout markdown.Layout, dst markdown.Dest) (*markdown.Report, error) {
+	if mock.ExportArticlesFunc == nil {
+		panic("ExportArticlesServiceMock.ExportArticlesFunc: method is nil but ExportArticlesService.ExportArticles was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Layout markdown.Layout
+		Dst    markdown.Dest
+	}{
+		Ctx:    ctx,
+		Layout: layout,
+		Dst:    dst,
+	}
+	mock.lockExportArticles.Lock()
+	mock.calls.ExportArticles = append(mock.calls.ExportArticles, callInfo)
+	mock.lockExportArticles.Unlock()
+	return mock.ExportArticlesFunc(ctx, layout, dst)
+}
+
+// ExportArticlesCalls gets all the calls that were made to ExportArticles.
+// Check the length with:
+//
+//	len(mockedExportArticlesService.ExportArticlesCalls())
+func (mock *ExportArticlesServiceMock) ExportArticlesCalls() []struct {
+	Ctx    context.Context
+	Layout markdown.Layout
+	Dst    markdown.Dest
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Layout markdown.Layout
+		Dst    markdown.Dest
+	}
+	mock.lockExportArticles.RLock()
+	calls = mock.calls.ExportArticles
+	mock.lockExportArticles.RUnlock()
+	return calls
+}
+
+// Ensure, that MediaOpenerMock does implement MediaOpener.
+// If this is not the case, regenerate this file with moq.
+var _ MediaOpener = &MediaOpenerMock{}
+
+// MediaOpenerMock is a mock implementation of MediaOpener.
+//
+//	func TestSomethingThatUsesMediaOpener(t *testing.T) {
+//
+//		// make and configure a mocked MediaOpener
+//		mockedMediaOpener := &MediaOpenerMock{
+//			OpenFunc: func(ctx context.Context, key string) (io.ReadCloser, error) {
+//				panic("mock out the Open method")
+//			},
+//		}
+//
+//		// use mockedMediaOpener in code that requires MediaOpener
+//		// and then make assertions.
+//
+//	}
+type MediaOpenerMock struct {
+	// OpenFunc mocks the Open method.
+	OpenFunc func(ctx context.Context, key string) (io.ReadCloser, error)
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// Open holds details about calls to the Open method.
+		Open []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Key is the key argument value.
+			Key string
+		}
+	}
+	lockOpen sync.RWMutex
+}
+
+// Open calls OpenFunc.
+func (mock *MediaOpenerMock) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	if mock.OpenFunc == nil {
+		panic("MediaOpenerMock.OpenFunc: method is nil but MediaOpener.Open was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Key string
+	}{
+		Ctx: ctx,
+		Key: key,
+	}
+	mock.lockOpen.Lock()
+	mock.calls.Open = append(mock.calls.Open, callInfo)
+	mock.lockOpen.Unlock()
+	return mock.OpenFunc(ctx, key)
+}
+
+// OpenCalls gets all the calls that were made to Open.
+// Check the length with:
+//
+//	len(mockedMediaOpener.OpenCalls())
+func (mock *MediaOpenerMock) OpenCalls() []struct {
+	Ctx context.Context
+	Key string
+} {
+	var calls []struct {
+		Ctx context.Context
+		Key string
+	}
+	mock.lockOpen.RLock()
+	calls = mock.calls.Open
+	mock.lockOpen.RUnlock()
+	return calls
+}
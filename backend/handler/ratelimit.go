@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/iinuma0710/react-go-blog/backend/entity"
+)
+
+// 呼び出し元ごとにトークンバケット方式でリクエスト数を制限するミドルウェア
+// name はルートのグループ名で、グループごとに別のバケットを使う
+// 制限値は設定の再読み込みで変わるため、リクエストごとに limit から取得する
+func RateLimitMiddleware(rl RateLimiter, name string, limit func() entity.RateLimit) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+
+			// 補充レートかバースト数が 0 以下の場合は制限しない
+			limit := limit()
+			if limit.Rate <= 0 || limit.Burst <= 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			res, err := rl.Take(ctx, name+":"+callerIdentity(r), limit)
+			if err != nil {
+				// バケットの保存先の障害でサービス全体を止めないよう、制限せずに通す
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Set("RateLimit-Limit", fmt.Sprint(res.Limit))
+			w.Header().Set("RateLimit-Remaining", fmt.Sprint(res.Remaining))
+			w.Header().Set("RateLimit-Reset", fmt.Sprint(ceilSeconds(res.Reset)))
+			if !res.Allowed {
+				w.Header().Set("Retry-After", fmt.Sprint(ceilSeconds(res.RetryAfter)))
+				RespondJSON(ctx, w, &ErrResponse{
+					Message: http.StatusText(http.StatusTooManyRequests),
+				}, http.StatusTooManyRequests)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func ceilSeconds(d time.Duration) int64 {
+	return int64(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/iinuma0710/react-go-blog/backend/entity"
+)
+
+func TestRateLimitMiddleware(t *testing.T) {
+	t.Parallel()
+
+	type want struct {
+		status     int
+		remaining  string
+		retryAfter string
+		called     bool
+	}
+
+	tests := map[string]struct {
+		disabled bool
+		result   entity.RateLimitResult
+		err      error
+		want     want
+	}{
+		"allowed": {
+			result: entity.RateLimitResult{Allowed: true, Limit: 5, Remaining: 4, Reset: 1500 * time.Millisecond},
+			want:   want{status: http.StatusOK, remaining: "4", called: true},
+		},
+		"limited": {
+			result: entity.RateLimitResult{Limit: 5, Remaining: 0, RetryAfter: 200 * time.Millisecond, Reset: 5 * time.Second},
+			want:   want{status: http.StatusTooManyRequests, remaining: "0", retryAfter: "1"},
+		},
+		"disabled": {
+			disabled: true,
+			want:     want{status: http.StatusOK, called: true},
+		},
+		"storeError": {
+			err:  errors.New("error from mock"),
+			want: want{status: http.StatusOK, called: true},
+		},
+	}
+
+	for n, tt := range tests {
+		tt := tt
+		t.Run(n, func(t *testing.T) {
+			t.Parallel()
+
+			moq := &RateLimiterMock{
+				TakeFunc: func(ctx context.Context, key string, limit entity.RateLimit) (entity.RateLimitResult, error) {
+					if tt.disabled {
+						t.Error("Take must not be called when rate limiting is disabled")
+					}
+					if key != "write:ip:192.0.2.1" {
+						t.Errorf("unexpected key %q", key)
+					}
+					return tt.result, tt.err
+				},
+			}
+			called := false
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				called = true
+			})
+			sut := RateLimitMiddleware(moq, "write", func() entity.RateLimit {
+				if tt.disabled {
+					return entity.RateLimit{}
+				}
+				return entity.RateLimit{Rate: 1, Burst: 5}
+			})(next)
+
+			w := httptest.NewRecorder()
+			sut.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/articles", nil))
+
+			rsp := w.Result()
+			if rsp.StatusCode != tt.want.status {
+				t.Errorf("want status %d, but got %d", tt.want.status, rsp.StatusCode)
+			}
+			if got := rsp.Header.Get("RateLimit-Remaining"); got != tt.want.remaining {
+				t.Errorf("want RateLimit-Remaining %q, but got %q", tt.want.remaining, got)
+			}
+			if got := rsp.Header.Get("Retry-After"); got != tt.want.retryAfter {
+				t.Errorf("want Retry-After %q, but got %q", tt.want.retryAfter, got)
+			}
+			if called != tt.want.called {
+				t.Errorf("want called %v, but got %v", tt.want.called, called)
+			}
+		})
+	}
+}
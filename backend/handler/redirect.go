@@ -0,0 +1,27 @@
+package handler
+
+import (
+	"net"
+	"net/http"
+	"strconv"
+)
+
+// HTTP のリクエストを同じホストの HTTPS のポートへリダイレクトするハンドラを返す
+// リダイレクト後もメソッドとボディが変わらないよう 308 を返す
+func RedirectToHTTPS(port int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(r.Host); err == nil {
+			host = h
+		}
+		if port != 443 {
+			host = net.JoinHostPort(host, strconv.Itoa(port))
+		} else if ip := net.ParseIP(host); ip != nil && ip.To4() == nil {
+			host = "[" + host + "]"
+		}
+		u := *r.URL
+		u.Scheme = "https"
+		u.Host = host
+		http.Redirect(w, r, u.String(), http.StatusPermanentRedirect)
+	})
+}
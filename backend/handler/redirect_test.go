@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRedirectToHTTPS(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		port int
+		host string
+		url  string
+		want string
+	}{
+		"defaultPort": {
+			port: 443,
+			host: "blog.example.com:80",
+			url:  "/articles?page=2",
+			want: "https://blog.example.com/articles?page=2",
+		},
+		"customPort": {
+			port: 8443,
+			host: "blog.example.com",
+			url:  "/articles/1",
+			want: "https://blog.example.com:8443/articles/1",
+		},
+		"ipv6": {
+			port: 443,
+			host: "[::1]:8080",
+			url:  "/",
+			want: "https://[::1]/",
+		},
+	}
+
+	for n, tt := range tests {
+		tt := tt
+		t.Run(n, func(t *testing.T) {
+			t.Parallel()
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, tt.url, nil)
+			r.Host = tt.host
+			RedirectToHTTPS(tt.port).ServeHTTP(w, r)
+
+			if w.Code != http.StatusPermanentRedirect {
+				t.Errorf("want status %d, but got %d", http.StatusPermanentRedirect, w.Code)
+			}
+			if got := w.Header().Get("Location"); got != tt.want {
+				t.Errorf("want %q, but got %q", tt.want, got)
+			}
+		})
+	}
+}
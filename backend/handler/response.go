@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+
+	"github.com/iinuma0710/react-go-blog/backend/logger"
 )
 
 type ErrResponse struct {
@@ -15,16 +17,22 @@ type ErrResponse struct {
 func RespondJSON(ctx context.Context, w http.ResponseWriter, body any, status int) {
 	w.Header().Set("Content-type", "application/json; charset=utf-8")
 
+	// サーバ側のエラーはリクエスト ID と一緒に原因を記録しておく
+	if er, ok := body.(*ErrResponse); ok && status >= http.StatusInternalServerError {
+		logger.FromContext(ctx).Error("server error", "status", status, "message", er.Message)
+	}
+
 	// レスポンスボディを JSON 形式に変換
 	bodyBytes, err := json.Marshal(body)
 	if err != nil {
+		logger.FromContext(ctx).Error("failed to marshal response", "error", err)
 		// 変換に失敗したらエラーメッセージを JSON に詰め込んでレスポンスする
 		w.WriteHeader(http.StatusInternalServerError)
 		rsp := ErrResponse{
 			Message: http.StatusText(http.StatusInternalServerError),
 		}
 		if err := json.NewEncoder(w).Encode(rsp); err != nil {
-			fmt.Printf("write error response error: %v", err)
+			logger.FromContext(ctx).Error("write error response error", "error", err)
 		}
 		return
 	}
@@ -32,6 +40,6 @@ func RespondJSON(ctx context.Context, w http.ResponseWriter, body any, status in
 	// ステータスコードと一緒に、変換した JSON をレスポンスに入れて返す
 	w.WriteHeader(status)
 	if _, err := fmt.Fprintf(w, "%s", bodyBytes); err != nil {
-		fmt.Printf("write response error: %v", err)
+		logger.FromContext(ctx).Error("write response error", "error", err)
 	}
 }
@@ -2,11 +2,15 @@ package handler
 
 import (
 	"context"
+	"io"
+	"time"
 
 	"github.com/iinuma0710/react-go-blog/backend/entity"
+	"github.com/iinuma0710/react-go-blog/backend/health"
+	"github.com/iinuma0710/react-go-blog/backend/markdown"
 )
 
-//go:generate go run github.com/matryer/moq -out moq_test.go . ListArticlesService AddArticleService
+//go:generate go run github.com/matryer/moq -out moq_test.go . ListArticlesService AddArticleService GetArticleService UpdateArticleService IdempotencyStore RateLimiter RequestObserver HealthChecker ExportArticlesService MediaOpener
 type ListArticlesService interface {
 	ListArticles(ctx context.Context) (entity.Articles, error)
 }
@@ -14,3 +18,38 @@ type ListArticlesService interface {
 type AddArticleService interface {
 	AddArticle(ctx context.Context, title string) (*entity.Article, error)
 }
+
+type GetArticleService interface {
+	GetArticle(ctx context.Context, id entity.ArticleID) (*entity.Article, error)
+}
+
+type UpdateArticleService interface {
+	UpdateArticle(ctx context.Context, id entity.ArticleID, version int64, title string, status entity.ArticleStatus) (*entity.Article, error)
+}
+
+type ExportArticlesService interface {
+	ExportArticles(ctx context.Context, layout markdown.Layout, dst markdown.Dest) (*markdown.Report, error)
+}
+
+type MediaOpener interface {
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+}
+
+type IdempotencyStore interface {
+	Reserve(ctx context.Context, rec *entity.IdempotencyRecord, ttl time.Duration) error
+	Get(ctx context.Context, key string) (*entity.IdempotencyRecord, error)
+	Complete(ctx context.Context, rec *entity.IdempotencyRecord) error
+	Delete(ctx context.Context, key string) error
+}
+
+type RateLimiter interface {
+	Take(ctx context.Context, key string, limit entity.RateLimit) (entity.RateLimitResult, error)
+}
+
+type RequestObserver interface {
+	ObserveRequest(method, route string, status int, d time.Duration)
+}
+
+type HealthChecker interface {
+	Check(ctx context.Context) health.Report
+}
@@ -0,0 +1,20 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/iinuma0710/react-go-blog/backend/store"
+)
+
+// リクエストごとに、書き込んだ内容をその後の読み出しで必ず読めるようにするミドルウェア
+// 書き込み前の読み出しはレプリカで実行するが、GET と HEAD 以外のリクエストでは
+// 読み出した内容をもとに書き込むので、最初からプライマリで読み出す
+func ReadYourWritesMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := store.WithSession(r.Context())
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			ctx = store.WithPrimary(ctx)
+		}
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
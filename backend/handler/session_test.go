@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/iinuma0710/react-go-blog/backend/store"
+	"github.com/jmoiron/sqlx"
+)
+
+func TestReadYourWritesMiddleware(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		method      string
+		write       bool
+		wantPrimary bool
+	}{
+		"get":           {method: http.MethodGet},
+		"post":          {method: http.MethodPost, wantPrimary: true},
+		"getAfterWrite": {method: http.MethodGet, write: true, wantPrimary: true},
+	}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			t.Parallel()
+
+			pdb, pmock, err := sqlmock.New()
+			if err != nil {
+				t.Fatal(err)
+			}
+			t.Cleanup(func() { pdb.Close() })
+			rdb, rmock, err := sqlmock.New()
+			if err != nil {
+				t.Fatal(err)
+			}
+			t.Cleanup(func() { rdb.Close() })
+
+			// 起動直後の確認で、レプリカを振り分け先にしておく
+			rmock.ExpectQuery(`SHOW REPLICA STATUS`).WillReturnRows(sqlmock.NewRows([]string{"Seconds_Behind_Source"}))
+			if tt.write {
+				pmock.ExpectExec(`UPDATE article`).WillReturnResult(sqlmock.NewResult(0, 1))
+			}
+			want := rmock
+			if tt.wantPrimary {
+				want = pmock
+			}
+			want.ExpectQuery(`SELECT`).WillReturnRows(sqlmock.NewRows([]string{"n"}).AddRow(1))
+
+			c := &store.Cluster{
+				Primary:  sqlx.NewDb(pdb, store.DriverMySQL),
+				Replicas: []*store.Replica{{DB: sqlx.NewDb(rdb, store.DriverMySQL), Name: "replica"}},
+			}
+			c.CheckReplicas(context.Background(), time.Second)
+
+			h := ReadYourWritesMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.write {
+					if _, err := c.ExecContext(r.Context(), "UPDATE article SET title = ?", "title"); err != nil {
+						t.Fatal(err)
+					}
+				}
+				var n int
+				if err := c.GetContext(r.Context(), &n, "SELECT 1 AS n"); err != nil {
+					t.Fatal(err)
+				}
+			}))
+			h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(tt.method, "/articles", nil))
+
+			for _, mock := range []sqlmock.Sqlmock{pmock, rmock} {
+				if err := mock.ExpectationsWereMet(); err != nil {
+					t.Error(err)
+				}
+			}
+		})
+	}
+}
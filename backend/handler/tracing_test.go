@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracingMiddleware(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	mux := chi.NewRouter()
+	mux.Use(TracingMiddleware)
+	mux.Get("/articles/{id}", func(w http.ResponseWriter, r *http.Request) {})
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	r := httptest.NewRequest(http.MethodGet, "/articles/1", nil)
+	r.Header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+	mux.ServeHTTP(httptest.NewRecorder(), r)
+
+	spans := sr.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("want 1 span, but got %d", len(spans))
+	}
+	if got := spans[0].Name(); got != "GET /articles/{id}" {
+		t.Errorf("want span name %q, but got %q", "GET /articles/{id}", got)
+	}
+	// 呼び出し元のトレースを引き継いでいる
+	if got := spans[0].SpanContext().TraceID().String(); got != traceID {
+		t.Errorf("want trace id %q, but got %q", traceID, got)
+	}
+}
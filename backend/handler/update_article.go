@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/iinuma0710/react-go-blog/backend/entity"
+	"github.com/iinuma0710/react-go-blog/backend/store"
+)
+
+type UpdateArticle struct {
+	Service   UpdateArticleService
+	Validator *validator.Validate
+}
+
+func (ua *UpdateArticle) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := articleIDParam(r)
+	if err != nil {
+		RespondJSON(ctx, w, &ErrResponse{
+			Message: err.Error(),
+		}, http.StatusBadRequest)
+		return
+	}
+
+	// 他の編集者の更新を上書きしないよう、If-Match ヘッダの指定を必須とする
+	version, err := parseIfMatch(r)
+	if err != nil {
+		status := http.StatusPreconditionFailed
+		if errors.Is(err, errNoIfMatch) {
+			status = http.StatusPreconditionRequired
+		}
+		RespondJSON(ctx, w, &ErrResponse{
+			Message: err.Error(),
+		}, status)
+		return
+	}
+
+	var b struct {
+		Title  string               `json:"title" validate:"required"`
+		Status entity.ArticleStatus `json:"status" validate:"required,oneof=draft published withdrawn"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&b); err != nil {
+		RespondJSON(ctx, w, &ErrResponse{
+			Message: err.Error(),
+		}, http.StatusBadRequest)
+		return
+	}
+	if err := ua.Validator.Struct(b); err != nil {
+		RespondJSON(ctx, w, &ErrResponse{
+			Message: err.Error(),
+		}, http.StatusBadRequest)
+		return
+	}
+
+	a, err := ua.Service.UpdateArticle(ctx, id, version, b.Title, b.Status)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, store.ErrNotFound):
+			status = http.StatusNotFound
+		case errors.Is(err, store.ErrVersionConflict):
+			status = http.StatusPreconditionFailed
+		}
+		RespondJSON(ctx, w, &ErrResponse{
+			Message: err.Error(),
+		}, status)
+		return
+	}
+
+	w.Header().Set("ETag", formatETag(a.Version))
+	RespondJSON(ctx, w, article{
+		ID:     a.ID,
+		Title:  a.Title,
+		Status: a.Status,
+	}, http.StatusOK)
+}
@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/iinuma0710/react-go-blog/backend/entity"
+	"github.com/iinuma0710/react-go-blog/backend/store"
+	"github.com/iinuma0710/react-go-blog/backend/testutil"
+)
+
+func TestUpdateArticle(t *testing.T) {
+	t.Parallel()
+
+	type want struct {
+		status  int
+		etag    string
+		rspFile string
+	}
+
+	tests := map[string]struct {
+		ifMatch string
+		want    want
+	}{
+		"ok": {
+			ifMatch: `"3"`,
+			want: want{
+				status:  http.StatusOK,
+				etag:    `"4"`,
+				rspFile: "testdata/update_article/ok_rsp.json.golden",
+			},
+		},
+		"noIfMatch": {
+			want: want{
+				status:  http.StatusPreconditionRequired,
+				rspFile: "testdata/update_article/no_if_match_rsp.json.golden",
+			},
+		},
+		"conflict": {
+			ifMatch: `"2"`,
+			want: want{
+				status:  http.StatusPreconditionFailed,
+				rspFile: "testdata/update_article/conflict_rsp.json.golden",
+			},
+		},
+	}
+
+	for n, tt := range tests {
+		tt := tt
+		t.Run(n, func(t *testing.T) {
+			t.Parallel()
+
+			w := httptest.NewRecorder()
+			r := withArticleID(httptest.NewRequest(
+				http.MethodPut,
+				"/articles/1",
+				bytes.NewReader(testutil.LoadFile(t, "testdata/update_article/ok_req.json.golden")),
+			), "1")
+			if tt.ifMatch != "" {
+				r.Header.Set("If-Match", tt.ifMatch)
+			}
+
+			// データベース上のバージョンは 3 とする
+			moq := &UpdateArticleServiceMock{}
+			moq.UpdateArticleFunc = func(
+				ctx context.Context, id entity.ArticleID, version int64, title string, status entity.ArticleStatus,
+			) (*entity.Article, error) {
+				if version != 3 {
+					return nil, fmt.Errorf("failed to update: %w", store.ErrVersionConflict)
+				}
+				return &entity.Article{ID: id, Title: title, Status: status, Version: version + 1}, nil
+			}
+			sut := UpdateArticle{Service: moq, Validator: validator.New()}
+			sut.ServeHTTP(w, r)
+
+			rsp := w.Result()
+			if got := rsp.Header.Get("ETag"); got != tt.want.etag {
+				t.Errorf("want ETag %q, but got %q", tt.want.etag, got)
+			}
+			testutil.AssertResponse(t, rsp, tt.want.status, testutil.LoadFile(t, tt.want.rspFile))
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	StatusOK          = "ok"
+	StatusUnavailable = "unavailable"
+)
+
+// シャットダウン中に readiness を失敗させるための疑似的なチェックの名前
+const shutdownCheckName = "shutdown"
+
+var ErrShuttingDown = errors.New("server is shutting down")
+
+// 個々のチェックの結果
+type CheckResult struct {
+	Status  string `json:"status"`
+	Latency string `json:"latency"`
+	Error   string `json:"error,omitempty"`
+}
+
+// 登録されたすべてのチェックの結果
+type Report struct {
+	Status string                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks"`
+}
+
+func (r Report) OK() bool {
+	return r.Status == StatusOK
+}
+
+type check struct {
+	name    string
+	timeout time.Duration
+	fn      func(ctx context.Context) error
+}
+
+// liveness と readiness のチェックをまとめたもの
+// liveness にはプロセスを再起動すべき異常を、readiness にはリクエストを受け付けられない状態を登録する
+type Checker struct {
+	Live  Registry
+	Ready Registry
+}
+
+// 名前付きのチェックを登録しておき、まとめて実行する
+type Registry struct {
+	mu       sync.RWMutex
+	checks   []check
+	draining atomic.Bool
+}
+
+// timeout 以内に fn が nil を返せば正常とみなすチェックを登録する
+func (r *Registry) Register(name string, timeout time.Duration, fn func(ctx context.Context) error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checks = append(r.checks, check{name: name, timeout: timeout, fn: fn})
+}
+
+// 以降のチェックを失敗させ、ロードバランサに振り分け対象から外してもらう
+func (r *Registry) Drain() {
+	r.draining.Store(true)
+}
+
+// 登録されたチェックを並行に実行する
+func (r *Registry) Check(ctx context.Context) Report {
+	r.mu.RLock()
+	checks := append([]check(nil), r.checks...)
+	r.mu.RUnlock()
+
+	results := make([]CheckResult, len(checks))
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = run(ctx, c)
+		}()
+	}
+	wg.Wait()
+
+	rep := Report{Status: StatusOK, Checks: make(map[string]CheckResult, len(checks)+1)}
+	for i, c := range checks {
+		rep.Checks[c.name] = results[i]
+		if results[i].Status != StatusOK {
+			rep.Status = StatusUnavailable
+		}
+	}
+	if r.draining.Load() {
+		rep.Status = StatusUnavailable
+		rep.Checks[shutdownCheckName] = CheckResult{
+			Status:  StatusUnavailable,
+			Latency: "0s",
+			Error:   ErrShuttingDown.Error(),
+		}
+	}
+	return rep
+}
+
+func run(ctx context.Context, c check) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	// コンテキストを見ないチェックでもタイムアウトで打ち切れるよう、別のゴルーチンで実行する
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() { done <- c.fn(ctx) }()
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	res := CheckResult{Status: StatusOK, Latency: time.Since(start).String()}
+	if err != nil {
+		res.Status = StatusUnavailable
+		res.Error = err.Error()
+	}
+	return res
+}
@@ -0,0 +1,90 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRegistry_Check(t *testing.T) {
+	t.Parallel()
+
+	ok := func(ctx context.Context) error { return nil }
+	fail := func(ctx context.Context) error { return errors.New("connection refused") }
+	// コンテキストを見ずに戻らないチェック
+	hang := func(ctx context.Context) error { select {} }
+
+	tests := map[string]struct {
+		checks map[string]func(ctx context.Context) error
+		drain  bool
+		want   string
+		failed []string
+	}{
+		"empty": {
+			want: StatusOK,
+		},
+		"ok": {
+			checks: map[string]func(ctx context.Context) error{"database": ok, "cache": ok},
+			want:   StatusOK,
+		},
+		"failing": {
+			checks: map[string]func(ctx context.Context) error{"database": fail, "cache": ok},
+			want:   StatusUnavailable,
+			failed: []string{"database"},
+		},
+		"timeout": {
+			checks: map[string]func(ctx context.Context) error{"database": hang},
+			want:   StatusUnavailable,
+			failed: []string{"database"},
+		},
+		"draining": {
+			checks: map[string]func(ctx context.Context) error{"database": ok},
+			drain:  true,
+			want:   StatusUnavailable,
+			failed: []string{shutdownCheckName},
+		},
+	}
+
+	for n, tt := range tests {
+		tt := tt
+		t.Run(n, func(t *testing.T) {
+			t.Parallel()
+
+			r := &Registry{}
+			for name, fn := range tt.checks {
+				r.Register(name, 50*time.Millisecond, fn)
+			}
+			if tt.drain {
+				r.Drain()
+			}
+
+			rep := r.Check(context.Background())
+			if rep.Status != tt.want {
+				t.Errorf("want status %q, but got %q", tt.want, rep.Status)
+			}
+			if rep.OK() != (tt.want == StatusOK) {
+				t.Errorf("OK() = %v for status %q", rep.OK(), rep.Status)
+			}
+			failed := map[string]bool{}
+			for _, name := range tt.failed {
+				failed[name] = true
+			}
+			for name, res := range rep.Checks {
+				if got := res.Status == StatusUnavailable; got != failed[name] {
+					t.Errorf("check %q: got status %q (error %q)", name, res.Status, res.Error)
+				}
+				if failed[name] && res.Error == "" {
+					t.Errorf("check %q: want error message", name)
+				}
+			}
+			want := len(tt.checks)
+			if tt.drain {
+				want++
+			}
+			if len(rep.Checks) != want {
+				t.Errorf("want %d checks, but got %d", want, len(rep.Checks))
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/iinuma0710/react-go-blog/backend/clock"
+	"github.com/iinuma0710/react-go-blog/backend/media"
+	"github.com/iinuma0710/react-go-blog/backend/service"
+	"github.com/iinuma0710/react-go-blog/backend/store"
+)
+
+func setupImport(fs *flag.FlagSet) func(ctx context.Context, e *cmdEnv, args []string) error {
+	dryRun := fs.Bool("dry-run", false, "書き込まずに、作成・更新される記事を表示する")
+	author := fs.String("author", "", "作成者を指定していない記事の作成者のユーザ名")
+	prefix := fs.String("prefix", "", "取り込み元のキーの先頭に付ける名前 (省略した場合はディレクトリ名)")
+
+	return func(ctx context.Context, e *cmdEnv, args []string) error {
+		if len(args) != 1 {
+			return errUsage
+		}
+		dir := args[0]
+		if fi, err := os.Stat(dir); err != nil || !fi.IsDir() {
+			return fmt.Errorf("not a directory: %s", dir)
+		}
+		// 同じディレクトリを別の場所から取り込んでも、同じキーになるようにする
+		if *prefix == "" {
+			abs, err := filepath.Abs(dir)
+			if err != nil {
+				return err
+			}
+			*prefix = filepath.Base(abs)
+		}
+
+		return runImport(ctx, e, *author, *dryRun, func(ctx context.Context, im *service.ImportArticles) (*service.ImportReport, error) {
+			return im.ImportMarkdown(ctx, os.DirFS(dir), *prefix)
+		})
+	}
+}
+
+func setupImportWordPress(fs *flag.FlagSet) func(ctx context.Context, e *cmdEnv, args []string) error {
+	dryRun := fs.Bool("dry-run", false, "書き込まずに、作成・更新される記事と作成されるユーザを表示する")
+	author := fs.String("author", "", "投稿者がユーザとして作成できない記事の作成者のユーザ名")
+
+	return func(ctx context.Context, e *cmdEnv, args []string) error {
+		if len(args) != 1 {
+			return errUsage
+		}
+		f, err := os.Open(args[0])
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		return runImport(ctx, e, *author, *dryRun, func(ctx context.Context, im *service.ImportArticles) (*service.ImportReport, error) {
+			// デッドロックなどで実行し直す場合に備えて、先頭から読み込む
+			if _, err := f.Seek(0, io.SeekStart); err != nil {
+				return nil, err
+			}
+			return im.ImportWXR(ctx, f)
+		})
+	}
+}
+
+// ドライランの結果を破棄するために、トランザクションをロールバックさせるエラー
+var errDryRun = errors.New("dry run")
+
+// 途中で失敗した場合に一部の記事だけが取り込まれないよう、1 つのトランザクションで実行する
+// 記事ごとの書き込みはセーブポイントで区切り、取り込めなかった記事の書き込みだけを取り消す
+// ドライランの場合はロールバックする
+func runImport(ctx context.Context, e *cmdEnv, author string, dryRun bool, run func(ctx context.Context, im *service.ImportArticles) (*service.ImportReport, error)) error {
+	ctx, _, err := e.setupLogger(ctx, e.stderr)
+	if err != nil {
+		return err
+	}
+	db, cleanup, err := e.openDB(ctx)
+	defer cleanup()
+	if err != nil {
+		return err
+	}
+
+	tr := store.NewTransactor(db)
+	var rep *service.ImportReport
+	err = tr.InTx(ctx, func(ctx context.Context, tx store.ExecQueryer) error {
+		r, err := run(ctx, &service.ImportArticles{
+			DB:            tx,
+			Repo:          &store.Repository{Clocker: clock.RealClocker{}},
+			Media:         media.Dir(e.cfg.MediaDir),
+			Tx:            tr,
+			DefaultAuthor: author,
+			DryRun:        dryRun,
+		})
+		if err != nil {
+			return err
+		}
+		rep = r
+		if dryRun {
+			return errDryRun
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errDryRun) {
+		return err
+	}
+	printImportReport(e.stdout, rep)
+	return nil
+}
+
+func printImportReport(w io.Writer, rep *service.ImportReport) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, res := range rep.Results {
+		id := "-"
+		if res.ArticleID != 0 {
+			id = fmt.Sprint(res.ArticleID)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", res.Action, id, res.Source, res.Title)
+		if res.Reason != "" {
+			fmt.Fprintf(tw, "\t\treason: %s\t\n", res.Reason)
+		}
+		if res.Err != nil {
+			fmt.Fprintf(tw, "\t\terror: %v\t\n", res.Err)
+		}
+		for _, warn := range res.Warnings {
+			fmt.Fprintf(tw, "\t\twarning: %s\t\n", warn)
+		}
+	}
+	tw.Flush()
+
+	for _, u := range rep.Users {
+		fmt.Fprintf(w, "user created: %s\n", u)
+	}
+	for _, u := range rep.SkippedAuthors {
+		fmt.Fprintf(w, "author skipped: %s (login is longer than 20 characters, articles are assigned to the default author)\n", u)
+	}
+
+	if rep.DryRun {
+		fmt.Fprint(w, "dry run: ")
+	}
+	for i, a := range service.ImportActions {
+		if i > 0 {
+			fmt.Fprint(w, ", ")
+		}
+		fmt.Fprintf(w, "%s %d", a, rep.Count(a))
+	}
+	fmt.Fprintln(w)
+}
@@ -0,0 +1,120 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/iinuma0710/react-go-blog/backend/logger"
+)
+
+// シャットダウン処理を実行する段階
+// 段階の順に実行し、同じ段階のフックは登録した順に実行する
+type Phase int
+
+const (
+	// readiness を失敗させ、ロードバランサに振り分け対象から外してもらう
+	PhaseReadiness Phase = iota
+	// 新しい接続の受付を止め、処理中のリクエストが終わるのを待つ
+	PhaseHTTP
+	// バックグラウンドで動いている処理を止める
+	PhaseWorkers
+	// データベースなどの接続を閉じる
+	PhaseResources
+	// シャットダウン中に記録したスパンなども含めて出力する
+	PhaseTelemetry
+)
+
+func (p Phase) String() string {
+	switch p {
+	case PhaseReadiness:
+		return "readiness"
+	case PhaseHTTP:
+		return "http"
+	case PhaseWorkers:
+		return "workers"
+	case PhaseResources:
+		return "resources"
+	case PhaseTelemetry:
+		return "telemetry"
+	}
+	return fmt.Sprintf("phase(%d)", int(p))
+}
+
+// 期限までにシャットダウン処理が終わらなかった
+var ErrTimeout = errors.New("shutdown deadline exceeded")
+
+type hook struct {
+	phase Phase
+	name  string
+	fn    func(ctx context.Context) error
+}
+
+// 終了時に実行する処理をまとめて管理する
+type Shutdown struct {
+	mu    sync.Mutex
+	hooks []hook
+	once  sync.Once
+	err   error
+}
+
+// phase の段階で実行する処理を登録する
+func (s *Shutdown) Add(phase Phase, name string, fn func(ctx context.Context) error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.hooks = append(s.hooks, hook{phase: phase, name: name, fn: fn})
+}
+
+// 登録された処理を順に実行する
+// 失敗した処理があっても残りの処理は続け、ctx の期限を過ぎた場合は残りを実行せずに ErrTimeout を返す
+// 2 回目以降の呼び出しでは何もせず、最初の呼び出しの結果を返す
+func (s *Shutdown) Run(ctx context.Context) error {
+	s.once.Do(func() {
+		s.err = s.run(ctx)
+	})
+	return s.err
+}
+
+func (s *Shutdown) run(ctx context.Context) error {
+	s.mu.Lock()
+	hooks := append([]hook(nil), s.hooks...)
+	s.mu.Unlock()
+	sort.SliceStable(hooks, func(i, j int) bool { return hooks[i].phase < hooks[j].phase })
+
+	l := logger.FromContext(ctx)
+	var errs []error
+	for _, h := range hooks {
+		start := time.Now()
+		// コンテキストを見ない処理でも期限で打ち切れるよう、別のゴルーチンで実行する
+		done := make(chan error, 1)
+		go func() { done <- h.fn(ctx) }()
+		select {
+		case err := <-done:
+			if err != nil {
+				l.Error("shutdown hook failed", "phase", h.phase, "hook", h.name, "error", err)
+				errs = append(errs, fmt.Errorf("%s: %w", h.name, err))
+				continue
+			}
+			l.Info("shutdown hook done", "phase", h.phase, "hook", h.name, "latency", time.Since(start))
+		case <-ctx.Done():
+			l.Error("shutdown deadline exceeded", "phase", h.phase, "hook", h.name)
+			return errors.Join(append(errs, fmt.Errorf("%w: while running %s", ErrTimeout, h.name))...)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ctx がキャンセルされるまで最大 d だけ待つ
+func Sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,69 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestShutdown_Run(t *testing.T) {
+	t.Parallel()
+
+	var got []string
+	record := func(name string, err error) func(ctx context.Context) error {
+		return func(ctx context.Context) error {
+			got = append(got, name)
+			return err
+		}
+	}
+	errClose := errors.New("close failed")
+
+	var s Shutdown
+	// 登録順ではなく段階の順に実行される
+	s.Add(PhaseTelemetry, "tracing", record("tracing", nil))
+	s.Add(PhaseResources, "database", record("database", errClose))
+	s.Add(PhaseHTTP, "http", record("http", nil))
+	s.Add(PhaseResources, "cache", record("cache", nil))
+	s.Add(PhaseReadiness, "readiness", record("readiness", nil))
+
+	err := s.Run(context.Background())
+	if !errors.Is(err, errClose) {
+		t.Errorf("want error %v, but got %v", errClose, err)
+	}
+	want := []string{"readiness", "http", "database", "cache", "tracing"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("want %v, but got %v", want, got)
+	}
+
+	// 2 回目の呼び出しではフックを実行しない
+	if err := s.Run(context.Background()); !errors.Is(err, errClose) {
+		t.Errorf("want error %v on second run, but got %v", errClose, err)
+	}
+	if len(got) != len(want) {
+		t.Errorf("hooks ran again: %v", got)
+	}
+}
+
+func TestShutdown_Run_Timeout(t *testing.T) {
+	t.Parallel()
+
+	var ran bool
+	var s Shutdown
+	// コンテキストを見ずに戻らない処理
+	s.Add(PhaseHTTP, "http", func(ctx context.Context) error { select {} })
+	s.Add(PhaseResources, "database", func(ctx context.Context) error {
+		ran = true
+		return nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := s.Run(ctx); !errors.Is(err, ErrTimeout) {
+		t.Errorf("want error %v, but got %v", ErrTimeout, err)
+	}
+	if ran {
+		t.Error("hooks after the deadline should not run")
+	}
+}
@@ -0,0 +1,65 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+
+	"github.com/iinuma0710/react-go-blog/backend/config"
+)
+
+type loggerKey struct{}
+
+// 設定に応じたロガーを作成する
+// 出力形式が指定されていない場合、本番環境では JSON 形式、それ以外ではテキスト形式にする
+// 出力レベルは返り値の LevelVar で実行中に変更できる
+func New(cfg *config.Config, w io.Writer) (*slog.Logger, *slog.LevelVar, error) {
+	level := &slog.LevelVar{}
+	if err := SetLevel(level, cfg.LogLevel); err != nil {
+		return nil, nil, err
+	}
+	opts := &slog.HandlerOptions{Level: level}
+
+	format := strings.ToLower(cfg.LogFormat)
+	if format == "" {
+		format = "text"
+		if cfg.BackendEnv == "prod" {
+			format = "json"
+		}
+	}
+
+	switch format {
+	case "json":
+		return slog.New(slog.NewJSONHandler(w, opts)), level, nil
+	case "text":
+		return slog.New(slog.NewTextHandler(w, opts)), level, nil
+	default:
+		return nil, nil, fmt.Errorf("invalid log format %q", cfg.LogFormat)
+	}
+}
+
+// 文字列で指定された出力レベルを lv に設定する
+func SetLevel(lv *slog.LevelVar, s string) error {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(s)); err != nil {
+		return fmt.Errorf("invalid log level %q: %w", s, err)
+	}
+	lv.Set(level)
+	return nil
+}
+
+// ロガーをコンテキストに格納する
+func WithContext(ctx context.Context, l *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, l)
+}
+
+// コンテキストに格納されたロガーを取り出す
+// 格納されていない場合はデフォルトのロガーを返す
+func FromContext(ctx context.Context) *slog.Logger {
+	if l, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok {
+		return l
+	}
+	return slog.Default()
+}
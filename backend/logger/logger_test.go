@@ -0,0 +1,91 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/iinuma0710/react-go-blog/backend/config"
+)
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		cfg      config.Config
+		wantJSON bool
+		wantErr  bool
+	}{
+		"dev":        {cfg: config.Config{BackendEnv: "dev", LogLevel: "info"}},
+		"prod":       {cfg: config.Config{BackendEnv: "prod", LogLevel: "info"}, wantJSON: true},
+		"explicit":   {cfg: config.Config{BackendEnv: "dev", LogLevel: "info", LogFormat: "json"}, wantJSON: true},
+		"badLevel":   {cfg: config.Config{LogLevel: "verbose"}, wantErr: true},
+		"badFormat":  {cfg: config.Config{LogLevel: "info", LogFormat: "xml"}, wantErr: true},
+		"debugLevel": {cfg: config.Config{LogLevel: "debug"}},
+	}
+
+	for n, tt := range tests {
+		tt := tt
+		t.Run(n, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			l, _, err := New(&tt.cfg, &buf)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("want error, but got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("want no error, but got %v", err)
+			}
+
+			l.Info("hello")
+			got := json.Valid(bytes.TrimSpace(buf.Bytes()))
+			if got != tt.wantJSON {
+				t.Errorf("want json %v, but got %q", tt.wantJSON, buf.String())
+			}
+		})
+	}
+}
+
+func TestSetLevel(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	l, lv, err := New(&config.Config{LogLevel: "info"}, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Debug("hidden")
+	if err := SetLevel(lv, "debug"); err != nil {
+		t.Fatal(err)
+	}
+	l.Debug("shown")
+	if err := SetLevel(lv, "verbose"); err == nil {
+		t.Error("want error for invalid level")
+	}
+
+	if got := buf.String(); strings.Contains(got, "hidden") || !strings.Contains(got, "shown") {
+		t.Errorf("level change is not applied: %q", got)
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	t.Parallel()
+
+	if got := FromContext(context.Background()); got != slog.Default() {
+		t.Error("want default logger")
+	}
+
+	var buf bytes.Buffer
+	l := slog.New(slog.NewTextHandler(&buf, nil)).With("request_id", "abc")
+	FromContext(WithContext(context.Background(), l)).Info("hello")
+	if !strings.Contains(buf.String(), "request_id=abc") {
+		t.Errorf("want request_id in log, but got %q", buf.String())
+	}
+}
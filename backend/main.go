@@ -2,47 +2,162 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"net"
+	"net/http"
 	"os"
 
 	"github.com/iinuma0710/react-go-blog/backend/config"
+	"github.com/iinuma0710/react-go-blog/backend/handler"
+	"github.com/iinuma0710/react-go-blog/backend/health"
+	"github.com/iinuma0710/react-go-blog/backend/lifecycle"
+	"github.com/iinuma0710/react-go-blog/backend/logger"
+	"github.com/iinuma0710/react-go-blog/backend/metrics"
+	"github.com/iinuma0710/react-go-blog/backend/tlsconfig"
+	"github.com/iinuma0710/react-go-blog/backend/tracing"
+	"golang.org/x/sync/errgroup"
 )
 
 func main() {
-	// run 関数を呼び出す
-	if err := run(context.Background()); err != nil {
-		fmt.Printf("failed to terminate server: %v", err)
+	if err := runCLI(context.Background(), os.Args[1:], os.Stdin, os.Stdout, os.Stderr); err != nil {
+		if errors.Is(err, errUsage) {
+			os.Exit(2)
+		}
+		slog.Error("failed to run command", "error", err)
 		os.Exit(1)
 	}
 }
 
-func run(ctx context.Context) error {
-	// 環境変数で指定された設定値を取得
-	cfg, err := config.New()
+// HTTP サーバを起動し、終了シグナルを受け取るまで待つ
+func serve(ctx context.Context, e *cmdEnv) error {
+	cfg := e.cfg
+
+	// 設定に応じたロガーを作成し、以降の処理ではコンテキスト経由で使う
+	ctx, level, err := e.setupLogger(ctx, e.stdout)
+	if err != nil {
+		return err
+	}
+	lg := logger.FromContext(ctx)
+
+	// SIGHUP で設定を読み込み直し、再起動せずに変更できる項目だけを入れ替える
+	cr := config.NewReloader(cfg, e.load)
+	cr.Subscribe(func(cfg *config.Config) {
+		// 設定の検証を通っているので失敗しない
+		_ = logger.SetLevel(level, cfg.LogLevel)
+	})
+
+	// 終了時の処理は段階ごとに登録しておき、シグナルを受け取ったときにまとめて実行する
+	// 起動の途中で失敗した場合も、それまでに登録した処理を実行する (Run は 1 回しか実行されない)
+	sd := &lifecycle.Shutdown{}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), cfg.ShutdownTimeout)
+		defer cancel()
+		_ = sd.Run(ctx)
+	}()
+
+	// 設定に応じたエクスポータでトレースの出力を開始する
+	shutdownTracing, err := tracing.Setup(ctx, cfg)
 	if err != nil {
 		return err
 	}
+	// 終了時にバッファに残っているスパンを出力する
+	sd.Add(lifecycle.PhaseTelemetry, "tracing", shutdownTracing)
+
+	// 証明書が指定されていれば HTTPS で配信する
+	var tc *tls.Config
+	scheme := "http"
+	if cfg.TLSCertFile != "" || cfg.TLSKeyFile != "" {
+		if tc, err = tlsconfig.New(cfg); err != nil {
+			return err
+		}
+		scheme = "https"
+	}
 
 	// 環境変数 BACKEND_PORT で設定されたポートのリッスンを開始
-	l, err := net.Listen("tcp", fmt.Sprintf(":%d", cfg.BckendPort))
+	l, err := net.Listen("tcp", fmt.Sprintf(":%d", cfg.BackendPort))
 	if err != nil {
-		log.Fatalf("failed to listen port %d: %v", cfg.BckendPort, err)
+		return fmt.Errorf("failed to listen port %d: %w", cfg.BackendPort, err)
 	}
 
 	// サーバの URL を表示
-	url := fmt.Sprintf("http://%s", l.Addr().String())
-	log.Printf("start with: %v", url)
+	url := fmt.Sprintf("%s://%s", scheme, l.Addr().String())
+	lg.Info("start server", "url", url, "h2c", tc == nil && cfg.H2C)
 
 	// ルーティングの設定を取得
-	mux, cleanup, err := NewMux(ctx, cfg)
-	defer cleanup()
+	m := metrics.New()
+	hc := &health.Checker{}
+	// 管理用のポートが指定されている場合のみ、管理用のエンドポイントを公開する
+	var admin *http.ServeMux
+	if cfg.MetricsPort != 0 {
+		admin = http.NewServeMux()
+	}
+	mux, err := NewMux(ctx, cr, m, hc, sd, admin)
 	if err != nil {
 		return err
 	}
 
+	// シャットダウンの開始時に readiness を失敗させ、ロードバランサが振り分け対象から外すのを待つ
+	sd.Add(lifecycle.PhaseReadiness, "readiness", func(ctx context.Context) error {
+		hc.Ready.Drain()
+		return lifecycle.Sleep(ctx, cfg.ShutdownDrainDelay)
+	})
+
 	// Server 型のインスタンスを生成し、HTTP サーバを起動
 	s := NewServer(l, mux)
-	return s.Run(ctx)
+	if tc != nil {
+		s.UseTLS(tc)
+	} else if cfg.H2C {
+		s.UseH2C()
+	}
+	s.OnShutdown(sd, cfg.ShutdownTimeout)
+	s.OnReload(func(ctx context.Context) {
+		res, err := cr.Reload()
+		if err != nil {
+			lg.Error("failed to reload config", "error", err)
+			return
+		}
+		for _, key := range res.Ignored {
+			lg.Warn("config change ignored until restart", "key", key)
+		}
+		lg.Info("config reloaded", "applied", res.Applied)
+	})
+
+	// 追加のサーバは HTTP の段階でまとめて停止する
+	var others []*Server
+	if cfg.MetricsPort != 0 {
+		// メトリクスを外部に公開しないよう、管理用のポートで別のサーバを起動する
+		al, err := net.Listen("tcp", fmt.Sprintf(":%d", cfg.MetricsPort))
+		if err != nil {
+			return fmt.Errorf("failed to listen metrics port %d: %w", cfg.MetricsPort, err)
+		}
+		admin.Handle("/metrics", m.Handler())
+		lg.Info("start admin server", "url", fmt.Sprintf("http://%s/metrics", al.Addr().String()))
+
+		as := NewServer(al, admin)
+		s.Attach("admin http", as)
+		others = append(others, as)
+	}
+	if cfg.HTTPRedirectPort != 0 {
+		// リバースプロキシを置かない構成向けに、HTTP でのアクセスを HTTPS へ誘導する
+		rl, err := net.Listen("tcp", fmt.Sprintf(":%d", cfg.HTTPRedirectPort))
+		if err != nil {
+			return fmt.Errorf("failed to listen redirect port %d: %w", cfg.HTTPRedirectPort, err)
+		}
+		lg.Info("start redirect server", "url", fmt.Sprintf("http://%s", rl.Addr().String()))
+
+		rs := NewServer(rl, handler.RedirectToHTTPS(cfg.BackendPort))
+		s.Attach("redirect http", rs)
+		others = append(others, rs)
+	}
+
+	eg, ctx := errgroup.WithContext(ctx)
+	eg.Go(func() error { return s.Run(ctx) })
+	for _, o := range others {
+		// Run が失敗して戻った場合も、追加のサーバを待ち続けないようにする
+		eg.Go(func() error { return o.ServeContext(ctx) })
+	}
+	return eg.Wait()
 }
@@ -24,7 +24,7 @@ func TestRun(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	eg, ctx := errgroup.WithContext(ctx)
 	eg.Go(func() error {
-		return run(ctx)
+		return runCLI(ctx, []string{"serve"}, nil, io.Discard, io.Discard)
 	})
 
 	// URL を作成してログ出力
@@ -0,0 +1,130 @@
+package markdown
+
+import (
+	"archive/zip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/iinuma0710/react-go-blog/backend/media"
+)
+
+// 書き出し先 (name はスラッシュ区切りの相対パス)
+type Dest interface {
+	Create(name string) (io.WriteCloser, error)
+}
+
+// ディレクトリに書き出す
+type DirDest string
+
+func (d DirDest) Create(name string) (io.WriteCloser, error) {
+	if !fs.ValidPath(name) {
+		return nil, fmt.Errorf("invalid path: %q", name)
+	}
+	p := filepath.Join(string(d), filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return nil, err
+	}
+	return os.Create(p)
+}
+
+// ZIP アーカイブに書き出す
+type ZipDest struct {
+	W *zip.Writer
+}
+
+func (z ZipDest) Create(name string) (io.WriteCloser, error) {
+	w, err := z.W.Create(name)
+	if err != nil {
+		return nil, err
+	}
+	return nopCloser{w}, nil
+}
+
+type nopCloser struct{ io.Writer }
+
+func (nopCloser) Close() error { return nil }
+
+type MediaOpener interface {
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+}
+
+type Report struct {
+	Articles int `json:"articles"`
+	Media    int `json:"media"`
+	// 本文から参照されているが、保存されていないファイルのキー
+	MissingMedia []string `json:"missing_media,omitempty"`
+}
+
+type Exporter struct {
+	Layout Layout
+	Media  MediaOpener
+}
+
+// 記事と、記事から参照しているファイルを書き出す
+func (e *Exporter) Export(ctx context.Context, posts []Post, dst Dest) (*Report, error) {
+	rep := &Report{}
+	copied := map[string]bool{}
+	for _, p := range posts {
+		b, err := Render(p, e.Layout)
+		if err != nil {
+			return rep, fmt.Errorf("failed to render article %d: %w", p.Article.ID, err)
+		}
+		if err := writeFile(dst, e.Layout.ArticlePath(Slug(p.Article)), func(w io.Writer) error {
+			_, err := w.Write(b)
+			return err
+		}); err != nil {
+			return rep, err
+		}
+		rep.Articles++
+
+		for _, key := range MediaKeys(p.Article.Content) {
+			if copied[key] {
+				continue
+			}
+			copied[key] = true
+			if err := e.copyMedia(ctx, dst, key); errors.Is(err, media.ErrNotFound) {
+				rep.MissingMedia = append(rep.MissingMedia, key)
+				continue
+			} else if err != nil {
+				return rep, err
+			}
+			rep.Media++
+		}
+	}
+	return rep, nil
+}
+
+func (e *Exporter) copyMedia(ctx context.Context, dst Dest, key string) error {
+	if !fs.ValidPath(key) {
+		return fmt.Errorf("%w: %s", media.ErrNotFound, key)
+	}
+	r, err := e.Media.Open(ctx, key)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	return writeFile(dst, e.Layout.MediaPath(key), func(w io.Writer) error {
+		_, err := io.Copy(w, r)
+		return err
+	})
+}
+
+func writeFile(dst Dest, name string, write func(w io.Writer) error) error {
+	w, err := dst.Create(name)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", name, err)
+	}
+	if err := write(w); err != nil {
+		w.Close()
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+package markdown
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+var (
+	blankLines = regexp.MustCompile(`\n[ \t]*\n(?:[ \t]*\n)+`)
+	emptyLines = regexp.MustCompile(`\n[ \t]*\n`)
+)
+
+// HTML を Markdown に変換する
+// 変換できない要素 (table など) は HTML のまま残し、その要素名を返す
+func FromHTML(s string) (string, []string, error) {
+	nodes, err := html.ParseFragment(strings.NewReader(s), &html.Node{Type: html.ElementNode, Data: "body", DataAtom: atom.Body})
+	if err != nil {
+		return "", nil, err
+	}
+	c := &htmlConverter{}
+	var sb strings.Builder
+	for _, n := range nodes {
+		sb.WriteString(c.node(n))
+	}
+	return tidy(sb.String()), c.raw, nil
+}
+
+type htmlConverter struct {
+	raw []string
+}
+
+func (c *htmlConverter) children(n *html.Node) string {
+	var sb strings.Builder
+	for ch := n.FirstChild; ch != nil; ch = ch.NextSibling {
+		sb.WriteString(c.node(ch))
+	}
+	return sb.String()
+}
+
+func (c *htmlConverter) node(n *html.Node) string {
+	switch n.Type {
+	case html.TextNode:
+		return n.Data
+	case html.ElementNode:
+	default:
+		// コメント (Gutenberg のブロックの区切りなど) は取り除く
+		return ""
+	}
+
+	switch n.DataAtom {
+	case atom.P, atom.Div, atom.Section, atom.Article, atom.Figure, atom.Figcaption:
+		return "\n\n" + c.children(n) + "\n\n"
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+		level := int(n.Data[1] - '0')
+		return "\n\n" + strings.Repeat("#", level) + " " + oneLine(c.children(n)) + "\n\n"
+	case atom.Br:
+		return "  \n"
+	case atom.Hr:
+		return "\n\n---\n\n"
+	case atom.Strong, atom.B:
+		return wrap(c.children(n), "**")
+	case atom.Em, atom.I:
+		return wrap(c.children(n), "*")
+	case atom.Del, atom.S:
+		return wrap(c.children(n), "~~")
+	case atom.Code:
+		return "`" + text(n) + "`"
+	case atom.Pre:
+		lang := ""
+		if code := n.FirstChild; code != nil && code.DataAtom == atom.Code && code.NextSibling == nil {
+			lang = strings.TrimPrefix(attr(code, "class"), "language-")
+		}
+		return "\n\n```" + lang + "\n" + strings.Trim(text(n), "\n") + "\n```\n\n"
+	case atom.A:
+		href := attr(n, "href")
+		if href == "" {
+			return c.children(n)
+		}
+		return "[" + oneLine(c.children(n)) + "](" + href + ")"
+	case atom.Img:
+		return "![" + attr(n, "alt") + "](" + attr(n, "src") + ")"
+	case atom.Ul, atom.Ol:
+		return "\n\n" + c.list(n) + "\n\n"
+	case atom.Blockquote:
+		lines := strings.Split(tidy(c.children(n)), "\n")
+		for i, l := range lines {
+			lines[i] = strings.TrimRight("> "+l, " ")
+		}
+		return "\n\n" + strings.Join(lines, "\n") + "\n\n"
+	case atom.Span, atom.U, atom.Small, atom.Sup, atom.Sub, atom.Mark:
+		return c.children(n)
+	}
+
+	// Markdown で表せない要素は HTML のまま残す
+	if !slices.Contains(c.raw, n.Data) {
+		c.raw = append(c.raw, n.Data)
+	}
+	var sb strings.Builder
+	if err := html.Render(&sb, n); err != nil {
+		return ""
+	}
+	return "\n\n" + sb.String() + "\n\n"
+}
+
+// 入れ子のリストは項目の先頭に合わせて字下げする
+func (c *htmlConverter) list(n *html.Node) string {
+	var items []string
+	i := 1
+	for li := n.FirstChild; li != nil; li = li.NextSibling {
+		if li.DataAtom != atom.Li {
+			continue
+		}
+		marker := "- "
+		if n.DataAtom == atom.Ol {
+			marker = fmt.Sprintf("%d. ", i)
+		}
+		i++
+		lines := strings.Split(emptyLines.ReplaceAllString(tidy(c.children(li)), "\n"), "\n")
+		for j := 1; j < len(lines); j++ {
+			if lines[j] != "" {
+				lines[j] = strings.Repeat(" ", len(marker)) + lines[j]
+			}
+		}
+		items = append(items, marker+strings.Join(lines, "\n"))
+	}
+	return strings.Join(items, "\n")
+}
+
+// 連続する空行を 1 行にまとめ、前後の空白を取り除く
+func tidy(s string) string {
+	return strings.TrimSpace(blankLines.ReplaceAllString(s, "\n\n"))
+}
+
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func wrap(s, mark string) string {
+	if strings.TrimSpace(s) == "" {
+		return s
+	}
+	return mark + s + mark
+}
+
+func text(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+	var sb strings.Builder
+	for ch := n.FirstChild; ch != nil; ch = ch.NextSibling {
+		sb.WriteString(text(ch))
+	}
+	return sb.String()
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
@@ -0,0 +1,24 @@
+package markdown
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/iinuma0710/react-go-blog/backend/testutil"
+)
+
+func TestFromHTML(t *testing.T) {
+	t.Parallel()
+
+	got, raw, err := FromHTML(string(testutil.LoadFile(t, "testdata/html/wordpress.html")))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := string(testutil.LoadFile(t, "testdata/html/wordpress.md.golden"))
+	if d := cmp.Diff(got+"\n", want); d != "" {
+		t.Errorf("differs: (-got +want)\n%s", d)
+	}
+	if d := cmp.Diff(raw, []string{"table"}); d != "" {
+		t.Errorf("differs: (-got +want)\n%s", d)
+	}
+}
@@ -0,0 +1,174 @@
+package markdown
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/iinuma0710/react-go-blog/backend/entity"
+	"gopkg.in/yaml.v3"
+)
+
+// 出力するディレクトリ構成
+type Layout string
+
+const (
+	// content/posts/<slug>.md と static/images/<key>
+	LayoutHugo Layout = "hugo"
+	// articles/<slug>.md と images/<key>
+	LayoutZenn Layout = "zenn"
+)
+
+var Layouts = []Layout{LayoutHugo, LayoutZenn}
+
+func ParseLayout(s string) (Layout, error) {
+	for _, l := range Layouts {
+		if string(l) == s {
+			return l, nil
+		}
+	}
+	return "", fmt.Errorf("unknown layout: %q (must be one of %v)", s, Layouts)
+}
+
+// 記事ファイルのパス
+func (l Layout) ArticlePath(slug string) string {
+	if l == LayoutZenn {
+		return "articles/" + slug + ".md"
+	}
+	return "content/posts/" + slug + ".md"
+}
+
+// 記事から参照するファイルのパス
+// どちらの構成でも /images/<key> で参照できる場所に置く
+func (l Layout) MediaPath(key string) string {
+	if l == LayoutZenn {
+		return "images/" + key
+	}
+	return "static/images/" + key
+}
+
+// 記事のファイルの先頭に置く YAML のメタデータ
+// Hugo と Zenn で解釈される項目に加えて、取り込み直すための項目を持つ
+type FrontMatter struct {
+	Title   string               `yaml:"title"`
+	Slug    string               `yaml:"slug,omitempty"`
+	Status  entity.ArticleStatus `yaml:"status,omitempty"`
+	Date    time.Time            `yaml:"date,omitempty"`
+	Lastmod time.Time            `yaml:"lastmod,omitempty"`
+	Author  string               `yaml:"author,omitempty"`
+	Tags    []string             `yaml:"tags,omitempty"`
+
+	// Hugo 向けの項目
+	Draft *bool `yaml:"draft,omitempty"`
+
+	// Zenn 向けの項目
+	Emoji       string   `yaml:"emoji,omitempty"`
+	Type        string   `yaml:"type,omitempty"`
+	Topics      []string `yaml:"topics,omitempty"`
+	Published   *bool    `yaml:"published,omitempty"`
+	PublishedAt string   `yaml:"published_at,omitempty"`
+}
+
+// 記事とファイルに書き出すための付随情報
+type Post struct {
+	Article *entity.Article
+	// 作成者のユーザ名 (不明な場合は空)
+	Author string
+	Tags   []string
+}
+
+// スラッグが未設定の記事は ID から作る
+// Zenn のスラッグの制約 (a-z0-9-_ の 12 文字以上) を満たす形にする
+func Slug(a *entity.Article) string {
+	if a.Slug != "" {
+		return a.Slug
+	}
+	return fmt.Sprintf("article-%06d", a.ID)
+}
+
+// 本文中の /media/<key> への参照 (Markdown のリンクと HTML の属性)
+var mediaRef = regexp.MustCompile(`([("'])/media/([^)\s"']+)`)
+
+// 本文から参照しているファイルのキーを重複なく取り出す
+func MediaKeys(content string) []string {
+	var keys []string
+	seen := map[string]bool{}
+	for _, m := range mediaRef.FindAllStringSubmatch(content, -1) {
+		if !seen[m[2]] {
+			seen[m[2]] = true
+			keys = append(keys, m[2])
+		}
+	}
+	return keys
+}
+
+// 記事をフロントマター付きの Markdown に変換する
+func Render(p Post, l Layout) ([]byte, error) {
+	a := p.Article
+	fm := FrontMatter{
+		Title:   a.Title,
+		Slug:    Slug(a),
+		Status:  a.Status,
+		Date:    a.CreatedAt.Truncate(time.Second),
+		Lastmod: a.UpdatedAt.Truncate(time.Second),
+		Author:  p.Author,
+		Tags:    p.Tags,
+	}
+	published := a.Status == entity.ArticlePublished
+	switch l {
+	case LayoutHugo:
+		draft := !published
+		fm.Draft = &draft
+	case LayoutZenn:
+		fm.Emoji, fm.Type, fm.Topics, fm.Published = "✏", "tech", zennTopics(p.Tags), &published
+		if published {
+			fm.PublishedAt = a.CreatedAt.Format("2006-01-02 15:04")
+		}
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("---\n")
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(fm); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	buf.WriteString("---\n\n")
+
+	// 参照先を書き出したファイルの場所に置き換える
+	buf.WriteString(mediaRef.ReplaceAllString(a.Content, "$1/images/$2"))
+	if a.Content != "" && !strings.HasSuffix(a.Content, "\n") {
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes(), nil
+}
+
+// Zenn のトピックは英小文字と数字のみで、5 つまで
+func zennTopics(tags []string) []string {
+	var topics []string
+	seen := map[string]bool{}
+	for _, t := range tags {
+		t = strings.Map(func(r rune) rune {
+			switch {
+			case 'a' <= r && r <= 'z', '0' <= r && r <= '9':
+				return r
+			case 'A' <= r && r <= 'Z':
+				return r + 'a' - 'A'
+			}
+			return -1
+		}, t)
+		if t == "" || seen[t] {
+			continue
+		}
+		seen[t] = true
+		if topics = append(topics, t); len(topics) == 5 {
+			break
+		}
+	}
+	return topics
+}
@@ -0,0 +1,104 @@
+package markdown
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/iinuma0710/react-go-blog/backend/entity"
+	"github.com/iinuma0710/react-go-blog/backend/media"
+	"github.com/iinuma0710/react-go-blog/backend/testutil"
+)
+
+var jst = time.FixedZone("Asia/Tokyo", 9*60*60)
+
+func testPost() Post {
+	return Post{
+		Article: &entity.Article{
+			ID:        1,
+			Title:     "GoでHTTPサーバを立てる",
+			Content:   "## はじめに\n\n![構成図](/media/2024/server.png)\n\n<img src=\"/media/logo.svg\" width=\"80\">",
+			Slug:      "go-http-server",
+			Status:    entity.ArticlePublished,
+			CreatedAt: time.Date(2024, 8, 8, 9, 30, 0, 123, jst),
+			UpdatedAt: time.Date(2024, 8, 10, 18, 0, 0, 0, jst),
+		},
+		Author: "taro",
+		Tags:   []string{"Go", "HTTP/2", "Next.js"},
+	}
+}
+
+func TestRender(t *testing.T) {
+	t.Parallel()
+
+	for _, l := range Layouts {
+		t.Run(string(l), func(t *testing.T) {
+			t.Parallel()
+
+			got, err := Render(testPost(), l)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			want := testutil.LoadFile(t, filepath.Join("testdata", string(l)+".md.golden"))
+			if d := cmp.Diff(string(got), string(want)); d != "" {
+				t.Errorf("differs: (-got +want)\n%s", d)
+			}
+		})
+	}
+}
+
+func TestSlug(t *testing.T) {
+	t.Parallel()
+
+	if got := Slug(&entity.Article{ID: 12}); got != "article-000012" {
+		t.Errorf("want article-000012, but got %q", got)
+	}
+	if got := Slug(&entity.Article{ID: 12, Slug: "hello"}); got != "hello" {
+		t.Errorf("want hello, but got %q", got)
+	}
+}
+
+// fstest.MapFS からファイルを読み出す
+type mapMedia fstest.MapFS
+
+func (m mapMedia) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	f, ok := m[key]
+	if !ok {
+		return nil, media.ErrNotFound
+	}
+	return io.NopCloser(bytes.NewReader(f.Data)), nil
+}
+
+func TestExporter_Export(t *testing.T) {
+	t.Parallel()
+
+	draft := testPost()
+	draft.Article = &entity.Article{ID: 2, Title: "下書き", Status: entity.ArticleDraft, Content: "![](/media/logo.svg)\n"}
+
+	dir := t.TempDir()
+	e := &Exporter{
+		Layout: LayoutHugo,
+		Media:  mapMedia{"logo.svg": {Data: []byte("<svg/>")}},
+	}
+	rep, err := e.Export(context.Background(), []Post{testPost(), draft}, DirDest(dir))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// 同じファイルは 1 回だけ書き出し、存在しないファイルは報告する
+	want := &Report{Articles: 2, Media: 1, MissingMedia: []string{"2024/server.png"}}
+	if d := cmp.Diff(rep, want); d != "" {
+		t.Errorf("differs: (-got +want)\n%s", d)
+	}
+	for _, name := range []string{"content/posts/go-http-server.md", "content/posts/article-000002.md", "static/images/logo.svg"} {
+		if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(name))); err != nil {
+			t.Errorf("want %s to be written: %v", name, err)
+		}
+	}
+}
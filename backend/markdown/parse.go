@@ -0,0 +1,156 @@
+package markdown
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/iinuma0710/react-go-blog/backend/entity"
+	"gopkg.in/yaml.v3"
+)
+
+// 取り込むために解析した Markdown のファイル
+type Document struct {
+	Title  string
+	Slug   string
+	Status entity.ArticleStatus
+	// ゼロ値の場合は日付が分からない
+	Date   time.Time
+	Author string
+	Tags   []string
+	Body   string
+}
+
+// memo/ のノートの 20240808_GoでHTTPサーバを立てる.md のようなファイル名
+var datedName = regexp.MustCompile(`^(\d{8})_(.+)\.md$`)
+
+// 先頭の見出し (# タイトル)
+var heading = regexp.MustCompile(`^#[ \t]+(.+?)[ \t#]*(?:\n|$)`)
+
+// フロントマターがあればその内容を使い、なければファイル名と本文の先頭の見出しから推測する
+// 日付はローカルタイムゾーンの 0 時として解釈する
+func Parse(name string, data []byte) (*Document, error) {
+	src := strings.ReplaceAll(string(data), "\r\n", "\n")
+	src = strings.TrimPrefix(src, "\ufeff")
+
+	var fm FrontMatter
+	hasFrontMatter := strings.HasPrefix(src, "---\n")
+	if hasFrontMatter {
+		rest := src[len("---\n"):]
+		end := strings.Index(rest, "\n---\n")
+		if end < 0 {
+			if !strings.HasSuffix(rest, "\n---") {
+				return nil, fmt.Errorf("%s: front matter is not closed", name)
+			}
+			end = len(rest) - len("\n---")
+		}
+		if err := yaml.Unmarshal([]byte(rest[:end]), &fm); err != nil {
+			return nil, fmt.Errorf("%s: invalid front matter: %w", name, err)
+		}
+		src = rest[min(end+len("\n---\n"), len(rest)):]
+	}
+	src = strings.TrimLeft(src, "\n")
+
+	doc := &Document{
+		Title:  fm.Title,
+		Slug:   fm.Slug,
+		Date:   fm.Date,
+		Author: fm.Author,
+		Tags:   fm.Tags,
+		Body:   src,
+	}
+	if len(doc.Tags) == 0 {
+		doc.Tags = fm.Topics
+	}
+	if doc.Date.IsZero() && fm.PublishedAt != "" {
+		if t, err := time.ParseInLocation("2006-01-02 15:04", fm.PublishedAt, time.Local); err == nil {
+			doc.Date = t
+		}
+	}
+
+	if m := datedName.FindStringSubmatch(name); m != nil {
+		if doc.Date.IsZero() {
+			t, err := time.ParseInLocation("20060102", m[1], time.Local)
+			if err != nil {
+				return nil, fmt.Errorf("%s: invalid date in file name: %w", name, err)
+			}
+			doc.Date = t
+		}
+		if doc.Title == "" && !hasFrontMatter {
+			doc.Title = strings.ReplaceAll(m[2], "_", " ")
+		}
+	}
+
+	// フロントマターのないノートでは、先頭の見出しがタイトルと重複するので取り除く
+	if !hasFrontMatter {
+		if m := heading.FindStringSubmatch(doc.Body); m != nil {
+			if doc.Title == "" {
+				doc.Title = m[1]
+			}
+			doc.Body = strings.TrimLeft(doc.Body[len(m[0]):], "\n")
+		}
+	}
+	if doc.Title == "" {
+		return nil, fmt.Errorf("%s: cannot determine title", name)
+	}
+
+	switch {
+	case fm.Status != "":
+		if !slices.Contains(entity.ArticleStatuses, fm.Status) {
+			return nil, fmt.Errorf("%s: unknown status: %q", name, fm.Status)
+		}
+		doc.Status = fm.Status
+	case fm.Draft != nil && *fm.Draft, fm.Published != nil && !*fm.Published:
+		doc.Status = entity.ArticleDraft
+	default:
+		doc.Status = entity.ArticlePublished
+	}
+	return doc, nil
+}
+
+// 本文中の画像の参照 (Markdown の画像と HTML の img 要素)
+var imageRefs = []*regexp.Regexp{
+	regexp.MustCompile(`!\[[^\]]*\]\(([^)\s]+)`),
+	regexp.MustCompile(`<img\s[^>]*?src=["']([^"']+)["']`),
+}
+
+// 本文から相対パスで参照している画像を重複なく取り出す
+func LocalImageRefs(body string) []string {
+	var refs []string
+	for _, re := range imageRefs {
+		for _, m := range re.FindAllStringSubmatch(body, -1) {
+			if isLocal(m[1]) && !slices.Contains(refs, m[1]) {
+				refs = append(refs, m[1])
+			}
+		}
+	}
+	return refs
+}
+
+func isLocal(ref string) bool {
+	return !strings.Contains(ref, "://") && !strings.HasPrefix(ref, "/") &&
+		!strings.HasPrefix(ref, "#") && !strings.HasPrefix(ref, "data:")
+}
+
+// 画像の参照を repl に従って置き換える
+func ReplaceImageRefs(body string, repl map[string]string) string {
+	for _, re := range imageRefs {
+		var buf bytes.Buffer
+		last := 0
+		for _, loc := range re.FindAllStringSubmatchIndex(body, -1) {
+			to, ok := repl[body[loc[2]:loc[3]]]
+			if !ok {
+				continue
+			}
+			buf.WriteString(body[last:loc[2]])
+			buf.WriteString(to)
+			last = loc[3]
+		}
+		buf.WriteString(body[last:])
+		body = buf.String()
+	}
+	return body
+}
@@ -0,0 +1,137 @@
+package markdown
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/iinuma0710/react-go-blog/backend/entity"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		name    string
+		data    string
+		want    *Document
+		wantErr bool
+	}{
+		"memo": {
+			name: "20240808_バックエンドの実装_番外編.md",
+			data: "# バックエンドの実装 番外編\r\nGo で HTTP サーバを立てます。\r\n",
+			want: &Document{
+				Title:  "バックエンドの実装 番外編",
+				Status: entity.ArticlePublished,
+				Date:   time.Date(2024, 8, 8, 0, 0, 0, 0, time.Local),
+				Body:   "Go で HTTP サーバを立てます。\n",
+			},
+		},
+		"headingOnly": {
+			name: "notes.md",
+			data: "# メモ\n\n本文",
+			want: &Document{Title: "メモ", Status: entity.ArticlePublished, Body: "本文"},
+		},
+		"hugo": {
+			name: "go-http-server.md",
+			data: "---\ntitle: GoでHTTPサーバを立てる\nslug: go-http-server\ndate: 2024-08-08T09:30:00+09:00\nauthor: taro\ntags: [Go]\ndraft: true\n---\n\n# 見出しは残す\n",
+			want: &Document{
+				Title:  "GoでHTTPサーバを立てる",
+				Slug:   "go-http-server",
+				Status: entity.ArticleDraft,
+				Date:   time.Date(2024, 8, 8, 9, 30, 0, 0, jst),
+				Author: "taro",
+				Tags:   []string{"Go"},
+				Body:   "# 見出しは残す\n",
+			},
+		},
+		"zenn": {
+			name: "20240101_ignored.md",
+			data: "---\ntitle: Zenn の記事\ntopics: [go]\npublished: true\npublished_at: 2024-08-08 09:30\n---\n本文",
+			want: &Document{
+				Title:  "Zenn の記事",
+				Status: entity.ArticlePublished,
+				Date:   time.Date(2024, 8, 8, 9, 30, 0, 0, time.Local),
+				Tags:   []string{"go"},
+				Body:   "本文",
+			},
+		},
+		"explicitStatus": {
+			name: "withdrawn.md",
+			data: "---\ntitle: 取り下げ\nstatus: withdrawn\ndraft: false\n---\n",
+			want: &Document{Title: "取り下げ", Status: entity.ArticleWithdrawn},
+		},
+		"unknownStatus": {
+			name:    "a.md",
+			data:    "---\ntitle: a\nstatus: archived\n---\n",
+			wantErr: true,
+		},
+		"unclosed": {
+			name:    "a.md",
+			data:    "---\ntitle: a\n",
+			wantErr: true,
+		},
+		"noTitle": {
+			name:    "a.md",
+			data:    "本文だけ",
+			wantErr: true,
+		},
+	}
+
+	for n, tt := range tests {
+		tt := tt
+		t.Run(n, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := Parse(tt.name, []byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("want error %v, but got %v", tt.wantErr, err)
+			}
+			if d := cmp.Diff(got, tt.want, cmp.Comparer(func(a, b time.Time) bool { return a.Equal(b) })); d != "" {
+				t.Errorf("differs: (-got +want)\n%s", d)
+			}
+		})
+	}
+}
+
+// 書き出したファイルを取り込むと元の記事に戻る
+func TestParse_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	p := testPost()
+	b, err := Render(p, LayoutHugo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := Parse("go-http-server.md", b)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Title != p.Article.Title || got.Slug != p.Article.Slug || got.Status != p.Article.Status || got.Author != p.Author {
+		t.Errorf("unexpected document: %+v", got)
+	}
+	if !got.Date.Equal(p.Article.CreatedAt.Truncate(time.Second)) {
+		t.Errorf("want date %v, but got %v", p.Article.CreatedAt, got.Date)
+	}
+	if d := cmp.Diff(got.Tags, p.Tags); d != "" {
+		t.Errorf("differs: (-got +want)\n%s", d)
+	}
+}
+
+func TestImageRefs(t *testing.T) {
+	t.Parallel()
+
+	body := "![図](./images/a.png)\n![外部](https://example.com/b.png)\n" +
+		"<img src=\"./images/c.png\" height=450>\n![再掲](./images/a.png \"タイトル\")\n![](/media/d.png)"
+	refs := LocalImageRefs(body)
+	if d := cmp.Diff(refs, []string{"./images/a.png", "./images/c.png"}); d != "" {
+		t.Errorf("differs: (-got +want)\n%s", d)
+	}
+
+	got := ReplaceImageRefs(body, map[string]string{"./images/a.png": "/media/x/a.png", "./images/c.png": "/media/y/c.png"})
+	want := "![図](/media/x/a.png)\n![外部](https://example.com/b.png)\n" +
+		"<img src=\"/media/y/c.png\" height=450>\n![再掲](/media/x/a.png \"タイトル\")\n![](/media/d.png)"
+	if got != want {
+		t.Errorf("want:\n%s\nbut got:\n%s", want, got)
+	}
+}
@@ -0,0 +1,64 @@
+package media
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// 記事の本文からは /media/<key> の形式で参照する
+const URLPrefix = "/media/"
+
+var ErrNotFound = errors.New("media not found")
+
+// ローカルのディレクトリにファイルを保存する
+// キーはスラッシュ区切りの相対パスで、ディレクトリの外は参照できない
+type Dir string
+
+func (d Dir) path(key string) (string, error) {
+	if !fs.ValidPath(key) || key == "." {
+		return "", fmt.Errorf("invalid media key: %q", key)
+	}
+	return filepath.Join(string(d), filepath.FromSlash(key)), nil
+}
+
+// 不正なキーも存在しないものとして扱う
+func (d Dir) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	p, err := d.path(key)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNotFound, err)
+	}
+	f, err := os.Open(p)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, key)
+	}
+	return f, err
+}
+
+// 一時ファイルに書き込んでから置き換え、書き込み途中のファイルが読まれないようにする
+func (d Dir) Put(ctx context.Context, key string, r io.Reader) error {
+	p, err := d.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(p), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), p)
+}
@@ -0,0 +1,37 @@
+package media
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestDir(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	d := Dir(t.TempDir())
+
+	if err := d.Put(ctx, "abc/logo.svg", strings.NewReader("<svg/>")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	r, err := d.Open(ctx, "abc/logo.svg")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer r.Close()
+	if b, _ := io.ReadAll(r); string(b) != "<svg/>" {
+		t.Errorf("want <svg/>, but got %q", b)
+	}
+
+	if _, err := d.Open(ctx, "abc/missing.png"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("want ErrNotFound, but got %v", err)
+	}
+	// ディレクトリの外は参照できない
+	for _, key := range []string{"../secret", "/etc/passwd", "a/../../b", ""} {
+		if err := d.Put(ctx, key, strings.NewReader("x")); err == nil {
+			t.Errorf("want error for key %q", key)
+		}
+	}
+}
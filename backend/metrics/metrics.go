@@ -0,0 +1,155 @@
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/iinuma0710/react-go-blog/backend/cache"
+	"github.com/iinuma0710/react-go-blog/backend/entity"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "blog"
+
+// スクレイプ時に実行する集計クエリのタイムアウト
+const collectTimeout = 3 * time.Second
+
+// Prometheus 形式で公開するメトリクスを管理する
+type Metrics struct {
+	registry *prometheus.Registry
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+}
+
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "Number of HTTP requests by chi route pattern and status code.",
+		}, []string{"method", "route", "status"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Latency of HTTP requests by chi route pattern and status code.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+	}
+	m.registry.MustRegister(
+		m.requests,
+		m.duration,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	return m
+}
+
+// HTTP リクエストの処理結果を記録する
+func (m *Metrics) ObserveRequest(method, route string, status int, d time.Duration) {
+	s := strconv.Itoa(status)
+	m.requests.WithLabelValues(method, route, s).Inc()
+	m.duration.WithLabelValues(method, route, s).Observe(d.Seconds())
+}
+
+// コネクションプールの sql.DBStats を公開する
+func (m *Metrics) RegisterDB(db *sql.DB, name string) error {
+	return m.registry.Register(collectors.NewDBStatsCollector(db, name))
+}
+
+// ステータスごとの記事数をスクレイプのたびに集計して公開する
+func (m *Metrics) RegisterArticleCounter(count func(ctx context.Context) (map[entity.ArticleStatus]int64, error)) error {
+	return m.registry.Register(&articleCollector{count: count})
+}
+
+// キャッシュの利用状況を公開する
+func (m *Metrics) RegisterCache(stats func() map[string]cache.Stats) error {
+	return m.registry.Register(&cacheCollector{stats: stats})
+}
+
+// Prometheus のテキスト形式でメトリクスを返すハンドラ
+// 一部のメトリクスの収集に失敗しても、残りのメトリクスは返す
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{
+		Registry:      m.registry,
+		ErrorHandling: promhttp.ContinueOnError,
+	})
+}
+
+var articlesDesc = prometheus.NewDesc(
+	prometheus.BuildFQName(namespace, "", "articles"),
+	"Number of articles by status.",
+	[]string{"status"}, nil,
+)
+
+type articleCollector struct {
+	count func(ctx context.Context) (map[entity.ArticleStatus]int64, error)
+}
+
+func (c *articleCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- articlesDesc
+}
+
+func (c *articleCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), collectTimeout)
+	defer cancel()
+
+	counts, err := c.count(ctx)
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(articlesDesc, err)
+		return
+	}
+	// 記事が 1 件もないステータスも 0 として出力する
+	for _, s := range entity.ArticleStatuses {
+		if _, ok := counts[s]; !ok {
+			counts[s] = 0
+		}
+	}
+	for s, n := range counts {
+		ch <- prometheus.MustNewConstMetric(articlesDesc, prometheus.GaugeValue, float64(n), string(s))
+	}
+}
+
+var (
+	cacheHitsDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "cache", "hits_total"),
+		"Number of cache hits.", []string{"cache"}, nil,
+	)
+	cacheMissesDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "cache", "misses_total"),
+		"Number of cache misses.", []string{"cache"}, nil,
+	)
+	cacheEvictionsDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "cache", "evictions_total"),
+		"Number of entries evicted from the cache.", []string{"cache"}, nil,
+	)
+	cacheSizeDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "cache", "entries"),
+		"Number of entries in the cache.", []string{"cache"}, nil,
+	)
+)
+
+type cacheCollector struct {
+	stats func() map[string]cache.Stats
+}
+
+func (c *cacheCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- cacheHitsDesc
+	ch <- cacheMissesDesc
+	ch <- cacheEvictionsDesc
+	ch <- cacheSizeDesc
+}
+
+func (c *cacheCollector) Collect(ch chan<- prometheus.Metric) {
+	for name, s := range c.stats() {
+		ch <- prometheus.MustNewConstMetric(cacheHitsDesc, prometheus.CounterValue, float64(s.Hits), name)
+		ch <- prometheus.MustNewConstMetric(cacheMissesDesc, prometheus.CounterValue, float64(s.Misses), name)
+		ch <- prometheus.MustNewConstMetric(cacheEvictionsDesc, prometheus.CounterValue, float64(s.Evictions), name)
+		ch <- prometheus.MustNewConstMetric(cacheSizeDesc, prometheus.GaugeValue, float64(s.Size), name)
+	}
+}
@@ -0,0 +1,77 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/iinuma0710/react-go-blog/backend/cache"
+	"github.com/iinuma0710/react-go-blog/backend/entity"
+)
+
+func scrape(t *testing.T, m *Metrics) string {
+	t.Helper()
+
+	w := httptest.NewRecorder()
+	m.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	b, err := io.ReadAll(w.Result().Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestMetrics(t *testing.T) {
+	t.Parallel()
+
+	sut := New()
+	sut.ObserveRequest(http.MethodGet, "/articles/{id}", http.StatusOK, 30*time.Millisecond)
+	if err := sut.RegisterArticleCounter(func(ctx context.Context) (map[entity.ArticleStatus]int64, error) {
+		return map[entity.ArticleStatus]int64{entity.ArticlePublished: 3}, nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := sut.RegisterCache(func() map[string]cache.Stats {
+		return map[string]cache.Stats{"article": {Hits: 5, Misses: 2, Size: 1}}
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	got := scrape(t, sut)
+	for _, want := range []string{
+		`blog_http_requests_total{method="GET",route="/articles/{id}",status="200"} 1`,
+		`blog_http_request_duration_seconds_bucket{method="GET",route="/articles/{id}",status="200",le="0.05"} 1`,
+		`blog_articles{status="published"} 3`,
+		`blog_articles{status="draft"} 0`,
+		`blog_cache_hits_total{cache="article"} 5`,
+		`blog_cache_misses_total{cache="article"} 2`,
+		`go_goroutines`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("want %q in metrics, but not found", want)
+		}
+	}
+}
+
+func TestMetrics_articleCounterError(t *testing.T) {
+	t.Parallel()
+
+	sut := New()
+	if err := sut.RegisterArticleCounter(func(ctx context.Context) (map[entity.ArticleStatus]int64, error) {
+		return nil, errors.New("db is down")
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// 集計に失敗しても他のメトリクスは返す
+	w := httptest.NewRecorder()
+	sut.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "go_goroutines") {
+		t.Errorf("unexpected response: %d %s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/iinuma0710/react-go-blog/backend/migration"
+	"github.com/iinuma0710/react-go-blog/backend/store"
+)
+
+// migrate up|down|status|to N を実行する
+func runMigrate(ctx context.Context, e *cmdEnv, args []string) error {
+	// データベースに接続する前に引数を確認する
+	var action func(ctx context.Context, m *migration.Migrator) error
+	switch {
+	case len(args) == 1 && args[0] == "up":
+		action = func(ctx context.Context, m *migration.Migrator) error { return m.Up(ctx) }
+	case len(args) == 1 && args[0] == "down":
+		action = func(ctx context.Context, m *migration.Migrator) error { return m.Down(ctx) }
+	case len(args) == 2 && args[0] == "to":
+		v, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid version %q: %w", args[1], err)
+		}
+		action = func(ctx context.Context, m *migration.Migrator) error { return m.To(ctx, v) }
+	case len(args) == 1 && args[0] == "status":
+		action = func(ctx context.Context, m *migration.Migrator) error {
+			ss, err := m.Status(ctx)
+			if err != nil {
+				return err
+			}
+			return printMigrationStatus(e.stdout, ss)
+		}
+	default:
+		return errUsage
+	}
+
+	ctx, _, err := e.setupLogger(ctx, e.stderr)
+	if err != nil {
+		return err
+	}
+	// スキーマが古くても実行できるよう、openDB ではなく直接接続する
+	db, cleanup, err := store.New(ctx, e.cfg, 10)
+	defer cleanup()
+	if err != nil {
+		return err
+	}
+	m, err := migration.New(db.Primary.DB, db.DriverName())
+	if err != nil {
+		return err
+	}
+	return action(ctx, m)
+}
+
+func printMigrationStatus(w io.Writer, ss []migration.Status) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+	for _, s := range ss {
+		status, at := "pending", ""
+		if s.Applied {
+			status, at = "applied", s.AppliedAt.Format(time.RFC3339)
+		}
+		if s.Unknown {
+			// 新しいバイナリで適用されたマイグレーション
+			status = "unknown"
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\n", s.Version, s.Name, status, at)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,462 @@
+package migration
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/iinuma0710/react-go-blog/backend/clock"
+	"github.com/iinuma0710/react-go-blog/backend/logger"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// バイナリに埋め込むマイグレーション
+// ファイル名は <バージョン>_<名前>.up.sql と <バージョン>_<名前>.down.sql の組にする
+// sql は MySQL 向け、sqlite は SQLite 向け、postgres は PostgreSQL 向けで、同じバージョンが同じスキーマになるようにする
+//
+//go:embed sql/*.sql sqlite/*.sql postgres/*.sql
+var embedded embed.FS
+
+// ドライバ名 (sqlx.DB の DriverName) ごとのマイグレーションのディレクトリ
+var dirs = map[string]string{
+	"mysql":    "sql",
+	"sqlite3":  "sqlite",
+	"postgres": "postgres",
+}
+
+const (
+	// 複数のインスタンスが同時にマイグレーションしないよう取得するロックの名前
+	lockName = "blog.schema_migrations"
+	// ロックの取得を待つ秒数
+	lockTimeout = 30
+)
+
+var (
+	// データベースのスキーマがバイナリの想定より古い
+	ErrBehind = errors.New("database schema is behind")
+	// 他のインスタンスがマイグレーション中
+	ErrLocked = errors.New("another migration is in progress")
+	// 指定されたバージョンのマイグレーションが存在しない
+	ErrUnknownVersion = errors.New("unknown migration version")
+)
+
+var fileName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// マイグレーションの適用状況
+type Status struct {
+	Version   int64
+	Name      string
+	AppliedAt time.Time
+	Applied   bool
+	// データベースには適用済みだが、このバイナリには含まれていない
+	Unknown bool
+}
+
+// fsys 直下の SQL ファイルをバージョンの昇順に読み込む
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int64]*Migration{}
+	for _, e := range entries {
+		m := fileName.FindStringSubmatch(e.Name())
+		if m == nil {
+			return nil, fmt.Errorf("invalid migration file name: %q", e.Name())
+		}
+		v, err := strconv.ParseInt(m[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version %q: %w", e.Name(), err)
+		}
+		b, err := fs.ReadFile(fsys, e.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		mg, ok := byVersion[v]
+		if !ok {
+			mg = &Migration{Version: v, Name: m[2]}
+			byVersion[v] = mg
+		}
+		if mg.Name != m[2] {
+			return nil, fmt.Errorf("version %d has different names: %q and %q", v, mg.Name, m[2])
+		}
+		if m[3] == "up" {
+			mg.Up = string(b)
+		} else {
+			mg.Down = string(b)
+		}
+	}
+
+	ms := make([]Migration, 0, len(byVersion))
+	for _, mg := range byVersion {
+		if mg.Up == "" || mg.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s must have both up and down files", mg.Version, mg.Name)
+		}
+		ms = append(ms, *mg)
+	}
+	sort.Slice(ms, func(i, j int) bool { return ms[i].Version < ms[j].Version })
+	return ms, nil
+}
+
+// 適用済みのバージョンを schema_migrations テーブルで管理し、マイグレーションを実行する
+type Migrator struct {
+	DB         *sql.DB
+	Migrations []Migration
+	Clocker    clock.Clocker
+	// データベースのドライバ名 (空の場合は mysql)
+	Driver string
+}
+
+// バイナリに埋め込まれた、driver 向けのマイグレーションを使う Migrator を作成する
+func New(db *sql.DB, driver string) (*Migrator, error) {
+	dir, ok := dirs[driver]
+	if !ok {
+		return nil, fmt.Errorf("unsupported database driver: %q", driver)
+	}
+	sub, err := fs.Sub(embedded, dir)
+	if err != nil {
+		return nil, err
+	}
+	ms, err := Load(sub)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{DB: db, Migrations: ms, Clocker: clock.RealClocker{}, Driver: driver}, nil
+}
+
+// バイナリに含まれる最新のバージョン
+func (m *Migrator) Latest() int64 {
+	if len(m.Migrations) == 0 {
+		return 0
+	}
+	return m.Migrations[len(m.Migrations)-1].Version
+}
+
+// 適用済みの最新のバージョン
+func (m *Migrator) Current(ctx context.Context) (int64, error) {
+	applied, err := m.applied(ctx, m.DB)
+	if err != nil {
+		return 0, err
+	}
+	var cur int64
+	for v := range applied {
+		cur = max(cur, v)
+	}
+	return cur, nil
+}
+
+// データベースのスキーマがバイナリの想定に追いついているかを確認する
+// 未適用のマイグレーションがあれば ErrBehind を返す
+func (m *Migrator) Check(ctx context.Context) error {
+	applied, err := m.applied(ctx, m.DB)
+	if err != nil {
+		return err
+	}
+	var pending []string
+	for _, mg := range m.Migrations {
+		if _, ok := applied[mg.Version]; !ok {
+			pending = append(pending, fmt.Sprintf("%d_%s", mg.Version, mg.Name))
+		}
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("%w: pending migrations %s", ErrBehind, strings.Join(pending, ", "))
+	}
+	return nil
+}
+
+// バイナリに含まれるマイグレーションと、データベースにだけ記録されているバージョンの状態を返す
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	applied, err := m.applied(ctx, m.DB)
+	if err != nil {
+		return nil, err
+	}
+	var ss []Status
+	for _, mg := range m.Migrations {
+		s := Status{Version: mg.Version, Name: mg.Name}
+		if r, ok := applied[mg.Version]; ok {
+			s.Applied, s.AppliedAt = true, r.AppliedAt
+			delete(applied, mg.Version)
+		}
+		ss = append(ss, s)
+	}
+	for _, r := range applied {
+		ss = append(ss, Status{Version: r.Version, Name: r.Name, AppliedAt: r.AppliedAt, Applied: true, Unknown: true})
+	}
+	sort.Slice(ss, func(i, j int) bool { return ss[i].Version < ss[j].Version })
+	return ss, nil
+}
+
+// 未適用のマイグレーションをすべて適用する
+func (m *Migrator) Up(ctx context.Context) error {
+	return m.To(ctx, m.Latest())
+}
+
+// 最後に適用したマイグレーションを 1 つ取り消す
+func (m *Migrator) Down(ctx context.Context) error {
+	return m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for i := len(m.Migrations) - 1; i >= 0; i-- {
+			if _, ok := applied[m.Migrations[i].Version]; ok {
+				return m.down(ctx, conn, m.Migrations[i])
+			}
+		}
+		return nil
+	})
+}
+
+// version まで適用し、それより新しいものは取り消す (0 の場合はすべて取り消す)
+func (m *Migrator) To(ctx context.Context, version int64) error {
+	if version != 0 && !m.known(version) {
+		return fmt.Errorf("%w: %d", ErrUnknownVersion, version)
+	}
+	return m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for _, mg := range m.Migrations {
+			if _, ok := applied[mg.Version]; !ok && mg.Version <= version {
+				if err := m.up(ctx, conn, mg); err != nil {
+					return err
+				}
+			}
+		}
+		for i := len(m.Migrations) - 1; i >= 0; i-- {
+			mg := m.Migrations[i]
+			if _, ok := applied[mg.Version]; ok && mg.Version > version {
+				if err := m.down(ctx, conn, mg); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+func (m *Migrator) known(version int64) bool {
+	for _, mg := range m.Migrations {
+		if mg.Version == version {
+			return true
+		}
+	}
+	return false
+}
+
+// MySQL では DDL が暗黙的にコミットされるためトランザクションは使わない
+// 途中で失敗した場合は、その時点までの文が適用されたままになる
+func (m *Migrator) up(ctx context.Context, conn *sql.Conn, mg Migration) error {
+	if err := execAll(ctx, conn, mg.Up); err != nil {
+		return fmt.Errorf("failed to apply %d_%s: %w", mg.Version, mg.Name, err)
+	}
+	if _, err := conn.ExecContext(ctx,
+		m.rebind("INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)"),
+		mg.Version, mg.Name, m.Clocker.Now(),
+	); err != nil {
+		return err
+	}
+	logger.FromContext(ctx).Info("migration applied", "version", mg.Version, "name", mg.Name)
+	return nil
+}
+
+func (m *Migrator) down(ctx context.Context, conn *sql.Conn, mg Migration) error {
+	if err := execAll(ctx, conn, mg.Down); err != nil {
+		return fmt.Errorf("failed to revert %d_%s: %w", mg.Version, mg.Name, err)
+	}
+	if _, err := conn.ExecContext(ctx, m.rebind("DELETE FROM schema_migrations WHERE version = ?"), mg.Version); err != nil {
+		return err
+	}
+	logger.FromContext(ctx).Info("migration reverted", "version", mg.Version, "name", mg.Name)
+	return nil
+}
+
+// 名前付きロックを取得してから fn を実行する
+// ロックはセッションに紐づくため、同じコネクションでマイグレーションを実行する
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.DB.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	switch m.Driver {
+	case "sqlite3":
+		// SQLite は 1 つのファイルを 1 つのプロセスから使う想定なので、ロックは取らない
+		if _, err := conn.ExecContext(ctx, createTableSQLite); err != nil {
+			return fmt.Errorf("failed to create schema_migrations: %w", err)
+		}
+		return fn(conn)
+	case "postgres":
+		return m.withAdvisoryLock(ctx, conn, fn)
+	}
+
+	var got sql.NullInt64
+	if err := conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, ?)", lockName, lockTimeout).Scan(&got); err != nil {
+		return fmt.Errorf("failed to get migration lock: %w", err)
+	}
+	if got.Int64 != 1 {
+		return ErrLocked
+	}
+	defer func() {
+		_, _ = conn.ExecContext(context.WithoutCancel(ctx), "SELECT RELEASE_LOCK(?)", lockName)
+	}()
+
+	if _, err := conn.ExecContext(ctx, createTable); err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+	return fn(conn)
+}
+
+// PostgreSQL ではセッション単位のアドバイザリロックを使う
+// MySQL の GET_LOCK と同じく、lockTimeout 秒まで待っても取得できなければ ErrLocked を返す
+func (m *Migrator) withAdvisoryLock(ctx context.Context, conn *sql.Conn, fn func(conn *sql.Conn) error) error {
+	lctx, cancel := context.WithTimeout(ctx, lockTimeout*time.Second)
+	defer cancel()
+	if _, err := conn.ExecContext(lctx, "SELECT pg_advisory_lock(hashtext($1))", lockName); err != nil {
+		if ctx.Err() == nil && errors.Is(lctx.Err(), context.DeadlineExceeded) {
+			return ErrLocked
+		}
+		return fmt.Errorf("failed to get migration lock: %w", err)
+	}
+	defer func() {
+		_, _ = conn.ExecContext(context.WithoutCancel(ctx), "SELECT pg_advisory_unlock(hashtext($1))", lockName)
+	}()
+
+	if _, err := conn.ExecContext(ctx, createTablePostgres); err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+	return fn(conn)
+}
+
+// ? のプレースホルダをドライバに合わせる
+func (m *Migrator) rebind(query string) string {
+	if m.Driver == "postgres" {
+		return sqlx.Rebind(sqlx.DOLLAR, query)
+	}
+	return query
+}
+
+const createTable = "CREATE TABLE IF NOT EXISTS `schema_migrations` (" +
+	"`version` BIGINT UNSIGNED NOT NULL, " +
+	"`name` VARCHAR(255) NOT NULL, " +
+	"`applied_at` DATETIME(6) NOT NULL, " +
+	"PRIMARY KEY (`version`)" +
+	") ENGINE=INNODB DEFAULT CHARSET=utf8mb4"
+
+const createTableSQLite = "CREATE TABLE IF NOT EXISTS `schema_migrations` (" +
+	"`version` INTEGER NOT NULL PRIMARY KEY, " +
+	"`name` TEXT NOT NULL, " +
+	"`applied_at` DATETIME NOT NULL" +
+	")"
+
+const createTablePostgres = "CREATE TABLE IF NOT EXISTS schema_migrations (" +
+	"version BIGINT NOT NULL PRIMARY KEY, " +
+	"name VARCHAR(255) NOT NULL, " +
+	"applied_at TIMESTAMPTZ NOT NULL" +
+	")"
+
+type record struct {
+	Version   int64
+	Name      string
+	AppliedAt time.Time
+}
+
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// 適用済みのバージョンを返す
+// schema_migrations テーブルがまだない場合は何も適用されていないとみなす
+func (m *Migrator) applied(ctx context.Context, db queryer) (map[int64]record, error) {
+	rows, err := db.QueryContext(ctx, "SELECT version, name, applied_at FROM schema_migrations")
+	if err != nil {
+		if isNoSuchTable(err) {
+			return map[int64]record{}, nil
+		}
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := map[int64]record{}
+	for rows.Next() {
+		var r record
+		if err := rows.Scan(&r.Version, &r.Name, &r.AppliedAt); err != nil {
+			return nil, err
+		}
+		applied[r.Version] = r
+	}
+	return applied, rows.Err()
+}
+
+// SQL ファイルを文ごとに分割して順に実行する
+func execAll(ctx context.Context, conn *sql.Conn, script string) error {
+	for _, stmt := range splitStatements(script) {
+		if _, err := conn.ExecContext(ctx, stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// 行末の ; で文を区切る
+// -- で始まる行はコメントとして読み飛ばす
+func splitStatements(script string) []string {
+	var stmts []string
+	var b strings.Builder
+	for _, line := range strings.Split(script, "\n") {
+		t := strings.TrimSpace(line)
+		if t == "" || strings.HasPrefix(t, "--") {
+			continue
+		}
+		b.WriteString(line)
+		b.WriteString("\n")
+		if strings.HasSuffix(t, ";") {
+			stmts = append(stmts, strings.TrimSuffix(strings.TrimSpace(b.String()), ";"))
+			b.Reset()
+		}
+	}
+	if s := strings.TrimSpace(b.String()); s != "" {
+		stmts = append(stmts, s)
+	}
+	return stmts
+}
+
+// テーブルが存在しないことを表す MySQL のエラー番号と PostgreSQL のエラーコード
+const (
+	mysqlErrNoSuchTable = 1146
+	pqErrUndefinedTable = "42P01"
+)
+
+func isNoSuchTable(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == mysqlErrNoSuchTable
+	}
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErr.Code == pqErrUndefinedTable
+	}
+	// SQLite はエラーコードで区別できないので、メッセージで判定する
+	return strings.HasPrefix(err.Error(), "no such table")
+}
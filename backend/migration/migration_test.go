@@ -0,0 +1,297 @@
+package migration
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/iinuma0710/react-go-blog/backend/clock"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	// 埋め込まれたマイグレーションが読み込めること
+	// ドライバごとに同じバージョンのマイグレーションを用意する
+	var versions []int64
+	for _, driver := range []string{"mysql", "sqlite3", "postgres"} {
+		m, err := New(nil, driver)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if m.Latest() < 1 {
+			t.Errorf("%s: want at least one migration, but got %d", driver, m.Latest())
+		}
+		var vs []int64
+		for _, mg := range m.Migrations {
+			if len(splitStatements(mg.Up)) == 0 || len(splitStatements(mg.Down)) == 0 {
+				t.Errorf("%s: migration %d_%s has no statements", driver, mg.Version, mg.Name)
+			}
+			vs = append(vs, mg.Version)
+		}
+		if versions != nil && !slices.Equal(versions, vs) {
+			t.Errorf("%s: want versions %v, but got %v", driver, versions, vs)
+		}
+		versions = vs
+	}
+
+	if _, err := New(nil, "oracle"); err == nil {
+		t.Error("want error for unknown driver, but got nil")
+	}
+}
+
+// SQLite 向けのマイグレーションを実際に適用し、取り消せることを確認する
+func TestMigrator_SQLite(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "blog.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	m, err := New(db, "sqlite3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Check(ctx); !errors.Is(err, ErrBehind) {
+		t.Fatalf("want ErrBehind, but got %v", err)
+	}
+	if err := m.Up(ctx); err != nil {
+		t.Fatalf("failed to migrate up: %v", err)
+	}
+	if err := m.Check(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := m.To(ctx, 0); err != nil {
+		t.Fatalf("failed to migrate down: %v", err)
+	}
+	if cur, err := m.Current(ctx); err != nil || cur != 0 {
+		t.Fatalf("want version 0, but got %d (%v)", cur, err)
+	}
+	if err := m.Up(ctx); err != nil {
+		t.Fatalf("failed to migrate up again: %v", err)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		fsys    fstest.MapFS
+		want    []int64
+		wantErr bool
+	}{
+		"sorted": {
+			fsys: fstest.MapFS{
+				"0002_add_tag.up.sql":     {Data: []byte("CREATE TABLE tag (id INT);")},
+				"0002_add_tag.down.sql":   {Data: []byte("DROP TABLE tag;")},
+				"0001_initial.up.sql":     {Data: []byte("CREATE TABLE a (id INT);")},
+				"0001_initial.down.sql":   {Data: []byte("DROP TABLE a;")},
+				"0010_add_index.up.sql":   {Data: []byte("CREATE INDEX idx ON a (id);")},
+				"0010_add_index.down.sql": {Data: []byte("DROP INDEX idx ON a;")},
+			},
+			want: []int64{1, 2, 10},
+		},
+		"missingDown": {
+			fsys:    fstest.MapFS{"0001_initial.up.sql": {Data: []byte("CREATE TABLE a (id INT);")}},
+			wantErr: true,
+		},
+		"invalidName": {
+			fsys:    fstest.MapFS{"initial.sql": {Data: []byte("CREATE TABLE a (id INT);")}},
+			wantErr: true,
+		},
+		"nameMismatch": {
+			fsys: fstest.MapFS{
+				"0001_initial.up.sql": {Data: []byte("CREATE TABLE a (id INT);")},
+				"0001_other.down.sql": {Data: []byte("DROP TABLE a;")},
+			},
+			wantErr: true,
+		},
+	}
+
+	for n, tt := range tests {
+		tt := tt
+		t.Run(n, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := Load(tt.fsys)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("want error %v, but got %v", tt.wantErr, err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("want %d migrations, but got %d", len(tt.want), len(got))
+			}
+			for i, v := range tt.want {
+				if got[i].Version != v {
+					t.Errorf("want version %d at %d, but got %d", v, i, got[i].Version)
+				}
+			}
+		})
+	}
+}
+
+func TestSplitStatements(t *testing.T) {
+	t.Parallel()
+
+	script := "-- コメント\nCREATE TABLE a (\n  id INT -- 識別子\n);\n\nINSERT INTO a VALUES (1);\nDROP TABLE b"
+	got := splitStatements(script)
+	want := []string{"CREATE TABLE a (\n  id INT -- 識別子\n)", "INSERT INTO a VALUES (1)", "DROP TABLE b"}
+	if len(got) != len(want) {
+		t.Fatalf("want %q, but got %q", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("want %q, but got %q", want[i], got[i])
+		}
+	}
+}
+
+var testMigrations = []Migration{
+	{Version: 1, Name: "initial", Up: "CREATE TABLE a (id INT);", Down: "DROP TABLE a;"},
+	{Version: 2, Name: "add_b", Up: "CREATE TABLE b (id INT);", Down: "DROP TABLE b;"},
+	{Version: 3, Name: "add_c", Up: "CREATE TABLE c (id INT);", Down: "DROP TABLE c;"},
+}
+
+func appliedRows(versions ...int64) *sqlmock.Rows {
+	rows := sqlmock.NewRows([]string{"version", "name", "applied_at"})
+	for _, v := range versions {
+		rows.AddRow(v, testMigrations[v-1].Name, time.Date(2024, 9, 24, 0, 0, 0, 0, time.UTC))
+	}
+	return rows
+}
+
+func TestMigrator_To(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		applied []int64
+		to      int64
+		expect  func(mock sqlmock.Sqlmock)
+	}{
+		"upFromEmpty": {
+			to: 3,
+			expect: func(mock sqlmock.Sqlmock) {
+				for _, mg := range testMigrations {
+					mock.ExpectExec(regexp.QuoteMeta(mg.Up[:len(mg.Up)-1])).WillReturnResult(sqlmock.NewResult(0, 0))
+					mock.ExpectExec(`INSERT INTO schema_migrations`).
+						WithArgs(mg.Version, mg.Name, clock.FixedClocker{}.Now()).
+						WillReturnResult(sqlmock.NewResult(0, 1))
+				}
+			},
+		},
+		"upPending": {
+			applied: []int64{1, 2},
+			to:      3,
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta("CREATE TABLE c (id INT)")).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(`INSERT INTO schema_migrations`).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		// 新しいものから順に取り消す
+		"downTo1": {
+			applied: []int64{1, 2, 3},
+			to:      1,
+			expect: func(mock sqlmock.Sqlmock) {
+				for _, tbl := range []string{"c", "b"} {
+					mock.ExpectExec(regexp.QuoteMeta("DROP TABLE " + tbl)).WillReturnResult(sqlmock.NewResult(0, 0))
+					mock.ExpectExec(`DELETE FROM schema_migrations WHERE version = \?`).WillReturnResult(sqlmock.NewResult(0, 1))
+				}
+			},
+		},
+	}
+
+	for n, tt := range tests {
+		tt := tt
+		t.Run(n, func(t *testing.T) {
+			t.Parallel()
+
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatal(err)
+			}
+			t.Cleanup(func() { db.Close() })
+
+			mock.ExpectQuery(regexp.QuoteMeta("SELECT GET_LOCK(?, ?)")).
+				WithArgs(lockName, lockTimeout).
+				WillReturnRows(sqlmock.NewRows([]string{"lock"}).AddRow(1))
+			mock.ExpectExec("CREATE TABLE IF NOT EXISTS `schema_migrations`").WillReturnResult(sqlmock.NewResult(0, 0))
+			mock.ExpectQuery(`SELECT version, name, applied_at FROM schema_migrations`).WillReturnRows(appliedRows(tt.applied...))
+			tt.expect(mock)
+			mock.ExpectExec(regexp.QuoteMeta("SELECT RELEASE_LOCK(?)")).WithArgs(lockName).WillReturnResult(sqlmock.NewResult(0, 0))
+
+			m := &Migrator{DB: db, Migrations: testMigrations, Clocker: clock.FixedClocker{}}
+			if err := m.To(context.Background(), tt.to); err != nil {
+				t.Fatal(err)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestMigrator_To_Locked(t *testing.T) {
+	t.Parallel()
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	// 他のインスタンスがロックを保持している
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT GET_LOCK(?, ?)")).
+		WillReturnRows(sqlmock.NewRows([]string{"lock"}).AddRow(0))
+
+	m := &Migrator{DB: db, Migrations: testMigrations, Clocker: clock.FixedClocker{}}
+	if err := m.Up(context.Background()); !errors.Is(err, ErrLocked) {
+		t.Errorf("want %v, but got %v", ErrLocked, err)
+	}
+	if err := m.To(context.Background(), 4); !errors.Is(err, ErrUnknownVersion) {
+		t.Errorf("want %v, but got %v", ErrUnknownVersion, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMigrator_Check(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		applied []int64
+		wantErr error
+	}{
+		"upToDate": {applied: []int64{1, 2, 3}},
+		"behind":   {applied: []int64{1}, wantErr: ErrBehind},
+	}
+
+	for n, tt := range tests {
+		tt := tt
+		t.Run(n, func(t *testing.T) {
+			t.Parallel()
+
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatal(err)
+			}
+			t.Cleanup(func() { db.Close() })
+			mock.ExpectQuery(`SELECT version, name, applied_at FROM schema_migrations`).WillReturnRows(appliedRows(tt.applied...))
+
+			m := &Migrator{DB: db, Migrations: testMigrations, Clocker: clock.FixedClocker{}}
+			if err := m.Check(context.Background()); !errors.Is(err, tt.wantErr) {
+				t.Errorf("want %v, but got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
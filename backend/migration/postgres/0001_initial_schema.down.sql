@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS idempotency_key;
+DROP TABLE IF EXISTS article;
+DROP TABLE IF EXISTS "user";
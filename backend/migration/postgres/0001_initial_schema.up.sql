@@ -0,0 +1,37 @@
+-- MySQL のマイグレーションと同じバージョンで、同じスキーマを PostgreSQL 向けに作成する
+-- user は予約語なので、テーブル名を引用符で囲む
+-- MySQL の既定の照合順序に合わせ、名前の一意制約は大文字と小文字を区別しない
+CREATE TABLE "user"
+(
+    id         BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    name       VARCHAR(20) NOT NULL,
+    email      VARCHAR(80) NOT NULL,
+    password   VARCHAR(80) NOT NULL,
+    role       VARCHAR(80) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE UNIQUE INDEX uix_user_name ON "user" (LOWER(name));
+
+CREATE TABLE article
+(
+    id         BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    title      VARCHAR(128) NOT NULL,
+    status     VARCHAR(20)  NOT NULL,
+    version    BIGINT       NOT NULL DEFAULT 1,
+    created_at TIMESTAMPTZ  NOT NULL
+);
+
+CREATE TABLE idempotency_key
+(
+    idempotency_key CHAR(64)     NOT NULL PRIMARY KEY,
+    request_hash    CHAR(64)     NOT NULL,
+    status          INTEGER      NOT NULL DEFAULT 0,
+    content_type    VARCHAR(255) NOT NULL DEFAULT '',
+    body            BYTEA        NULL,
+    expires_at      TIMESTAMPTZ  NOT NULL,
+    created_at      TIMESTAMPTZ  NOT NULL
+);
+
+CREATE INDEX idx_idempotency_key_expires_at ON idempotency_key (expires_at);
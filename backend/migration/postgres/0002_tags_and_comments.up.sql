@@ -0,0 +1,28 @@
+CREATE TABLE tag
+(
+    id         BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    name       VARCHAR(64) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE UNIQUE INDEX uix_tag_name ON tag (LOWER(name));
+
+CREATE TABLE article_tag
+(
+    article_id BIGINT NOT NULL,
+    tag_id     BIGINT NOT NULL,
+    PRIMARY KEY (article_id, tag_id)
+);
+
+CREATE INDEX idx_article_tag_tag_id ON article_tag (tag_id);
+
+CREATE TABLE comment
+(
+    id         BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    article_id BIGINT      NOT NULL,
+    user_id    BIGINT      NOT NULL,
+    body       TEXT        NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX idx_comment_article_id ON comment (article_id);
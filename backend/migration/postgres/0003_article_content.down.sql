@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS uix_article_slug;
+
+ALTER TABLE article
+    DROP COLUMN updated_at,
+    DROP COLUMN author_id,
+    DROP COLUMN slug,
+    DROP COLUMN content;
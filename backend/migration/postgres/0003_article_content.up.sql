@@ -0,0 +1,15 @@
+-- 既存の記事は空の本文とし、追加した後は MySQL と同じく既定値を持たない列にする
+ALTER TABLE article
+    ADD COLUMN content    TEXT         NOT NULL DEFAULT '',
+    ADD COLUMN slug       VARCHAR(128) NULL,
+    ADD COLUMN author_id  BIGINT       NULL,
+    ADD COLUMN updated_at TIMESTAMPTZ  NULL;
+
+ALTER TABLE article ALTER COLUMN content DROP DEFAULT;
+
+CREATE UNIQUE INDEX uix_article_slug ON article (LOWER(slug));
+
+-- 既存の記事は作成日時を更新日時とする
+UPDATE article SET updated_at = created_at;
+
+ALTER TABLE article ALTER COLUMN updated_at SET NOT NULL;
@@ -0,0 +1,19 @@
+CREATE TABLE media
+(
+    storage_key  VARCHAR(255) NOT NULL PRIMARY KEY,
+    content_type VARCHAR(255) NOT NULL,
+    size         BIGINT       NOT NULL,
+    sha256       CHAR(64)     NOT NULL,
+    created_at   TIMESTAMPTZ  NOT NULL
+);
+
+CREATE TABLE article_source
+(
+    source     VARCHAR(255) NOT NULL PRIMARY KEY,
+    article_id BIGINT       NOT NULL,
+    checksum   CHAR(64)     NOT NULL,
+    created_at TIMESTAMPTZ  NOT NULL,
+    updated_at TIMESTAMPTZ  NOT NULL
+);
+
+CREATE INDEX idx_article_source_article_id ON article_source (article_id);
@@ -0,0 +1,5 @@
+DELETE FROM comment WHERE user_id IS NULL;
+
+ALTER TABLE comment
+    DROP COLUMN author_name,
+    ALTER COLUMN user_id SET NOT NULL;
@@ -0,0 +1,4 @@
+-- 取り込んだコメントのように、ユーザとして登録されていない投稿者のコメントを保存できるようにする
+ALTER TABLE comment
+    ALTER COLUMN user_id DROP NOT NULL,
+    ADD COLUMN author_name VARCHAR(255) NOT NULL DEFAULT '';
@@ -1,4 +1,5 @@
-CREATE TABLE `user`
+-- マイグレーションの導入前に sqldef で作成したデータベースでは、既存のテーブルをそのまま使う
+CREATE TABLE IF NOT EXISTS `user`
 (
     `id`         BIGINT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT 'ユーザの識別子',
     `name`       VARCHAR(20) NOT NULL COMMENT 'ユーザ名',
@@ -11,14 +12,28 @@ CREATE TABLE `user`
     UNIQUE KEY `uix_name` (`name`) USING BTREE
 ) ENGINE=INNODB DEFAULT CHARSET=utf8mb4 COMMENT='ユーザ';
 
-CREATE TABLE `article`
+CREATE TABLE IF NOT EXISTS `article`
 (
     `id`         BIGINT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT '記事の識別子',
     `title`      VARCHAR(128)    NOT NULL COMMENT '記事のタイトル',
     -- `content`    VARCHAR(20)     NOT NULL COMMENT '記事の本文',
     `status`     VARCHAR(20)     NOT NULL COMMENT '記事のステータス',
+    `version`    BIGINT UNSIGNED NOT NULL DEFAULT 1 COMMENT '楽観的排他制御のためのバージョン',
     -- `author_id`  BIGINT UNSIGNED NOT NULL COMMENT '記事作成者のユーザID',
     `created_at` DATETIME(6)     NOT NULL COMMENT 'レコードの作成日時',
     -- `updated_at` DATETIME(6)     NOT NULL COMMENT 'レコードの更新日時',
     PRIMARY KEY (`id`)
-) ENGINE=INNODB DEFAULT CHARSET=utf8mb4 COMMENT='ブログ記事';
\ No newline at end of file
+) ENGINE=INNODB DEFAULT CHARSET=utf8mb4 COMMENT='ブログ記事';
+
+CREATE TABLE IF NOT EXISTS `idempotency_key`
+(
+    `idempotency_key` CHAR(64)     NOT NULL COMMENT '呼び出し元と Idempotency-Key ヘッダから作るハッシュ値',
+    `request_hash`    CHAR(64)     NOT NULL COMMENT 'リクエスト内容のハッシュ値',
+    `status`          INT          NOT NULL DEFAULT 0 COMMENT 'レスポンスのステータスコード (0 は処理中)',
+    `content_type`    VARCHAR(255) NOT NULL DEFAULT '' COMMENT 'レスポンスの Content-Type',
+    `body`            MEDIUMBLOB   NULL COMMENT 'レスポンスボディ',
+    `expires_at`      DATETIME(6)  NOT NULL COMMENT 'レコードの有効期限',
+    `created_at`      DATETIME(6)  NOT NULL COMMENT 'レコードの作成日時',
+    PRIMARY KEY (`idempotency_key`),
+    KEY `idx_expires_at` (`expires_at`)
+) ENGINE=INNODB DEFAULT CHARSET=utf8mb4 COMMENT='冪等性キー';
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS `comment`;
+DROP TABLE IF EXISTS `article_tag`;
+DROP TABLE IF EXISTS `tag`;
@@ -0,0 +1,27 @@
+CREATE TABLE `tag`
+(
+    `id`         BIGINT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT 'タグの識別子',
+    `name`       VARCHAR(64)     NOT NULL COMMENT 'タグ名',
+    `created_at` DATETIME(6)     NOT NULL COMMENT 'レコードの作成日時',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uix_name` (`name`) USING BTREE
+) ENGINE=INNODB DEFAULT CHARSET=utf8mb4 COMMENT='タグ';
+
+CREATE TABLE `article_tag`
+(
+    `article_id` BIGINT UNSIGNED NOT NULL COMMENT '記事の識別子',
+    `tag_id`     BIGINT UNSIGNED NOT NULL COMMENT 'タグの識別子',
+    PRIMARY KEY (`article_id`, `tag_id`),
+    KEY `idx_tag_id` (`tag_id`)
+) ENGINE=INNODB DEFAULT CHARSET=utf8mb4 COMMENT='記事とタグの対応';
+
+CREATE TABLE `comment`
+(
+    `id`         BIGINT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT 'コメントの識別子',
+    `article_id` BIGINT UNSIGNED NOT NULL COMMENT 'コメント先の記事の識別子',
+    `user_id`    BIGINT UNSIGNED NOT NULL COMMENT 'コメントしたユーザの識別子',
+    `body`       TEXT            NOT NULL COMMENT 'コメントの本文',
+    `created_at` DATETIME(6)     NOT NULL COMMENT 'レコードの作成日時',
+    PRIMARY KEY (`id`),
+    KEY `idx_article_id` (`article_id`)
+) ENGINE=INNODB DEFAULT CHARSET=utf8mb4 COMMENT='記事へのコメント';
@@ -0,0 +1,6 @@
+ALTER TABLE `article`
+    DROP INDEX `uix_slug`,
+    DROP COLUMN `updated_at`,
+    DROP COLUMN `author_id`,
+    DROP COLUMN `slug`,
+    DROP COLUMN `content`;
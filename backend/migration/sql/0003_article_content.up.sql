@@ -0,0 +1,13 @@
+-- 記事の本文、スラッグ、作成者と更新日時を追加する
+ALTER TABLE `article`
+    ADD COLUMN `content`    MEDIUMTEXT      NOT NULL COMMENT '記事の本文 (Markdown)' AFTER `title`,
+    ADD COLUMN `slug`       VARCHAR(128)    NULL COMMENT 'URL やファイル名に使う記事の識別子' AFTER `content`,
+    ADD COLUMN `author_id`  BIGINT UNSIGNED NULL COMMENT '記事作成者のユーザID' AFTER `version`,
+    ADD COLUMN `updated_at` DATETIME(6)     NULL COMMENT 'レコードの更新日時' AFTER `created_at`,
+    ADD UNIQUE KEY `uix_slug` (`slug`) USING BTREE;
+
+-- 既存の記事は作成日時を更新日時とする
+UPDATE `article` SET `updated_at` = `created_at`;
+
+ALTER TABLE `article`
+    MODIFY COLUMN `updated_at` DATETIME(6) NOT NULL COMMENT 'レコードの更新日時';
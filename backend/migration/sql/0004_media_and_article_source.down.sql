@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS `article_source`;
+DROP TABLE IF EXISTS `media`;
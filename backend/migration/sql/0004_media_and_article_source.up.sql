@@ -0,0 +1,20 @@
+CREATE TABLE `media`
+(
+    `storage_key`  VARCHAR(255)    NOT NULL COMMENT 'メディアストレージ上のキー',
+    `content_type` VARCHAR(255)    NOT NULL COMMENT 'ファイルの Content-Type',
+    `size`         BIGINT UNSIGNED NOT NULL COMMENT 'ファイルのサイズ (バイト)',
+    `sha256`       CHAR(64)        NOT NULL COMMENT 'ファイルの内容のハッシュ値',
+    `created_at`   DATETIME(6)     NOT NULL COMMENT 'レコードの作成日時',
+    PRIMARY KEY (`storage_key`)
+) ENGINE=INNODB DEFAULT CHARSET=utf8mb4 COMMENT='記事から参照するファイル';
+
+CREATE TABLE `article_source`
+(
+    `source`     VARCHAR(255)    NOT NULL COMMENT '取り込み元のファイルのパスなど',
+    `article_id` BIGINT UNSIGNED NOT NULL COMMENT '取り込んだ記事の識別子',
+    `checksum`   CHAR(64)        NOT NULL COMMENT '取り込んだ内容のハッシュ値',
+    `created_at` DATETIME(6)     NOT NULL COMMENT 'レコードの作成日時',
+    `updated_at` DATETIME(6)     NOT NULL COMMENT 'レコードの更新日時',
+    PRIMARY KEY (`source`),
+    KEY `idx_article_id` (`article_id`)
+) ENGINE=INNODB DEFAULT CHARSET=utf8mb4 COMMENT='取り込んだ記事の取り込み元';
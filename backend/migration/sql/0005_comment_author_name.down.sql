@@ -0,0 +1,5 @@
+DELETE FROM `comment` WHERE `user_id` IS NULL;
+
+ALTER TABLE `comment`
+    DROP COLUMN `author_name`,
+    MODIFY COLUMN `user_id` BIGINT UNSIGNED NOT NULL COMMENT 'コメントしたユーザの識別子';
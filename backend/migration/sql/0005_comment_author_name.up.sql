@@ -0,0 +1,4 @@
+-- 取り込んだコメントのように、ユーザとして登録されていない投稿者のコメントを保存できるようにする
+ALTER TABLE `comment`
+    MODIFY COLUMN `user_id` BIGINT UNSIGNED NULL COMMENT 'コメントしたユーザの識別子 (ユーザ以外の場合は NULL)',
+    ADD COLUMN `author_name` VARCHAR(255) NOT NULL DEFAULT '' COMMENT 'ユーザ以外の投稿者の名前' AFTER `user_id`;
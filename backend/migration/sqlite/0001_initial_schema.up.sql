@@ -0,0 +1,37 @@
+-- MySQL のマイグレーションと同じバージョンで、同じスキーマを SQLite 向けに作成する
+-- 日時は DATETIME 型として宣言し、ドライバに time.Time として読み書きさせる
+-- MySQL の既定の照合順序に合わせ、名前の一意制約は大文字と小文字を区別しない
+CREATE TABLE `user`
+(
+    `id`         INTEGER PRIMARY KEY AUTOINCREMENT,
+    `name`       TEXT     NOT NULL COLLATE NOCASE,
+    `email`      TEXT     NOT NULL,
+    `password`   TEXT     NOT NULL,
+    `role`       TEXT     NOT NULL,
+    `created_at` DATETIME NOT NULL,
+    `updated_at` DATETIME NOT NULL
+);
+
+CREATE UNIQUE INDEX `uix_user_name` ON `user` (`name`);
+
+CREATE TABLE `article`
+(
+    `id`         INTEGER PRIMARY KEY AUTOINCREMENT,
+    `title`      TEXT     NOT NULL,
+    `status`     TEXT     NOT NULL,
+    `version`    INTEGER  NOT NULL DEFAULT 1,
+    `created_at` DATETIME NOT NULL
+);
+
+CREATE TABLE `idempotency_key`
+(
+    `idempotency_key` TEXT     NOT NULL PRIMARY KEY,
+    `request_hash`    TEXT     NOT NULL,
+    `status`          INTEGER  NOT NULL DEFAULT 0,
+    `content_type`    TEXT     NOT NULL DEFAULT '',
+    `body`            BLOB     NULL,
+    `expires_at`      DATETIME NOT NULL,
+    `created_at`      DATETIME NOT NULL
+);
+
+CREATE INDEX `idx_idempotency_key_expires_at` ON `idempotency_key` (`expires_at`);
@@ -0,0 +1,28 @@
+CREATE TABLE `tag`
+(
+    `id`         INTEGER PRIMARY KEY AUTOINCREMENT,
+    `name`       TEXT     NOT NULL COLLATE NOCASE,
+    `created_at` DATETIME NOT NULL
+);
+
+CREATE UNIQUE INDEX `uix_tag_name` ON `tag` (`name`);
+
+CREATE TABLE `article_tag`
+(
+    `article_id` INTEGER NOT NULL,
+    `tag_id`     INTEGER NOT NULL,
+    PRIMARY KEY (`article_id`, `tag_id`)
+);
+
+CREATE INDEX `idx_article_tag_tag_id` ON `article_tag` (`tag_id`);
+
+CREATE TABLE `comment`
+(
+    `id`         INTEGER PRIMARY KEY AUTOINCREMENT,
+    `article_id` INTEGER  NOT NULL,
+    `user_id`    INTEGER  NOT NULL,
+    `body`       TEXT     NOT NULL,
+    `created_at` DATETIME NOT NULL
+);
+
+CREATE INDEX `idx_comment_article_id` ON `comment` (`article_id`);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS `uix_article_slug`;
+ALTER TABLE `article` DROP COLUMN `updated_at`;
+ALTER TABLE `article` DROP COLUMN `author_id`;
+ALTER TABLE `article` DROP COLUMN `slug`;
+ALTER TABLE `article` DROP COLUMN `content`;
@@ -0,0 +1,10 @@
+-- SQLite の ADD COLUMN では NOT NULL の列に既定値が必要なので、既存の記事は空の本文にする
+ALTER TABLE `article` ADD COLUMN `content` TEXT NOT NULL DEFAULT '';
+ALTER TABLE `article` ADD COLUMN `slug` TEXT NULL;
+ALTER TABLE `article` ADD COLUMN `author_id` INTEGER NULL;
+ALTER TABLE `article` ADD COLUMN `updated_at` DATETIME NULL;
+
+CREATE UNIQUE INDEX `uix_article_slug` ON `article` (`slug`);
+
+-- 既存の記事は作成日時を更新日時とする
+UPDATE `article` SET `updated_at` = `created_at`;
@@ -0,0 +1,19 @@
+CREATE TABLE `media`
+(
+    `storage_key`  TEXT     NOT NULL PRIMARY KEY,
+    `content_type` TEXT     NOT NULL,
+    `size`         INTEGER  NOT NULL,
+    `sha256`       TEXT     NOT NULL,
+    `created_at`   DATETIME NOT NULL
+);
+
+CREATE TABLE `article_source`
+(
+    `source`     TEXT     NOT NULL PRIMARY KEY,
+    `article_id` INTEGER  NOT NULL,
+    `checksum`   TEXT     NOT NULL,
+    `created_at` DATETIME NOT NULL,
+    `updated_at` DATETIME NOT NULL
+);
+
+CREATE INDEX `idx_article_source_article_id` ON `article_source` (`article_id`);
@@ -0,0 +1,19 @@
+DELETE FROM `comment` WHERE `user_id` IS NULL;
+
+CREATE TABLE `comment_old`
+(
+    `id`         INTEGER PRIMARY KEY AUTOINCREMENT,
+    `article_id` INTEGER  NOT NULL,
+    `user_id`    INTEGER  NOT NULL,
+    `body`       TEXT     NOT NULL,
+    `created_at` DATETIME NOT NULL
+);
+
+INSERT INTO `comment_old` (`id`, `article_id`, `user_id`, `body`, `created_at`)
+    SELECT `id`, `article_id`, `user_id`, `body`, `created_at` FROM `comment`;
+
+DROP TABLE `comment`;
+
+ALTER TABLE `comment_old` RENAME TO `comment`;
+
+CREATE INDEX `idx_comment_article_id` ON `comment` (`article_id`);
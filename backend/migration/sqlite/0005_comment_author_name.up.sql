@@ -0,0 +1,19 @@
+-- SQLite では列の NOT NULL 制約を変更できないので、テーブルを作り直す
+CREATE TABLE `comment_new`
+(
+    `id`          INTEGER PRIMARY KEY AUTOINCREMENT,
+    `article_id`  INTEGER  NOT NULL,
+    `user_id`     INTEGER  NULL,
+    `author_name` TEXT     NOT NULL DEFAULT '',
+    `body`        TEXT     NOT NULL,
+    `created_at`  DATETIME NOT NULL
+);
+
+INSERT INTO `comment_new` (`id`, `article_id`, `user_id`, `body`, `created_at`)
+    SELECT `id`, `article_id`, `user_id`, `body`, `created_at` FROM `comment`;
+
+DROP TABLE `comment`;
+
+ALTER TABLE `comment_new` RENAME TO `comment`;
+
+CREATE INDEX `idx_comment_article_id` ON `comment` (`article_id`);
@@ -2,50 +2,295 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-playground/validator/v10"
 	"github.com/iinuma0710/react-go-blog/backend/clock"
 	"github.com/iinuma0710/react-go-blog/backend/config"
+	"github.com/iinuma0710/react-go-blog/backend/entity"
 	"github.com/iinuma0710/react-go-blog/backend/handler"
+	"github.com/iinuma0710/react-go-blog/backend/health"
+	"github.com/iinuma0710/react-go-blog/backend/lifecycle"
+	"github.com/iinuma0710/react-go-blog/backend/logger"
+	"github.com/iinuma0710/react-go-blog/backend/media"
+	"github.com/iinuma0710/react-go-blog/backend/metrics"
+	"github.com/iinuma0710/react-go-blog/backend/migration"
 	"github.com/iinuma0710/react-go-blog/backend/service"
 	"github.com/iinuma0710/react-go-blog/backend/store"
 )
 
-func NewMux(ctx context.Context, cfg *config.Config) (http.Handler, func(), error) {
+// admin には管理用のポートでのみ公開するエンドポイントを登録する (nil の場合は登録しない)
+func NewMux(ctx context.Context, cr *config.Reloader, m *metrics.Metrics, hc *health.Checker, sd *lifecycle.Shutdown, admin *http.ServeMux) (http.Handler, error) {
 	mux := chi.NewRouter()
+	cfg := cr.Current()
+
+	// レート制限などで使うクライアントの IP アドレスを解決する
+	tp, err := handler.ParseTrustedProxies(cfg.TrustedProxies)
+	if err != nil {
+		return nil, err
+	}
+	mux.Use(handler.ClientIPMiddleware(tp))
+
+	// traceparent ヘッダを引き継いでリクエストごとのスパンを開始する
+	mux.Use(handler.TracingMiddleware)
+
+	// リクエスト ID 付きのロガーをコンテキストに格納し、リクエストごとにアクセスログを出力する
+	mux.Use(handler.RequestIDMiddleware(logger.FromContext(ctx)), handler.AccessLogMiddleware)
+	mux.Use(handler.MetricsMiddleware(m))
+
+	// 同じリクエストの中で書き込んだ内容は、レプリカではなくプライマリから読み出す
+	mux.Use(handler.ReadYourWritesMiddleware)
+
+	// 管理用のポートが指定されていない場合は、同じポートでメトリクスを公開する
+	if cfg.MetricsPort == 0 {
+		mux.Handle("/metrics", m.Handler())
+	}
 
 	// ヘルスチェック用のエンドポイント
-	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "application/json; charset=utf-8")
-		// 静的解析ツールのエラー回避のため、明示的に戻り値を捨てる
-		_, _ = w.Write([]byte(`{"status": "ok"}`))
-	})
+	// /health は以前からの互換性のために残し、readiness と同じ結果を返す
+	mux.Handle("/livez", &handler.Health{Checker: &hc.Live})
+	mux.Handle("/readyz", &handler.Health{Checker: &hc.Ready})
+	mux.Handle("/health", &handler.Health{Checker: &hc.Ready})
 
 	v := validator.New()
 
+	// 記事などの保存先を用意する
+	db, tx, r, err := openStore(ctx, cfg, m, hc, sd)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.RegisterArticleCounter(func(ctx context.Context) (map[entity.ArticleStatus]int64, error) {
+		return r.CountArticlesByStatus(ctx, db)
+	}); err != nil {
+		return nil, err
+	}
+
+	// 記事の読み出しはキャッシュを経由し、書き込み時にサービス層から破棄する
+	var (
+		lister      service.ArticleLister = r
+		getter      service.ArticleGetter = r
+		invalidator service.ArticleInvalidator
+	)
+	if cfg.ArticleCacheSize > 0 {
+		ac := service.NewArticleCache(r, r, cfg.ArticleCacheSize, cfg.ArticleCacheTTL, clock.RealClocker{})
+		lister, getter, invalidator = ac, ac, ac
+		if err := m.RegisterCache(ac.Stats); err != nil {
+			return nil, err
+		}
+
+		// キャッシュの調整用にヒット率などを確認するためのエンドポイント (管理用のポートでのみ公開する)
+		if admin != nil {
+			admin.HandleFunc("GET /debug/cache", func(w http.ResponseWriter, r *http.Request) {
+				handler.RespondJSON(r.Context(), w, ac.Stats(), http.StatusOK)
+			})
+		} else {
+			logger.FromContext(ctx).Info("/debug/cache is disabled, set METRICS_PORT to serve it on the admin port")
+		}
+	}
+
+	// Idempotency-Key ヘッダによる POST リクエストの重複排除
+	// データベースを使わない場合は、冪等性キーもメモリに保存する
+	kind := cfg.IdempotencyStore
+	if kind == "mysql" {
+		// PostgreSQL や SQLite でも使えるので sql に改名した (mysql は互換性のために残している)
+		logger.FromContext(ctx).Warn("IDEMPOTENCY_STORE=mysql is deprecated, use sql instead")
+		kind = "sql"
+	}
+	if cfg.BackendStore == "memory" {
+		kind = "memory"
+	}
+	var (
+		is    handler.IdempotencyStore
+		purge func(ctx context.Context) (int64, error)
+	)
+	switch kind {
+	case "sql":
+		ir := &store.IdempotencyRepository{DB: db, Clocker: clock.RealClocker{}}
+		is, purge = ir, ir.Purge
+	case "memory":
+		im := store.NewIdempotencyMemory(clock.RealClocker{})
+		is, purge = im, im.Purge
+	default:
+		return nil, fmt.Errorf("unknown idempotency store: %q", cfg.IdempotencyStore)
+	}
+	// 期限切れのキーはリクエストごとではなく定期的に削除する
+	startWorker(ctx, sd, "idempotency purge", func(ctx context.Context) {
+		purgeIdempotencyKeys(ctx, purge, cfg.IdempotencyPurgeInterval)
+	})
+	idempotency := handler.IdempotencyMiddleware(is, cfg.IdempotencyTTL)
+
+	// ルートのグループごとにレート制限をかける
+	// 制限値は SIGHUP で再読み込みされた設定をリクエストごとに参照する
+	rl := store.NewRateLimitMemory(clock.RealClocker{})
+	readLimit := func() entity.RateLimit {
+		c := cr.Current()
+		return entity.RateLimit{Rate: c.RateLimitReadRate, Burst: c.RateLimitReadBurst}
+	}
+	writeLimit := func() entity.RateLimit {
+		c := cr.Current()
+		return entity.RateLimit{Rate: c.RateLimitWriteRate, Burst: c.RateLimitWriteBurst}
+	}
+
+	// 記事を Markdown のアーカイブとして書き出すためのエンドポイント (下書きも含む)
+	if admin != nil {
+		ea := &handler.ExportArticles{
+			Service: &service.ExportArticles{DB: db, Repo: r, Media: media.Dir(cfg.MediaDir)},
+		}
+		admin.Handle("GET /admin/export", ea)
+	}
+
+	// 読み出し系のエンドポイント
+	mux.Group(func(g chi.Router) {
+		g.Use(handler.RateLimitMiddleware(rl, "read", readLimit))
+
+		// 記事一覧を取得するためのエンドポイント
+		la := &handler.ListArticle{
+			Service: &service.ListArticle{DB: db, Repo: lister},
+		}
+		g.Get("/articles", la.ServeHTTP)
+
+		// 記事を 1 件取得するためのエンドポイント (ETag でバージョンを返す)
+		ga := &handler.GetArticle{
+			Service: &service.GetArticle{DB: db, Repo: getter},
+		}
+		g.Get("/articles/{id}", ga.ServeHTTP)
+
+		// 記事から参照する画像などのファイル
+		g.Get("/media/*", (&handler.Media{Storage: media.Dir(cfg.MediaDir)}).ServeHTTP)
+	})
+
+	// 書き込み系のエンドポイント
+	mux.Group(func(g chi.Router) {
+		g.Use(handler.RateLimitMiddleware(rl, "write", writeLimit))
+
+		// 記事を追加するためのエンドポイント
+		aa := &handler.AddArticle{
+			Service:   &service.AddArticle{DB: db, Repo: r, Cache: invalidator, Tx: tx},
+			Validator: v,
+		}
+		g.With(idempotency).Post("/articles", aa.ServeHTTP)
+
+		// 記事を更新するためのエンドポイント (If-Match ヘッダが必須)
+		ua := &handler.UpdateArticle{
+			Service:   &service.UpdateArticle{DB: db, Repo: r, Cache: invalidator, Tx: tx},
+			Validator: v,
+		}
+		g.Put("/articles/{id}", ua.ServeHTTP)
+	})
+
+	return mux, nil
+}
+
+// サーバで使うリポジトリ (store.Repository または store.MemoryRepository)
+type repository interface {
+	service.ArticleAdder
+	service.ArticleUpdater
+	service.ArticleExporter
+	CountArticlesByStatus(ctx context.Context, db store.Queryer) (map[entity.ArticleStatus]int64, error)
+}
+
+// 設定に応じて記事などの保存先を用意する
+// BACKEND_STORE=memory の場合はデータベースに接続せず、返す db は nil になる (トランザクションはリポジトリ自身が扱う)
+func openStore(ctx context.Context, cfg *config.Config, m *metrics.Metrics, hc *health.Checker, sd *lifecycle.Shutdown) (store.ExecQueryer, service.Transactor, repository, error) {
+	if cfg.BackendStore == "memory" {
+		logger.FromContext(ctx).Warn("using in-memory store, data will be lost on restart")
+		r := store.NewMemoryRepository(clock.RealClocker{})
+		return nil, r, r, nil
+	}
+
 	// データベースに接続
 	db, cleanup, err := store.New(ctx, cfg, 30)
+	sd.Add(lifecycle.PhaseResources, "database", func(ctx context.Context) error {
+		cleanup()
+		return nil
+	})
 	if err != nil {
-		return nil, cleanup, err
+		return nil, nil, nil, err
 	}
 
-	// store.Repository 型のインスタンスを生成
-	r := store.Repository{Clocker: clock.RealClocker{}}
+	// スキーマがバイナリの想定より古い場合は起動しない
+	mg, err := migration.New(db.Primary.DB, db.DriverName())
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if err := mg.Check(ctx); err != nil {
+		return nil, nil, nil, fmt.Errorf("%w (run `migrate up` first)", err)
+	}
+
+	// データベースに接続できない場合や、起動後にスキーマが戻された場合はリクエストを受け付けない
+	// レプリカが使えなくてもプライマリから読み出せるので、レプリカの状態は含めない
+	hc.Ready.Register("database", 2*time.Second, db.Primary.PingContext)
+	hc.Ready.Register("migrations", 2*time.Second, mg.Check)
 
-	// 記事を追加するためのエンドポイント
-	aa := &handler.AddArticle{
-		Service:   &service.AddArticle{DB: db, Repo: &r},
-		Validator: v,
+	// SQL 文の実行ごとにスパンを記録する
+	system := cfg.DBDriver
+	if system == "postgres" {
+		// OpenTelemetry のセマンティック規約での名前に合わせる
+		system = "postgresql"
+	}
+	tdb := &store.TracedDB{DB: db, System: system}
+	tr := store.NewTransactor(db)
+	tr.Wrap = func(tx store.ExecQueryer) store.ExecQueryer {
+		return &store.TracedDB{DB: tx, System: system}
 	}
-	mux.Post("/articles", aa.ServeHTTP)
 
-	// 記事一覧を取得するためのエンドポイント
-	la := &handler.ListArticle{
-		Service: &service.ListArticle{DB: db, Repo: &r},
+	// コネクションプールの状態をメトリクスとして公開する
+	if err := m.RegisterDB(db.Primary.DB, cfg.DBName); err != nil {
+		return nil, nil, nil, err
+	}
+	for _, r := range db.Replicas {
+		if err := m.RegisterDB(r.DB.DB, cfg.DBName+"@"+r.Name); err != nil {
+			return nil, nil, nil, err
+		}
 	}
-	mux.Get("/articles", la.ServeHTTP)
 
-	return mux, cleanup, nil
+	// レプリカの疎通と遅延を定期的に確認し、読み出しの振り分け先を切り替える
+	if len(db.Replicas) > 0 {
+		startWorker(ctx, sd, "replica monitor", func(ctx context.Context) {
+			db.Monitor(ctx, cfg.DBReplicaCheckInterval, 2*time.Second)
+		})
+	}
+	return tdb, tr, &store.Repository{Clocker: clock.RealClocker{}}, nil
+}
+
+// ctx が終了するまで戻らない run をゴルーチンで実行し、停止時には終了を待つ
+func startWorker(ctx context.Context, sd *lifecycle.Shutdown, name string, run func(ctx context.Context)) {
+	wctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		run(wctx)
+	}()
+	sd.Add(lifecycle.PhaseWorkers, name, func(ctx context.Context) error {
+		cancel()
+		select {
+		case <-done:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+}
+
+// interval ごとに期限切れの冪等性キーを削除する
+func purgeIdempotencyKeys(ctx context.Context, purge func(ctx context.Context) (int64, error), interval time.Duration) {
+	l := logger.FromContext(ctx)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		n, err := purge(ctx)
+		if err != nil && ctx.Err() == nil {
+			l.Warn("failed to purge expired idempotency keys", "error", err)
+		} else if n > 0 {
+			l.Debug("expired idempotency keys purged", "count", n)
+		}
+	}
 }
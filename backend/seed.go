@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/iinuma0710/react-go-blog/backend/entity"
+	"github.com/iinuma0710/react-go-blog/backend/seed"
+	"github.com/iinuma0710/react-go-blog/backend/store"
+)
+
+func setupSeed(fs *flag.FlagSet) func(ctx context.Context, e *cmdEnv, args []string) error {
+	opts := seed.Options{Start: time.Date(2023, 1, 1, 9, 0, 0, 0, time.Local)}
+	fs.Int64Var(&opts.Seed, "seed", 1, "乱数のシード (同じ値なら同じデータを生成する)")
+	fs.IntVar(&opts.Users, "users", 10, "ユーザ数 (1 人目は管理者)")
+	fs.IntVar(&opts.Articles, "articles", 100, "記事数")
+	fs.IntVar(&opts.Tags, "tags", 15, "タグ数")
+	fs.IntVar(&opts.Comments, "comments", 5, "記事ごとのコメント数の上限")
+	fs.DurationVar(&opts.Step, "step", 12*time.Hour, "レコードの作成日時の平均の間隔")
+	start := fs.String("start", opts.Start.Format(time.DateOnly), "最初のレコードの作成日 (YYYY-MM-DD)")
+
+	return func(ctx context.Context, e *cmdEnv, args []string) error {
+		if len(args) != 0 || opts.Users < 1 || opts.Articles < 0 || opts.Tags < 0 || opts.Comments < 0 || opts.Step <= 0 {
+			return errUsage
+		}
+		t, err := time.ParseInLocation(time.DateOnly, *start, time.Local)
+		if err != nil {
+			return errUsage
+		}
+		opts.Start = t.Add(9 * time.Hour)
+
+		ctx, _, err = e.setupLogger(ctx, e.stderr)
+		if err != nil {
+			return err
+		}
+		db, cleanup, err := e.openDB(ctx)
+		defer cleanup()
+		if err != nil {
+			return err
+		}
+
+		// 途中で失敗した場合に中途半端なデータが残らないよう、1 つのトランザクションで投入する
+		sd := seed.New(opts)
+		var sum seed.Summary
+		err = store.NewTransactor(db).InTx(ctx, func(ctx context.Context, tx store.ExecQueryer) error {
+			var err error
+			sum, err = sd.Run(ctx, tx, &store.Repository{Clocker: sd.Clocker()})
+			return err
+		})
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(e.stdout, "users: %d (password: %q)\n", sum.Users, seed.Password)
+		for _, s := range entity.ArticleStatuses {
+			fmt.Fprintf(e.stdout, "articles (%s): %d\n", s, sum.Articles[s])
+		}
+		fmt.Fprintf(e.stdout, "tags: %d\ncomments: %d\n", sum.Tags, sum.Comments)
+		return nil
+	}
+}
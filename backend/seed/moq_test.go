@@ -0,0 +1,319 @@
+// Code generated by moq; DO NOT EDIT.
+// github.com/matryer/moq
+
+package seed
+
+import (
+	"context"
+	"github.com/iinuma0710/react-go-blog/backend/entity"
+	"github.com/iinuma0710/react-go-blog/backend/store"
+	"sync"
+)
+
+// Ensure, that RepositoryMock does implement Repository.
+// If this is not the case, regenerate this file with moq.
+var _ Repository = &RepositoryMock{}
+
+// RepositoryMock is a mock implementation of Repository.
+//
+//	func TestSomethingThatUsesRepository(t *testing.T) {
+//
+//		// make and configure a mocked Repository
+//		mockedRepository := &RepositoryMock{
+//			AddArticleFunc: func(ctx context.Context, db store.Execer, a *entity.Article) error {
+//				panic("mock out the AddArticle method")
+//			},
+//			AddArticleTagFunc: func(ctx context.Context, db store.Execer, articleID entity.ArticleID, tagID entity.TagID) error {
+//				panic("mock out the AddArticleTag method")
+//			},
+//			AddCommentFunc: func(ctx context.Context, db store.Execer, c *entity.Comment) error {
+//				panic("mock out the AddComment method")
+//			},
+//			AddTagFunc: func(ctx context.Context, db store.Execer, t *entity.Tag) error {
+//				panic("mock out the AddTag method")
+//			},
+//			RegisterUserFunc: func(ctx context.Context, db store.Execer, u *entity.User) error {
+//				panic("mock out the RegisterUser method")
+//			},
+//		}
+//
+//		// use mockedRepository in code that requires Repository
+//		// and then make assertions.
+//
+//	}
+type RepositoryMock struct {
+	// AddArticleFunc mocks the AddArticle method.
+	AddArticleFunc func(ctx context.Context, db store.Execer, a *entity.Article) error
+
+	// AddArticleTagFunc mocks the AddArticleTag method.
+	AddArticleTagFunc func(ctx context.Context, db store.Execer, articleID entity.ArticleID, tagID entity.TagID) error
+
+	// AddCommentFunc mocks the AddComment method.
+	AddCommentFunc func(ctx context.Context, db store.Execer, c *entity.Comment) error
+
+	// AddTagFunc mocks the AddTag method.
+	AddTagFunc func(ctx context.Context, db store.Execer, t *entity.Tag) error
+
+	// RegisterUserFunc mocks the RegisterUser method.
+	RegisterUserFunc func(ctx context.Context, db store.Execer, u *entity.User) error
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// AddArticle holds details about calls to the AddArticle method.
+		AddArticle []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Db is the db argument value.
+			Db store.Execer
+			// A is the a argument value.
+			A *entity.Article
+		}
+		// AddArticleTag holds details about calls to the AddArticleTag method.
+		AddArticleTag []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Db is the db argument value.
+			Db store.Execer
+			// ArticleID is the articleID argument value.
+			ArticleID entity.ArticleID
+			// TagID is the tagID argument value.
+			TagID entity.TagID
+		}
+		// AddComment holds details about calls to the AddComment method.
+		AddComment []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Db is the db argument value.
+			Db store.Execer
+			// C is the c argument value.
+			C *entity.Comment
+		}
+		// AddTag holds details about calls to the AddTag method.
+		AddTag []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Db is the db argument value.
+			Db store.Execer
+			// T is the t argument value.
+			T *entity.Tag
+		}
+		// RegisterUser holds details about calls to the RegisterUser method.
+		RegisterUser []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Db is the db argument value.
+			Db store.Execer
+			// U is the u argument value.
+			U *entity.User
+		}
+	}
+	lockAddArticle    sync.RWMutex
+	lockAddArticleTag sync.RWMutex
+	lockAddComment    sync.RWMutex
+	lockAddTag        sync.RWMutex
+	lockRegisterUser  sync.RWMutex
+}
+
+// AddArticle calls AddArticleFunc.
+func (mock *RepositoryMock) AddArticle(ctx context.Context, db store.Execer, a *entity.Article) error {
+	if mock.AddArticleFunc == nil {
+		panic("RepositoryMock.AddArticleFunc: method is nil but Repository.AddArticle was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Db  store.Execer
+		A   *entity.Article
+	}{
+		Ctx: ctx,
+		Db:  db,
+		A:   a,
+	}
+	mock.lockAddArticle.Lock()
+	mock.calls.AddArticle = append(mock.calls.AddArticle, callInfo)
+	mock.lockAddArticle.Unlock()
+	return mock.AddArticleFunc(ctx, db, a)
+}
+
+// AddArticleCalls gets all the calls that were made to AddArticle.
+// Check the length with:
+//
+//	len(mockedRepository.AddArticleCalls())
+func (mock *RepositoryMock) AddArticleCalls() []struct {
+	Ctx context.Context
+	Db  store.Execer
+	A   *entity.Article
+} {
+	var calls []struct {
+		Ctx context.Context
+		Db  store.Execer
+		A   *entity.Article
+	}
+	mock.lockAddArticle.RLock()
+	calls = mock.calls.AddArticle
+	mock.lockAddArticle.RUnlock()
+	return calls
+}
+
+// AddArticleTag calls AddArticleTagFunc.
+func (mock *RepositoryMock) AddArticleTag(ctx context.Context, db store.Execer, articleID entity.ArticleID, tagID entity.TagID) error {
+	if mock.AddArticleTagFunc == nil {
+		panic("RepositoryMock.AddArticleTagFunc: method is nil but Repository.AddArticleTag was just called")
+	}
+	callInfo := struct {
+		Ctx       context.Context
+		Db        store.Execer
+		ArticleID entity.ArticleID
+		TagID     entity.TagID
+	}{
+		Ctx:       ctx,
+		Db:        db,
+		ArticleID: articleID,
+		TagID:     tagID,
+	}
+	mock.lockAddArticleTag.Lock()
+	mock.calls.AddArticleTag = append(mock.calls.AddArticleTag, callInfo)
+	mock.lockAddArticleTag.Unlock()
+	return mock.AddArticleTagFunc(ctx, db, articleID, tagID)
+}
+
+// AddArticleTagCalls gets all the calls that were made to AddArticleTag.
+// Check the length with:
+//
+//	len(mockedRepository.AddArticleTagCalls())
+func (mock *RepositoryMock) AddArticleTagCalls() []struct {
+	Ctx       context.Context
+	Db        store.Execer
+	ArticleID entity.ArticleID
+	TagID     entity.TagID
+} {
+	var calls []struct {
+		Ctx       context.Context
+		Db        store.Execer
+		ArticleID entity.ArticleID
+		TagID     entity.TagID
+	}
+	mock.lockAddArticleTag.RLock()
+	calls = mock.calls.AddArticleTag
+	mock.lockAddArticleTag.RUnlock()
+	return calls
+}
+
+// AddComment calls AddCommentFunc.
+func (mock *RepositoryMock) AddComment(ctx context.Context, db store.Execer, c *entity.Comment) error {
+	if mock.AddCommentFunc == nil {
+		panic("RepositoryMock.AddCommentFunc: method is nil but Repository.AddComment was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Db  store.Execer
+		C   *entity.Comment
+	}{
+		Ctx: ctx,
+		Db:  db,
+		C:   c,
+	}
+	mock.lockAddComment.Lock()
+	mock.calls.AddComment = append(mock.calls.AddComment, callInfo)
+	mock.lockAddComment.Unlock()
+	return mock.AddCommentFunc(ctx, db, c)
+}
+
+// AddCommentCalls gets all the calls that were made to AddComment.
+// Check the length with:
+//
+//	len(mockedRepository.AddCommentCalls())
+func (mock *RepositoryMock) AddCommentCalls() []struct {
+	Ctx context.Context
+	Db  store.Execer
+	C   *entity.Comment
+} {
+	var calls []struct {
+		Ctx context.Context
+		Db  store.Execer
+		C   *entity.Comment
+	}
+	mock.lockAddComment.RLock()
+	calls = mock.calls.AddComment
+	mock.lockAddComment.RUnlock()
+	return calls
+}
+
+// AddTag calls AddTagFunc.
+func (mock *RepositoryMock) AddTag(ctx context.Context, db store.Execer, t *entity.Tag) error {
+	if mock.AddTagFunc == nil {
+		panic("RepositoryMock.AddTagFunc: method is nil but Repository.AddTag was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Db  store.Execer
+		T   *entity.Tag
+	}{
+		Ctx: ctx,
+		Db:  db,
+		T:   t,
+	}
+	mock.lockAddTag.Lock()
+	mock.calls.AddTag = append(mock.calls.AddTag, callInfo)
+	mock.lockAddTag.Unlock()
+	return mock.AddTagFunc(ctx, db, t)
+}
+
+// AddTagCalls gets all the calls that were made to AddTag.
+// Check the length with:
+//
+//	len(mockedRepository.AddTagCalls())
+func (mock *RepositoryMock) AddTagCalls() []struct {
+	Ctx context.Context
+	Db  store.Execer
+	T   *entity.Tag
+} {
+	var calls []struct {
+		Ctx context.Context
+		Db  store.Execer
+		T   *entity.Tag
+	}
+	mock.lockAddTag.RLock()
+	calls = mock.calls.AddTag
+	mock.lockAddTag.RUnlock()
+	return calls
+}
+
+// RegisterUser calls RegisterUserFunc.
+func (mock *RepositoryMock) RegisterUser(ctx context.Context, db store.Execer, u *entity.User) error {
+	if mock.RegisterUserFunc == nil {
+		panic("RepositoryMock.RegisterUserFunc: method is nil but Repository.RegisterUser was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Db  store.Execer
+		U   *entity.User
+	}{
+		Ctx: ctx,
+		Db:  db,
+		U:   u,
+	}
+	mock.lockRegisterUser.Lock()
+	mock.calls.RegisterUser = append(mock.calls.RegisterUser, callInfo)
+	mock.lockRegisterUser.Unlock()
+	return mock.RegisterUserFunc(ctx, db, u)
+}
+
+// RegisterUserCalls gets all the calls that were made to RegisterUser.
+// Check the length with:
+//
+//	len(mockedRepository.RegisterUserCalls())
+func (mock *RepositoryMock) RegisterUserCalls() []struct {
+	Ctx context.Context
+	Db  store.Execer
+	U   *entity.User
+} {
+	var calls []struct {
+		Ctx context.Context
+		Db  store.Execer
+		U   *entity.User
+	}
+	mock.lockRegisterUser.RLock()
+	calls = mock.calls.RegisterUser
+	mock.lockRegisterUser.RUnlock()
+	return calls
+}
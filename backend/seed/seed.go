@@ -0,0 +1,214 @@
+package seed
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/iinuma0710/react-go-blog/backend/clock"
+	"github.com/iinuma0710/react-go-blog/backend/entity"
+	"github.com/iinuma0710/react-go-blog/backend/store"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// 生成したユーザのパスワード (開発環境専用)
+const Password = "password"
+
+//go:generate go run github.com/matryer/moq -out moq_test.go . Repository
+type Repository interface {
+	RegisterUser(ctx context.Context, db store.Execer, u *entity.User) error
+	AddArticle(ctx context.Context, db store.Execer, a *entity.Article) error
+	AddTag(ctx context.Context, db store.Execer, t *entity.Tag) error
+	AddArticleTag(ctx context.Context, db store.Execer, articleID entity.ArticleID, tagID entity.TagID) error
+	AddComment(ctx context.Context, db store.Execer, c *entity.Comment) error
+}
+
+// 生成するデータの量と乱数のシード
+type Options struct {
+	Seed     int64
+	Users    int
+	Articles int
+	Tags     int
+	// 記事ごとのコメント数の上限
+	Comments int
+	// 最初のレコードの作成日時と、レコード間の平均の間隔
+	Start time.Time
+	Step  time.Duration
+}
+
+// 生成したレコードの件数
+type Summary struct {
+	Users    int
+	Articles map[entity.ArticleStatus]int
+	Tags     int
+	Comments int
+}
+
+// 同じ Options からは常に同じデータを生成する
+type Seeder struct {
+	opts  Options
+	rng   *rand.Rand
+	clock *Clock
+}
+
+func New(opts Options) *Seeder {
+	rng := rand.New(rand.NewSource(opts.Seed))
+	return &Seeder{
+		opts: opts,
+		rng:  rng,
+		// 時刻の間隔は本文の生成とは別の乱数で決め、件数を変えても先頭の時刻が変わらないようにする
+		clock: &Clock{now: opts.Start, step: opts.Step, rng: rand.New(rand.NewSource(opts.Seed))},
+	}
+}
+
+// レコードの作成日時を決める Clocker
+// Repository の Clocker に設定すると、作成日時が呼び出しごとにずれていく
+func (s *Seeder) Clocker() clock.Clocker {
+	return s.clock
+}
+
+// ユーザ、タグ、記事、コメントの順に生成して保存する
+func (s *Seeder) Run(ctx context.Context, db store.Execer, repo Repository) (Summary, error) {
+	sum := Summary{Articles: map[entity.ArticleStatus]int{}}
+	if s.opts.Users < 1 {
+		return sum, fmt.Errorf("at least one user is required")
+	}
+
+	// ハッシュ化は遅いので、全員で同じハッシュを使う
+	pw, err := bcrypt.GenerateFromPassword([]byte(Password), bcrypt.MinCost)
+	if err != nil {
+		return sum, err
+	}
+	users := make([]*entity.User, s.opts.Users)
+	for i := range users {
+		role := entity.RoleAuthor
+		if i == 0 {
+			role = entity.RoleAdmin
+		}
+		name := fmt.Sprintf("%s%d", userNames[i%len(userNames)], i+1)
+		users[i] = &entity.User{
+			Name:     name,
+			Email:    name + "@example.com",
+			Password: string(pw),
+			Role:     role,
+		}
+		if err := repo.RegisterUser(ctx, db, users[i]); err != nil {
+			return sum, fmt.Errorf("failed to add user %q: %w", name, err)
+		}
+		sum.Users++
+	}
+
+	tags := make([]*entity.Tag, min(s.opts.Tags, len(topics)))
+	for i, j := range s.rng.Perm(len(topics))[:len(tags)] {
+		tags[i] = &entity.Tag{Name: topics[j]}
+		if err := repo.AddTag(ctx, db, tags[i]); err != nil {
+			return sum, fmt.Errorf("failed to add tag %q: %w", tags[i].Name, err)
+		}
+		sum.Tags++
+	}
+
+	for i := 0; i < s.opts.Articles; i++ {
+		title, content := s.text()
+		a := &entity.Article{
+			Title:    title,
+			Content:  content,
+			Status:   s.status(i),
+			AuthorID: users[s.rng.Intn(len(users))].ID,
+		}
+		if err := repo.AddArticle(ctx, db, a); err != nil {
+			return sum, fmt.Errorf("failed to add article: %w", err)
+		}
+		sum.Articles[a.Status]++
+
+		if len(tags) > 0 {
+			for _, j := range s.rng.Perm(len(tags))[:s.rng.Intn(min(3, len(tags))+1)] {
+				if err := repo.AddArticleTag(ctx, db, a.ID, tags[j].ID); err != nil {
+					return sum, fmt.Errorf("failed to tag article %d: %w", a.ID, err)
+				}
+			}
+		}
+
+		// 下書きにはコメントが付かない
+		if a.Status == entity.ArticleDraft || s.opts.Comments < 1 {
+			continue
+		}
+		for n := s.rng.Intn(s.opts.Comments + 1); n > 0; n-- {
+			c := &entity.Comment{
+				ArticleID: a.ID,
+				UserID:    users[s.rng.Intn(len(users))].ID,
+				Body:      s.comment(),
+			}
+			if err := repo.AddComment(ctx, db, c); err != nil {
+				return sum, fmt.Errorf("failed to add comment to article %d: %w", a.ID, err)
+			}
+			sum.Comments++
+		}
+	}
+	return sum, nil
+}
+
+// 先頭の記事ですべてのステータスを網羅し、残りは公開済みを多めにする
+func (s *Seeder) status(i int) entity.ArticleStatus {
+	if i < len(entity.ArticleStatuses) {
+		return entity.ArticleStatuses[i]
+	}
+	switch n := s.rng.Intn(10); {
+	case n < 7:
+		return entity.ArticlePublished
+	case n < 9:
+		return entity.ArticleDraft
+	default:
+		return entity.ArticleWithdrawn
+	}
+}
+
+// 日本語と英語の記事を半々で生成する
+func (s *Seeder) text() (title, content string) {
+	a, b := topics[s.rng.Intn(len(topics))], topics[s.rng.Intn(len(topics))]
+	templates, headings, sentences := enTitleTemplates, enHeadings, enSentences
+	if s.rng.Intn(2) == 0 {
+		templates, headings, sentences = jaTitleTemplates, jaHeadings, jaSentences
+	}
+	title = fmt.Sprintf(templates[s.rng.Intn(len(templates))], a, b)
+
+	var sb strings.Builder
+	for _, h := range headings {
+		fmt.Fprintf(&sb, "## %s\n\n", h)
+		for n := s.rng.Intn(3) + 1; n > 0; n-- {
+			st := sentences[s.rng.Intn(len(sentences))]
+			if strings.Contains(st, "%s") {
+				st = fmt.Sprintf(st, a)
+			}
+			sb.WriteString(st)
+		}
+		sb.WriteString("\n\n")
+	}
+	return title, strings.TrimSuffix(sb.String(), "\n")
+}
+
+func (s *Seeder) comment() string {
+	if s.rng.Intn(2) == 0 {
+		return jaComments[s.rng.Intn(len(jaComments))]
+	}
+	return enComments[s.rng.Intn(len(enComments))]
+}
+
+// 呼び出すたびに平均 step ずつ進む時計
+type Clock struct {
+	mu   sync.Mutex
+	now  time.Time
+	step time.Duration
+	rng  *rand.Rand
+}
+
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now
+	// 0 から 2*step の間でずらし、平均が step になるようにする
+	c.now = c.now.Add(time.Duration(c.rng.Int63n(int64(2*c.step) + 1)))
+	return now
+}
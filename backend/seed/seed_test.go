@@ -0,0 +1,132 @@
+package seed
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/iinuma0710/react-go-blog/backend/entity"
+	"github.com/iinuma0710/react-go-blog/backend/store"
+)
+
+// 投入されたレコードを記録するリポジトリ
+// 作成日時は Seeder の Clocker から取得する
+func recorder(t *testing.T, s *Seeder) (*RepositoryMock, *[]any) {
+	t.Helper()
+	var got []any
+	var id int64
+	next := func() int64 { id++; return id }
+	return &RepositoryMock{
+		RegisterUserFunc: func(ctx context.Context, db store.Execer, u *entity.User) error {
+			u.ID, u.CreatedAt = entity.UserID(next()), s.Clocker().Now()
+			// bcrypt のハッシュはソルトが毎回変わるので比較しない
+			r := *u
+			r.Password = ""
+			got = append(got, r)
+			return nil
+		},
+		AddArticleFunc: func(ctx context.Context, db store.Execer, a *entity.Article) error {
+			a.ID, a.CreatedAt = entity.ArticleID(next()), s.Clocker().Now()
+			got = append(got, *a)
+			return nil
+		},
+		AddTagFunc: func(ctx context.Context, db store.Execer, tg *entity.Tag) error {
+			tg.ID, tg.CreatedAt = entity.TagID(next()), s.Clocker().Now()
+			got = append(got, *tg)
+			return nil
+		},
+		AddArticleTagFunc: func(ctx context.Context, db store.Execer, articleID entity.ArticleID, tagID entity.TagID) error {
+			got = append(got, [2]int64{int64(articleID), int64(tagID)})
+			return nil
+		},
+		AddCommentFunc: func(ctx context.Context, db store.Execer, c *entity.Comment) error {
+			c.ID, c.CreatedAt = entity.CommentID(next()), s.Clocker().Now()
+			got = append(got, *c)
+			return nil
+		},
+	}, &got
+}
+
+func TestSeeder_Run(t *testing.T) {
+	opts := Options{
+		Seed:     42,
+		Users:    3,
+		Articles: 20,
+		Tags:     5,
+		Comments: 4,
+		Start:    time.Date(2023, 1, 1, 9, 0, 0, 0, time.UTC),
+		Step:     time.Hour,
+	}
+
+	run := func() (Summary, []any, *RepositoryMock) {
+		s := New(opts)
+		repo, got := recorder(t, s)
+		sum, err := s.Run(context.Background(), nil, repo)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return sum, *got, repo
+	}
+	sum, got, repo := run()
+
+	// 同じシードからは同じデータが生成される
+	if _, again, _ := run(); !reflect.DeepEqual(got, again) {
+		t.Error("want the same records for the same seed")
+	}
+	other := opts
+	other.Seed = 43
+	s := New(other)
+	orepo, ogot := recorder(t, s)
+	if _, err := s.Run(context.Background(), nil, orepo); err != nil {
+		t.Fatal(err)
+	}
+	if reflect.DeepEqual(got, *ogot) {
+		t.Error("want different records for a different seed")
+	}
+
+	if sum.Users != opts.Users || len(repo.RegisterUserCalls()) != opts.Users {
+		t.Errorf("want %d users, but got %d", opts.Users, sum.Users)
+	}
+	if sum.Tags != opts.Tags || len(repo.AddTagCalls()) != opts.Tags {
+		t.Errorf("want %d tags, but got %d", opts.Tags, sum.Tags)
+	}
+	if sum.Comments != len(repo.AddCommentCalls()) {
+		t.Errorf("want %d comments, but got %d", len(repo.AddCommentCalls()), sum.Comments)
+	}
+	var articles int
+	for _, st := range entity.ArticleStatuses {
+		if sum.Articles[st] == 0 {
+			t.Errorf("want at least one %q article", st)
+		}
+		articles += sum.Articles[st]
+	}
+	if articles != opts.Articles {
+		t.Errorf("want %d articles, but got %d", opts.Articles, articles)
+	}
+	if u := repo.RegisterUserCalls()[0].U; u.Role != entity.RoleAdmin {
+		t.Errorf("want the first user to be admin, but got %q", u.Role)
+	}
+
+	// 作成日時は開始日時から単調に増加する
+	prev := opts.Start
+	for _, r := range got {
+		var at time.Time
+		switch v := r.(type) {
+		case entity.User:
+			at = v.CreatedAt
+		case entity.Article:
+			at = v.CreatedAt
+		case entity.Tag:
+			at = v.CreatedAt
+		case entity.Comment:
+			at = v.CreatedAt
+		default:
+			continue
+		}
+		if at.Before(prev) {
+			t.Fatalf("want increasing timestamps, but got %v after %v", at, prev)
+		}
+		prev = at
+	}
+}
@@ -0,0 +1,75 @@
+package seed
+
+// タイトルやコメントの生成に使う文章の部品
+// このリポジトリで実際に扱っている技術を題材にする
+
+var topics = []string{
+	"Go", "React", "Next.js", "TypeScript", "Docker", "MySQL", "chi", "sqlx",
+	"OpenTelemetry", "Prometheus", "GitHub Actions", "Kubernetes", "slog", "TLS",
+	"HTTP/2", "Markdown", "Tailwind CSS", "Redis", "PostgreSQL", "SQLite",
+}
+
+var jaTitleTemplates = []string{
+	"%sで%sを作る",
+	"%sと%sを組み合わせて使う",
+	"%sの基本から%sとの連携まで",
+	"%sを使った%sの実装メモ",
+	"%sで%sを動かすときにハマったこと",
+	"はじめての%s: %s編",
+}
+
+var enTitleTemplates = []string{
+	"Building %s services with %s",
+	"Getting started with %s and %s",
+	"Lessons learned running %s behind %s",
+	"A practical guide to %s for %s developers",
+	"Debugging %s: notes from a %s project",
+}
+
+var jaSentences = []string{
+	"%s を使うと、少ないコードで必要な機能を実装できます。",
+	"まずは公式ドキュメントの手順に沿って環境を用意します。",
+	"設定ファイルは環境ごとに分けておくと管理しやすくなります。",
+	"エラーが発生した場合は、ログに出力された内容を確認しましょう。",
+	"本番環境ではタイムアウトとリトライの設定を忘れずに行います。",
+	"テストを先に書いておくと、リファクタリングのときに安心です。",
+	"%s のバージョンによって挙動が異なる点に注意が必要です。",
+}
+
+var enSentences = []string{
+	"%s lets you build this with surprisingly little code.",
+	"First, set up the environment by following the official guide.",
+	"Keeping configuration per environment makes it easier to manage.",
+	"When something goes wrong, start by reading the logs carefully.",
+	"In production, always configure timeouts and retries.",
+	"Writing tests first makes refactoring much less scary.",
+	"Behavior may differ between versions of %s, so pin your dependencies.",
+}
+
+var jaHeadings = []string{"はじめに", "環境構築", "実装", "動作確認", "まとめ"}
+
+var enHeadings = []string{"Introduction", "Setup", "Implementation", "Testing", "Wrap-up"}
+
+var jaComments = []string{
+	"とても参考になりました。ありがとうございます。",
+	"同じところでハマっていたので助かりました！",
+	"サンプルコードがそのまま動きました。",
+	"続編を楽しみにしています。",
+	"手元の環境ではバージョンの違いでエラーになりました。補足があると嬉しいです。",
+	"図があるともっと分かりやすいと思います。",
+	"この方法は本番環境でも使えますか？",
+}
+
+var enComments = []string{
+	"Great write-up, thanks for sharing!",
+	"This saved me a lot of time.",
+	"Works on my machine with the latest version as well.",
+	"Could you add a section about error handling?",
+	"I ran into the same issue last week. Nice fix.",
+	"Any plans for a follow-up post?",
+}
+
+var userNames = []string{
+	"taro", "hanako", "yuki", "sora", "ren", "mio", "alice", "bob",
+	"carol", "dave", "erin", "frank", "kenta", "haruka", "daichi", "aoi",
+}
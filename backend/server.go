@@ -2,26 +2,101 @@ package main
 
 import (
 	"context"
-	"log"
+	"crypto/tls"
+	"errors"
 	"net"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
+	"github.com/iinuma0710/react-go-blog/backend/lifecycle"
+	"github.com/iinuma0710/react-go-blog/backend/logger"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
 	"golang.org/x/sync/errgroup"
 )
 
+// シャットダウンの期限が指定されていない場合の既定値
+const defaultShutdownTimeout = 30 * time.Second
+
 type Server struct {
 	srv *http.Server
 	l   net.Listener
+
+	sd      *lifecycle.Shutdown
+	timeout time.Duration
+	reload  func(ctx context.Context)
+	// 同じ段階で停止する管理用などのサーバ
+	others []*Server
 }
 
 func NewServer(l net.Listener, mux http.Handler) *Server {
 	return &Server{
-		srv: &http.Server{Handler: mux},
-		l:   l,
+		srv:     &http.Server{Handler: mux},
+		l:       l,
+		timeout: defaultShutdownTimeout,
+	}
+}
+
+// 終了シグナルを受け取ったときに sd に登録された処理を timeout 以内に実行するようにする
+// HTTP サーバの停止は lifecycle.PhaseHTTP の段階で行われる
+func (s *Server) OnShutdown(sd *lifecycle.Shutdown, timeout time.Duration) {
+	sd.Add(lifecycle.PhaseHTTP, "http", s.Shutdown)
+	s.sd = sd
+	s.timeout = timeout
+}
+
+// 管理用などの追加のサーバを、このサーバと同じ HTTP の段階で停止するようにする
+// シャットダウンが期限を過ぎた場合は、追加のサーバも処理中のリクエストを待たずに接続を閉じる
+// OnShutdown の後に呼び出す
+func (s *Server) Attach(name string, o *Server) {
+	s.sd.Add(lifecycle.PhaseHTTP, name, o.Shutdown)
+	s.others = append(s.others, o)
+}
+
+// TLS で配信するようにする
+// HTTP/2 は ALPN でネゴシエーションされる
+func (s *Server) UseTLS(cfg *tls.Config) {
+	s.srv.TLSConfig = cfg
+}
+
+// TLS を使わずに HTTP/2 のリクエスト (h2c) も受け付けるようにする
+func (s *Server) UseH2C() {
+	s.srv.Handler = h2c.NewHandler(s.srv.Handler, &http2.Server{})
+}
+
+// SIGHUP を受け取るたびに fn を呼び出すようにする
+func (s *Server) OnReload(fn func(ctx context.Context)) {
+	s.reload = fn
+}
+
+// リクエストの受付を開始し、Shutdown が呼ばれるまでブロックする
+func (s *Server) Serve() error {
+	var err error
+	if s.srv.TLSConfig != nil {
+		// 証明書は TLSConfig.GetCertificate から取得する
+		err = s.srv.ServeTLS(s.l, "", "")
+	} else {
+		err = s.srv.Serve(s.l)
 	}
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// Serve と同じだが、ctx が終了した場合は処理中のリクエストを待たずに接続を閉じて戻る
+func (s *Server) ServeContext(ctx context.Context) error {
+	stop := context.AfterFunc(ctx, func() { _ = s.srv.Close() })
+	defer stop()
+	return s.Serve()
+}
+
+// 新しい接続の受付を止め、処理中のリクエストが終わるのを待つ
+func (s *Server) Shutdown(ctx context.Context) error {
+	return s.srv.Shutdown(ctx)
 }
 
 func (s *Server) Run(ctx context.Context) error {
@@ -29,24 +104,57 @@ func (s *Server) Run(ctx context.Context) error {
 	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
 	defer stop()
 
+	l := logger.FromContext(ctx)
+
+	sd := s.sd
+	if sd == nil {
+		sd = &lifecycle.Shutdown{}
+		sd.Add(lifecycle.PhaseHTTP, "http", s.Shutdown)
+	}
+
 	// eg.Go メソッドで HTTP サーバを起動するゴルーチンを立ち上げる
 	eg, ctx := errgroup.WithContext(ctx)
 	eg.Go(func() error {
-		// Server メソッドで HTTP サーバを立ち上げ
-		if err := s.srv.Serve(s.l); err != nil && err != http.ErrServerClosed {
-			log.Printf("failed to close: %+v", err)
+		if err := s.Serve(); err != nil {
+			l.Error("failed to close", "error", err)
 			return err
 		}
 		return nil
 	})
 
-	// チャネルからの終了通知を待機
-	<-ctx.Done()
-	if err := s.srv.Shutdown(context.Background()); err != nil {
-		log.Printf("failed to shutdown: %+v", err)
+	// 終了通知を待つ間、SIGHUP を受け取るたびに設定を再読み込みする
+	hup := make(chan os.Signal, 1)
+	if s.reload != nil {
+		signal.Notify(hup, syscall.SIGHUP)
+		defer signal.Stop(hup)
+	}
+wait:
+	for {
+		select {
+		case <-hup:
+			s.reload(ctx)
+		case <-ctx.Done():
+			// チャネルからの終了通知を受け取ったらシャットダウンに進む
+			break wait
+		}
 	}
+	// 2 回目のシグナルではシャットダウンを待たずに終了できるよう、待ち受けをやめる
+	stop()
+	l.Info("shutting down", "timeout", s.timeout)
 
-	// Go メソッドで起動したゴルーチンの終了を待つ
-	return eg.Wait()
+	sctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), s.timeout)
+	defer cancel()
+	err := sd.Run(sctx)
+	if errors.Is(err, lifecycle.ErrTimeout) {
+		// 期限を過ぎたら処理中のリクエストを待たずに接続を閉じる
+		// 停止する前に期限を過ぎた追加のサーバも閉じないと、Serve から戻らない
+		_ = s.srv.Close()
+		for _, o := range s.others {
+			_ = o.srv.Close()
+		}
+		return err
+	}
 
+	// Go メソッドで起動したゴルーチンの終了を待つ
+	return errors.Join(eg.Wait(), err)
 }
@@ -2,13 +2,24 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"net"
 	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/signal"
+	"syscall"
 	"testing"
+	"time"
 
+	"github.com/iinuma0710/react-go-blog/backend/config"
+	"github.com/iinuma0710/react-go-blog/backend/lifecycle"
+	"golang.org/x/net/http2"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -59,3 +70,227 @@ func TestServer_Run(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestServer_Run_ShutdownTimeout(t *testing.T) {
+	l, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatalf("failed to listen port %v", err)
+	}
+
+	// 期限までに終わらない処理があれば、残りを待たずに Run から戻る
+	var closed bool
+	sd := &lifecycle.Shutdown{}
+	sd.Add(lifecycle.PhaseWorkers, "worker", func(ctx context.Context) error { select {} })
+	sd.Add(lifecycle.PhaseResources, "database", func(ctx context.Context) error {
+		closed = true
+		return nil
+	})
+
+	s := NewServer(l, http.NotFoundHandler())
+	s.OnShutdown(sd, 50*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := s.Run(ctx); !errors.Is(err, lifecycle.ErrTimeout) {
+		t.Errorf("want error %v, but got %v", lifecycle.ErrTimeout, err)
+	}
+	if closed {
+		t.Error("hooks after the deadline should not run")
+	}
+	if _, err := http.Get("http://" + l.Addr().String()); err == nil {
+		t.Error("server should not accept connections after shutdown")
+	}
+}
+
+func TestServer_Run_HTTP2(t *testing.T) {
+	// httptest の自己署名証明書を借りる
+	ts := httptest.NewUnstartedServer(nil)
+	ts.EnableHTTP2 = true
+	ts.StartTLS()
+	cert, pool := ts.TLS.Certificates[0], x509.NewCertPool()
+	pool.AddCert(ts.Certificate())
+	ts.Close()
+
+	tests := map[string]struct {
+		setup  func(s *Server)
+		scheme string
+		client *http.Client
+	}{
+		"tls": {
+			setup: func(s *Server) {
+				s.UseTLS(&tls.Config{Certificates: []tls.Certificate{cert}})
+			},
+			scheme: "https",
+			client: &http.Client{Transport: &http.Transport{
+				TLSClientConfig:   &tls.Config{RootCAs: pool},
+				ForceAttemptHTTP2: true,
+			}},
+		},
+		"h2c": {
+			setup:  func(s *Server) { s.UseH2C() },
+			scheme: "http",
+			client: &http.Client{Transport: &http2.Transport{
+				AllowHTTP: true,
+				DialTLSContext: func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+					var d net.Dialer
+					return d.DialContext(ctx, network, addr)
+				},
+			}},
+		},
+	}
+
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			l, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatalf("failed to listen port %v", err)
+			}
+			s := NewServer(l, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, r.Proto)
+			}))
+			tt.setup(s)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			eg, ctx := errgroup.WithContext(ctx)
+			eg.Go(func() error { return s.Run(ctx) })
+
+			rsp, err := tt.client.Get(fmt.Sprintf("%s://%s/", tt.scheme, l.Addr().String()))
+			if err != nil {
+				t.Fatalf("failed to get: %+v", err)
+			}
+			got, err := io.ReadAll(rsp.Body)
+			rsp.Body.Close()
+			if err != nil {
+				t.Fatalf("failed to read body: %+v", err)
+			}
+			if want := "HTTP/2.0"; string(got) != want {
+				t.Errorf("want %q, but got %q", want, got)
+			}
+
+			cancel()
+			if err := eg.Wait(); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestServer_Run_Reload(t *testing.T) {
+	l, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatalf("failed to listen port %v", err)
+	}
+
+	// Run が待ち受けを始める前に送ってもプロセスが終了しないよう、先に受け取っておく
+	guard := make(chan os.Signal, 1)
+	signal.Notify(guard, syscall.SIGHUP)
+	defer signal.Stop(guard)
+
+	reloaded := make(chan struct{}, 1)
+	s := NewServer(l, http.NotFoundHandler())
+	s.OnReload(func(ctx context.Context) {
+		select {
+		case reloaded <- struct{}{}:
+		default:
+		}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	eg, ctx := errgroup.WithContext(ctx)
+	eg.Go(func() error { return s.Run(ctx) })
+
+	// Run が SIGHUP の待ち受けを始めるまで送り直す
+	tick := time.NewTicker(10 * time.Millisecond)
+	defer tick.Stop()
+	timeout := time.After(5 * time.Second)
+loop:
+	for {
+		if err := syscall.Kill(os.Getpid(), syscall.SIGHUP); err != nil {
+			t.Fatal(err)
+		}
+		select {
+		case <-reloaded:
+			break loop
+		case <-tick.C:
+		case <-timeout:
+			t.Fatal("reload is not called on SIGHUP")
+		}
+	}
+
+	cancel()
+	if err := eg.Wait(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// 空いているポートを返す
+func freePort(t *testing.T) int {
+	t.Helper()
+	l, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port
+}
+
+func TestServe_ShutdownTimeout(t *testing.T) {
+	port, metricsPort := freePort(t), freePort(t)
+	cfg, err := config.Load("", []string{
+		"BACKEND_STORE=memory",
+		fmt.Sprintf("BACKEND_PORT=%d", port),
+		fmt.Sprintf("METRICS_PORT=%d", metricsPort),
+		"SHUTDOWN_TIMEOUT=200ms",
+		"SHUTDOWN_DRAIN_DELAY=0s",
+		"MEDIA_DIR=" + t.TempDir(),
+		"LOG_LEVEL=error",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		done <- serve(ctx, &cmdEnv{cfg: cfg, stdout: io.Discard, stderr: io.Discard})
+	}()
+
+	url := fmt.Sprintf("http://localhost:%d/livez", port)
+	for i := 0; ; i++ {
+		rsp, err := http.Get(url)
+		if err == nil {
+			rsp.Body.Close()
+			break
+		}
+		if i == 50 {
+			t.Fatalf("server did not start: %v", err)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	// リクエストを送り終えない接続は、処理中のまま終わらない
+	conn, err := net.Dial("tcp", fmt.Sprintf("localhost:%d", port))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	if _, err := conn.Write([]byte("GET /livez HTTP/1.1\r\n")); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	// メインのサーバの停止が期限を過ぎても、管理用のサーバを閉じて serve から戻る
+	cancel()
+	select {
+	case err := <-done:
+		if !errors.Is(err, lifecycle.ErrTimeout) {
+			t.Errorf("want error %v, but got %v", lifecycle.ErrTimeout, err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("serve did not return after the shutdown deadline")
+	}
+	if _, err := http.Get(fmt.Sprintf("http://localhost:%d/metrics", metricsPort)); err == nil {
+		t.Error("admin server should not accept connections after shutdown")
+	}
+}
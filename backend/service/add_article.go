@@ -5,24 +5,43 @@ import (
 	"fmt"
 
 	"github.com/iinuma0710/react-go-blog/backend/entity"
+	"github.com/iinuma0710/react-go-blog/backend/logger"
 	"github.com/iinuma0710/react-go-blog/backend/store"
 )
 
 type AddArticle struct {
-	DB   store.Execer
-	Repo ArticleAdder
+	DB    store.Execer
+	Repo  ArticleAdder
+	Cache ArticleInvalidator
+	// 書き込みを実行するトランザクション (nil の場合は DB にそのまま書き込む)
+	Tx Transactor
 }
 
 func (aa *AddArticle) AddArticle(ctx context.Context, title string) (*entity.Article, error) {
+	ctx, span := tracer.Start(ctx, "service.AddArticle")
+	defer span.End()
+
 	a := &entity.Article{
 		Title:  title,
 		Status: entity.ArticleDraft,
 	}
 
-	err := aa.Repo.AddArticle(ctx, aa.DB, a)
+	var err error
+	if aa.Tx != nil {
+		// デッドロックなどで失敗した場合は実行し直す
+		err = aa.Tx.InTx(ctx, func(ctx context.Context, tx store.ExecQueryer) error {
+			return aa.Repo.AddArticle(ctx, tx, a)
+		})
+	} else {
+		err = aa.Repo.AddArticle(ctx, aa.DB, a)
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to resister: %w", err)
+		return nil, spanError(span, fmt.Errorf("failed to resister: %w", err))
+	}
+	if aa.Cache != nil {
+		aa.Cache.InvalidateArticles()
 	}
+	logger.FromContext(ctx).Info("article added", "article_id", a.ID)
 
 	return a, nil
 }
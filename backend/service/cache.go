@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/iinuma0710/react-go-blog/backend/cache"
+	"github.com/iinuma0710/react-go-blog/backend/clock"
+	"github.com/iinuma0710/react-go-blog/backend/entity"
+	"github.com/iinuma0710/react-go-blog/backend/store"
+)
+
+const articleListCacheKey = "articles"
+
+// 記事の読み出し結果をメモリ上にキャッシュする ArticleLister / ArticleGetter のデコレータ
+// 書き込み系のサービスから InvalidateArticles を呼び出してキャッシュを破棄する
+// キャッシュにない場合は常にプライマリから読み出すので、キャッシュを有効にすると記事の読み出しはほとんどレプリカに振り分けられない
+// (遅延したレプリカの古い値を有効期間まで返し続けるよりも、ミスした分の負荷をプライマリで受ける方を選んでいる)
+type ArticleCache struct {
+	Lister ArticleLister
+	Getter ArticleGetter
+
+	list    *cache.LRU[entity.Articles]
+	article *cache.LRU[*entity.Article]
+}
+
+func NewArticleCache(l ArticleLister, g ArticleGetter, size int, ttl time.Duration, c clock.Clocker) *ArticleCache {
+	return &ArticleCache{
+		Lister:  l,
+		Getter:  g,
+		list:    cache.NewLRU[entity.Articles](1, ttl, c),
+		article: cache.NewLRU[*entity.Article](size, ttl, c),
+	}
+}
+
+var (
+	_ ArticleLister      = (*ArticleCache)(nil)
+	_ ArticleGetter      = (*ArticleCache)(nil)
+	_ ArticleInvalidator = (*ArticleCache)(nil)
+)
+
+// キャッシュに格納する値は、書き込み直後に遅延したレプリカから古い値を読み出さないようプライマリから読み出す
+func (ac *ArticleCache) ListArticles(ctx context.Context, db store.Queryer) (entity.Articles, error) {
+	as, err := ac.list.GetOrLoad(ctx, articleListCacheKey, func(ctx context.Context) (entity.Articles, error) {
+		return ac.Lister.ListArticles(store.WithPrimary(ctx), db)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 呼び出し元で変更されてもキャッシュに影響しないようにコピーを返す
+	cp := make(entity.Articles, 0, len(as))
+	for _, a := range as {
+		a := *a
+		cp = append(cp, &a)
+	}
+	return cp, nil
+}
+
+func (ac *ArticleCache) GetArticle(ctx context.Context, db store.Queryer, id entity.ArticleID) (*entity.Article, error) {
+	a, err := ac.article.GetOrLoad(ctx, fmt.Sprint(id), func(ctx context.Context) (*entity.Article, error) {
+		return ac.Getter.GetArticle(store.WithPrimary(ctx), db, id)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	cp := *a
+	return &cp, nil
+}
+
+// 記事一覧と、指定された ID の記事のキャッシュを破棄する
+func (ac *ArticleCache) InvalidateArticles(ids ...entity.ArticleID) {
+	ac.list.Purge()
+	keys := make([]string, 0, len(ids))
+	for _, id := range ids {
+		keys = append(keys, fmt.Sprint(id))
+	}
+	ac.article.Remove(keys...)
+}
+
+// 記事一覧と記事単体のキャッシュの利用状況を返す
+func (ac *ArticleCache) Stats() map[string]cache.Stats {
+	return map[string]cache.Stats{
+		"article_list": ac.list.Stats(),
+		"article":      ac.article.Stats(),
+	}
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/iinuma0710/react-go-blog/backend/clock"
+	"github.com/iinuma0710/react-go-blog/backend/entity"
+	"github.com/iinuma0710/react-go-blog/backend/store"
+	"github.com/jmoiron/sqlx"
+)
+
+func TestArticleCache(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	lister := &ArticleListerMock{
+		ListArticlesFunc: func(ctx context.Context, db store.Queryer) (entity.Articles, error) {
+			return entity.Articles{{ID: 1, Title: "title"}}, nil
+		},
+	}
+	getter := &ArticleGetterMock{
+		GetArticleFunc: func(ctx context.Context, db store.Queryer, id entity.ArticleID) (*entity.Article, error) {
+			return &entity.Article{ID: id, Title: "title"}, nil
+		},
+	}
+	sut := NewArticleCache(lister, getter, 10, time.Minute, clock.FixedClocker{})
+
+	for i := 0; i < 3; i++ {
+		as, err := sut.ListArticles(ctx, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// 返り値を書き換えてもキャッシュには影響しない
+		as[0].Title = "changed"
+		if _, err := sut.GetArticle(ctx, nil, 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := len(lister.ListArticlesCalls()); got != 1 {
+		t.Errorf("want ListArticles to be called once, but got %d", got)
+	}
+	if got := len(getter.GetArticleCalls()); got != 1 {
+		t.Errorf("want GetArticle to be called once, but got %d", got)
+	}
+	as, _ := sut.ListArticles(ctx, nil)
+	if as[0].Title != "title" {
+		t.Errorf("want cached title %q, but got %q", "title", as[0].Title)
+	}
+
+	sut.InvalidateArticles(1)
+	if _, err := sut.ListArticles(ctx, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sut.GetArticle(ctx, nil, 1); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(lister.ListArticlesCalls()); got != 2 {
+		t.Errorf("want ListArticles to be called twice, but got %d", got)
+	}
+	if got := len(getter.GetArticleCalls()); got != 2 {
+		t.Errorf("want GetArticle to be called twice, but got %d", got)
+	}
+
+	if got := sut.Stats()["article"]; got.Hits != 2 || got.Misses != 2 {
+		t.Errorf("unexpected stats: %+v", got)
+	}
+}
+
+// 書き込んだ後のキャッシュの読み込みは、遅延したレプリカではなくプライマリから読み出す
+func TestArticleCache_laggingReplica(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	newDB := func() (*sqlx.DB, sqlmock.Sqlmock) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() {
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+			db.Close()
+		})
+		return sqlx.NewDb(db, store.DriverMySQL), mock
+	}
+	primary, pmock := newDB()
+	replica, rmock := newDB()
+	rmock.ExpectQuery(`SHOW REPLICA STATUS`).
+		WillReturnRows(sqlmock.NewRows([]string{"Seconds_Behind_Source"}).AddRow([]byte("1")))
+	pmock.ExpectExec(`UPDATE article`).WillReturnResult(sqlmock.NewResult(0, 1))
+	pmock.ExpectQuery(`SELECT title FROM article`).WillReturnRows(sqlmock.NewRows([]string{"title"}).AddRow("new"))
+
+	// 遅延が許容範囲内のレプリカでも、書き込みはまだ反映されていない
+	c := &store.Cluster{Primary: primary, Replicas: []*store.Replica{{DB: replica, Name: "replica"}}, MaxLag: 5 * time.Second}
+	c.CheckReplicas(ctx, time.Second)
+	if !c.Replicas[0].Healthy() {
+		t.Fatal("want replica to be healthy")
+	}
+
+	getter := &ArticleGetterMock{
+		GetArticleFunc: func(ctx context.Context, db store.Queryer, id entity.ArticleID) (*entity.Article, error) {
+			a := &entity.Article{ID: id}
+			return a, db.GetContext(ctx, &a.Title, "SELECT title FROM article WHERE id = ?", id)
+		},
+	}
+	sut := NewArticleCache(nil, getter, 10, time.Minute, clock.FixedClocker{})
+
+	// 書き込んだリクエストとは別のリクエスト (セッション) から読み出す
+	if _, err := c.ExecContext(store.WithSession(ctx), "UPDATE article SET title = ? WHERE id = ?", "new", 1); err != nil {
+		t.Fatal(err)
+	}
+	sut.InvalidateArticles(1)
+	got, err := sut.GetArticle(store.WithSession(ctx), c, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Title != "new" {
+		t.Errorf("want title %q, but got %q", "new", got.Title)
+	}
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/iinuma0710/react-go-blog/backend/entity"
+	"github.com/iinuma0710/react-go-blog/backend/markdown"
+	"github.com/iinuma0710/react-go-blog/backend/store"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// すべての記事をステータスによらず Markdown のファイルとして書き出す
+type ExportArticles struct {
+	DB    store.Queryer
+	Repo  ArticleExporter
+	Media markdown.MediaOpener
+}
+
+func (e *ExportArticles) ExportArticles(ctx context.Context, layout markdown.Layout, dst markdown.Dest) (*markdown.Report, error) {
+	ctx, span := tracer.Start(ctx, "service.ExportArticles", trace.WithAttributes(attribute.String("export.layout", string(layout))))
+	defer span.End()
+
+	as, err := e.Repo.ListArticles(ctx, e.DB)
+	if err != nil {
+		return nil, spanError(span, fmt.Errorf("failed to list: %w", err))
+	}
+	tags, err := e.Repo.ListArticleTags(ctx, e.DB)
+	if err != nil {
+		return nil, spanError(span, fmt.Errorf("failed to list tags: %w", err))
+	}
+	users, err := e.Repo.ListUsers(ctx, e.DB)
+	if err != nil {
+		return nil, spanError(span, fmt.Errorf("failed to list users: %w", err))
+	}
+	names := make(map[entity.UserID]string, len(users))
+	for _, u := range users {
+		names[u.ID] = u.Name
+	}
+
+	posts := make([]markdown.Post, 0, len(as))
+	for _, a := range as {
+		posts = append(posts, markdown.Post{Article: a, Author: names[a.AuthorID], Tags: tags[a.ID]})
+	}
+	ex := &markdown.Exporter{Layout: layout, Media: e.Media}
+	rep, err := ex.Export(ctx, posts, dst)
+	if err != nil {
+		return rep, spanError(span, fmt.Errorf("failed to export: %w", err))
+	}
+	span.SetAttributes(attribute.Int("export.articles", rep.Articles), attribute.Int("export.media", rep.Media))
+	return rep, nil
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/iinuma0710/react-go-blog/backend/entity"
+	"github.com/iinuma0710/react-go-blog/backend/markdown"
+	"github.com/iinuma0710/react-go-blog/backend/media"
+	"github.com/iinuma0710/react-go-blog/backend/store"
+)
+
+func TestExportArticles(t *testing.T) {
+	t.Parallel()
+
+	moq := &ArticleExporterMock{
+		ListArticlesFunc: func(ctx context.Context, db store.Queryer) (entity.Articles, error) {
+			return entity.Articles{
+				{ID: 1, Title: "公開済み", Status: entity.ArticlePublished, AuthorID: 2},
+				{ID: 2, Title: "下書き", Status: entity.ArticleDraft, Slug: "draft"},
+			}, nil
+		},
+		ListArticleTagsFunc: func(ctx context.Context, db store.Queryer) (map[entity.ArticleID][]string, error) {
+			return map[entity.ArticleID][]string{1: {"Go"}}, nil
+		},
+		ListUsersFunc: func(ctx context.Context, db store.Queryer) ([]*entity.User, error) {
+			return []*entity.User{{ID: 2, Name: "hanako"}}, nil
+		},
+	}
+
+	dir := t.TempDir()
+	sut := &ExportArticles{Repo: moq, Media: media.Dir(t.TempDir())}
+	rep, err := sut.ExportArticles(context.Background(), markdown.LayoutHugo, markdown.DirDest(dir))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rep.Articles != 2 {
+		t.Errorf("want 2 articles, but got %d", rep.Articles)
+	}
+
+	// 作成者とタグがフロントマターに含まれる
+	b, err := os.ReadFile(filepath.Join(dir, "content", "posts", "article-000001.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"author: hanako\n", "  - Go\n", "draft: false\n"} {
+		if !strings.Contains(string(b), want) {
+			t.Errorf("want %q in:\n%s", want, b)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "content", "posts", "draft.md")); err != nil {
+		t.Errorf("want draft to be exported: %v", err)
+	}
+}
+
+func TestExportArticles_Error(t *testing.T) {
+	t.Parallel()
+
+	errDB := errors.New("db error")
+	moq := &ArticleExporterMock{
+		ListArticlesFunc: func(ctx context.Context, db store.Queryer) (entity.Articles, error) {
+			return nil, errDB
+		},
+	}
+	sut := &ExportArticles{Repo: moq}
+	if _, err := sut.ExportArticles(context.Background(), markdown.LayoutHugo, markdown.DirDest(t.TempDir())); !errors.Is(err, errDB) {
+		t.Errorf("want error %v, but got %v", errDB, err)
+	}
+}
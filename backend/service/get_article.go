@@ -0,0 +1,27 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/iinuma0710/react-go-blog/backend/entity"
+	"github.com/iinuma0710/react-go-blog/backend/store"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type GetArticle struct {
+	DB   store.Queryer
+	Repo ArticleGetter
+}
+
+func (g *GetArticle) GetArticle(ctx context.Context, id entity.ArticleID) (*entity.Article, error) {
+	ctx, span := tracer.Start(ctx, "service.GetArticle", trace.WithAttributes(attribute.Int64("article.id", int64(id))))
+	defer span.End()
+
+	a, err := g.Repo.GetArticle(ctx, g.DB, id)
+	if err != nil {
+		return nil, spanError(span, fmt.Errorf("failed to get: %w", err))
+	}
+	return a, nil
+}
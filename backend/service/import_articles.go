@@ -0,0 +1,423 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"mime"
+	"net/http"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/iinuma0710/react-go-blog/backend/entity"
+	"github.com/iinuma0710/react-go-blog/backend/logger"
+	"github.com/iinuma0710/react-go-blog/backend/markdown"
+	"github.com/iinuma0710/react-go-blog/backend/media"
+	"github.com/iinuma0710/react-go-blog/backend/store"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type ImportAction string
+
+const (
+	ImportCreated   ImportAction = "created"
+	ImportUpdated   ImportAction = "updated"
+	ImportUnchanged ImportAction = "unchanged"
+	ImportFailed    ImportAction = "failed"
+	// 記事として取り込めない項目 (WordPress の固定ページなど)
+	ImportSkipped ImportAction = "skipped"
+)
+
+var ImportActions = []ImportAction{ImportCreated, ImportUpdated, ImportUnchanged, ImportFailed, ImportSkipped}
+
+// 取り込み元ごとの結果
+type ImportResult struct {
+	Source string
+	Action ImportAction
+	// 新規作成をドライランした場合は 0
+	ArticleID entity.ArticleID
+	Title     string
+	Media     int
+	Warnings  []string
+	Err       error
+	// スキップした理由
+	Reason string
+}
+
+type ImportReport struct {
+	DryRun  bool
+	Results []ImportResult
+	// 取り込み元の投稿者から作成したユーザ
+	Users []string
+	// ユーザ名が長すぎて作成できず、記事を既定の作成者に置き換えた投稿者
+	SkippedAuthors []string
+}
+
+func (r *ImportReport) Count(action ImportAction) int {
+	var n int
+	for _, res := range r.Results {
+		if res.Action == action {
+			n++
+		}
+	}
+	return n
+}
+
+// 取り込み元のファイルを記事として保存する
+// 取り込み元のキーごとに記事を 1 件だけ作り、内容が変わっていれば更新する
+type ImportArticles struct {
+	DB    store.ExecQueryer
+	Repo  ArticleImporter
+	Media MediaPutter
+	// 記事ごとの書き込みをまとめるトランザクション (nil の場合は DB にそのまま書き込む)
+	Tx Transactor
+	// 作成者を指定していない記事の作成者のユーザ名 (空の場合は作成者なし)
+	DefaultAuthor string
+	// データベースとメディアストレージに書き込まず、結果だけを報告する
+	DryRun bool
+}
+
+// fsys の中の *.md を取り込む
+// 取り込み元のキーは prefix とファイルのパスをつなげたものにする
+func (im *ImportArticles) ImportMarkdown(ctx context.Context, fsys fs.FS, prefix string) (*ImportReport, error) {
+	ctx, span := tracer.Start(ctx, "service.ImportMarkdown", trace.WithAttributes(attribute.Bool("import.dry_run", im.DryRun)))
+	defer span.End()
+
+	st, err := im.begin(ctx)
+	if err != nil {
+		return nil, spanError(span, err)
+	}
+
+	var names []string
+	err = fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		// .git などの隠しディレクトリは対象外
+		if d.IsDir() && p != "." && strings.HasPrefix(d.Name(), ".") {
+			return fs.SkipDir
+		}
+		if !d.IsDir() && strings.EqualFold(path.Ext(p), ".md") {
+			names = append(names, p)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, spanError(span, fmt.Errorf("failed to walk: %w", err))
+	}
+
+	rep := &ImportReport{DryRun: im.DryRun}
+	for _, name := range names {
+		res := ImportResult{Source: path.Join(prefix, name)}
+		item, err := readMarkdown(fsys, name, &res)
+		if err == nil {
+			err = im.saveInTx(ctx, st, item, &res)
+		}
+		var fe *importError
+		if errors.As(err, &fe) {
+			res.Action, res.Err = ImportFailed, fe.err
+		} else if err != nil {
+			return rep, spanError(span, fmt.Errorf("failed to import %s: %w", res.Source, err))
+		}
+		rep.Results = append(rep.Results, res)
+	}
+
+	span.SetAttributes(attribute.Int("import.created", rep.Count(ImportCreated)), attribute.Int("import.updated", rep.Count(ImportUpdated)))
+	return rep, nil
+}
+
+// 取り込み元ごとの失敗 (他の取り込み元の処理は続ける)
+type importError struct{ err error }
+
+func (e *importError) Error() string { return e.err.Error() }
+
+func failed(format string, args ...any) error {
+	return &importError{fmt.Errorf(format, args...)}
+}
+
+// 取り込む記事と付随する情報
+type importItem struct {
+	checksum string
+	article  *entity.Article
+	author   string
+	tags     []string
+	media    []mediaFile
+	// 記事を作成したときだけ追加する (更新したときは既存のコメントを残す)
+	comments []*entity.Comment
+}
+
+type mediaFile struct {
+	key  string
+	data []byte
+}
+
+// Markdown のファイルを読み込み、相対パスで参照している画像をメディアストレージのキーに置き換える
+func readMarkdown(fsys fs.FS, name string, res *ImportResult) (*importItem, error) {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, failed("cannot read: %w", err)
+	}
+	doc, err := markdown.Parse(path.Base(name), data)
+	if err != nil {
+		return nil, &importError{err}
+	}
+	res.Title = doc.Title
+
+	// ファイルの内容と画像のキー (画像の内容のハッシュ値を含む) から変更を検出する
+	h := sha256.New()
+	h.Write(data)
+	item := &importItem{author: doc.Author, tags: doc.Tags}
+	repl := map[string]string{}
+	for _, ref := range markdown.LocalImageRefs(doc.Body) {
+		p := path.Join(path.Dir(name), ref)
+		if !fs.ValidPath(p) {
+			res.Warnings = append(res.Warnings, fmt.Sprintf("image outside of the directory: %s", ref))
+			continue
+		}
+		b, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			res.Warnings = append(res.Warnings, fmt.Sprintf("image not found: %s", ref))
+			continue
+		}
+		sum := sha256.Sum256(b)
+		key := hex.EncodeToString(sum[:8]) + "/" + path.Base(p)
+		repl[ref] = media.URLPrefix + key
+		item.media = append(item.media, mediaFile{key: key, data: b})
+		h.Write([]byte(key))
+	}
+	item.checksum = hex.EncodeToString(h.Sum(nil))
+	item.article = &entity.Article{
+		Title:     doc.Title,
+		Content:   markdown.ReplaceImageRefs(doc.Body, repl),
+		Slug:      doc.Slug,
+		Status:    doc.Status,
+		CreatedAt: doc.Date,
+	}
+	return item, nil
+}
+
+// 取り込み中に参照するユーザとタグ
+type importState struct {
+	users map[string]entity.UserID
+	// メールアドレスからユーザを探す (取り込んだコメントの投稿者の対応付けに使う)
+	emails map[string]entity.UserID
+	tags   map[string]entity.TagID
+	// 記事ごとのトランザクションで追加したタグ (コミットするまで tags には反映しない)
+	pending map[string]entity.TagID
+}
+
+func (im *ImportArticles) begin(ctx context.Context) (*importState, error) {
+	users, err := im.Repo.ListUsers(ctx, im.DB)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+	tags, err := im.Repo.ListTags(ctx, im.DB)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+	st := &importState{users: map[string]entity.UserID{}, emails: map[string]entity.UserID{}, tags: map[string]entity.TagID{}, pending: map[string]entity.TagID{}}
+	for _, u := range users {
+		st.users[u.Name] = u.ID
+		st.emails[strings.ToLower(u.Email)] = u.ID
+	}
+	for _, t := range tags {
+		st.tags[t.Name] = t.ID
+	}
+	if im.DefaultAuthor != "" {
+		if _, ok := st.user(im.DefaultAuthor); !ok {
+			return nil, fmt.Errorf("default author %q: %w", im.DefaultAuthor, store.ErrNotFound)
+		}
+	}
+	return st, nil
+}
+
+// 記事と付随するタグやコメントを 1 つのトランザクションで保存し、
+// 取り込めなかった記事の書き込みが途中まで残らないようにする
+func (im *ImportArticles) saveInTx(ctx context.Context, st *importState, item *importItem, res *ImportResult) error {
+	if im.Tx == nil {
+		defer st.commitTags()
+		return im.save(ctx, st, item, res)
+	}
+	err := im.Tx.InTx(ctx, func(ctx context.Context, tx store.ExecQueryer) error {
+		// 失敗して実行し直す場合は、取り消されたタグを使わない
+		clear(st.pending)
+		in := *im
+		in.DB = tx
+		return in.save(ctx, st, item, res)
+	})
+	if err != nil {
+		// ロールバックしたタグを後の記事で参照しないよう破棄する
+		clear(st.pending)
+		return err
+	}
+	st.commitTags()
+	return nil
+}
+
+// 名前でユーザを探す
+// ユーザ名は大文字と小文字を区別せずに一意なので、一致しない場合は区別せずに探す
+func (st *importState) user(name string) (entity.UserID, bool) {
+	if id, ok := st.users[name]; ok {
+		return id, true
+	}
+	for n, id := range st.users {
+		if strings.EqualFold(n, name) {
+			return id, true
+		}
+	}
+	return 0, false
+}
+
+// 追加したタグを、以降の記事から参照できるようにする
+func (st *importState) commitTags() {
+	maps.Copy(st.tags, st.pending)
+	clear(st.pending)
+}
+
+func (im *ImportArticles) save(ctx context.Context, st *importState, item *importItem, res *ImportResult) error {
+	a := item.article
+	res.Media = len(item.media)
+
+	author := im.DefaultAuthor
+	if item.author != "" {
+		if _, ok := st.user(item.author); ok {
+			author = item.author
+		} else {
+			res.Warnings = append(res.Warnings, fmt.Sprintf("unknown author: %s", item.author))
+		}
+	}
+	if author != "" {
+		a.AuthorID, _ = st.user(author)
+	}
+
+	src, err := im.Repo.GetArticleSource(ctx, im.DB, res.Source)
+	switch {
+	case errors.Is(err, store.ErrNotFound):
+		res.Action = ImportCreated
+	case err != nil:
+		return err
+	case src.Checksum == item.checksum:
+		res.Action, res.ArticleID = ImportUnchanged, src.ArticleID
+		return nil
+	default:
+		res.Action, res.ArticleID = ImportUpdated, src.ArticleID
+	}
+
+	if im.DryRun {
+		return nil
+	}
+
+	for _, m := range item.media {
+		if err := im.putMedia(ctx, m); err != nil {
+			return err
+		}
+	}
+
+	if res.Action == ImportUpdated {
+		cur, err := im.Repo.GetArticle(ctx, im.DB, res.ArticleID)
+		if errors.Is(err, store.ErrNotFound) {
+			// 記事が削除されている場合は作り直す
+			res.Action, res.ArticleID = ImportCreated, 0
+		} else if err != nil {
+			return err
+		} else {
+			a.ID, a.Version = cur.ID, cur.Version
+			if err := im.Repo.ReplaceArticle(ctx, im.DB, a); errors.Is(err, store.ErrAlreadyExists) {
+				return failed("slug %q is already used by another article", a.Slug)
+			} else if err != nil {
+				return err
+			}
+			if err := im.Repo.DeleteArticleTags(ctx, im.DB, a.ID); err != nil {
+				return err
+			}
+		}
+	}
+	if res.Action == ImportCreated {
+		if err := im.Repo.AddArticle(ctx, im.DB, a); errors.Is(err, store.ErrAlreadyExists) {
+			return failed("slug %q is already used by another article", a.Slug)
+		} else if err != nil {
+			return err
+		}
+		res.ArticleID = a.ID
+
+		for _, c := range item.comments {
+			c.ArticleID = a.ID
+			if err := im.Repo.AddComment(ctx, im.DB, c); err != nil {
+				return err
+			}
+		}
+	}
+
+	ids, err := im.tagIDs(ctx, st, item.tags)
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if err := im.Repo.AddArticleTag(ctx, im.DB, a.ID, id); err != nil {
+			return err
+		}
+	}
+
+	if err := im.Repo.SaveArticleSource(ctx, im.DB, &entity.ArticleSource{
+		Source: res.Source, ArticleID: a.ID, Checksum: item.checksum,
+	}); err != nil {
+		return err
+	}
+	logger.FromContext(ctx).Info("article imported", "source", res.Source, "article_id", a.ID, "action", res.Action)
+	return nil
+}
+
+// タグ名に対応するタグを重複なく返し、存在しないタグは作成する
+func (im *ImportArticles) tagIDs(ctx context.Context, st *importState, names []string) ([]entity.TagID, error) {
+	var ids []entity.TagID
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		id, ok := st.tags[name]
+		if !ok {
+			id, ok = st.pending[name]
+		}
+		if !ok {
+			t := &entity.Tag{Name: name}
+			if err := im.Repo.AddTag(ctx, im.DB, t); err != nil {
+				return nil, fmt.Errorf("failed to add tag %q: %w", name, err)
+			}
+			id, st.pending[name] = t.ID, t.ID
+		}
+		if !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+// キーに内容のハッシュ値を含むので、既に保存されているファイルはそのまま使う
+func (im *ImportArticles) putMedia(ctx context.Context, m mediaFile) error {
+	ct := mime.TypeByExtension(path.Ext(m.key))
+	if ct == "" {
+		ct = http.DetectContentType(m.data)
+	}
+	sum := sha256.Sum256(m.data)
+	err := im.Repo.AddMedia(ctx, im.DB, &entity.Media{
+		Key: m.key, ContentType: ct, Size: int64(len(m.data)), SHA256: hex.EncodeToString(sum[:]),
+	})
+	if errors.Is(err, store.ErrAlreadyExists) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to add media %s: %w", m.key, err)
+	}
+	if err := im.Media.Put(ctx, m.key, bytes.NewReader(m.data)); err != nil {
+		return fmt.Errorf("failed to upload media %s: %w", m.key, err)
+	}
+	return nil
+}
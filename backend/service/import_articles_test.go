@@ -0,0 +1,267 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/iinuma0710/react-go-blog/backend/entity"
+	"github.com/iinuma0710/react-go-blog/backend/store"
+)
+
+// 取り込み結果を保持するリポジトリ
+func importRepo(t *testing.T) (*ArticleImporterMock, map[string]*entity.ArticleSource, map[entity.ArticleID]*entity.Article) {
+	t.Helper()
+	sources := map[string]*entity.ArticleSource{}
+	articles := map[entity.ArticleID]*entity.Article{}
+	var tagID entity.TagID
+	return &ArticleImporterMock{
+		ListUsersFunc: func(ctx context.Context, db store.Queryer) ([]*entity.User, error) {
+			return []*entity.User{{ID: 1, Name: "admin", Email: "admin@example.com"}, {ID: 2, Name: "taro"}}, nil
+		},
+		ListTagsFunc: func(ctx context.Context, db store.Queryer) ([]*entity.Tag, error) {
+			return []*entity.Tag{}, nil
+		},
+		GetArticleSourceFunc: func(ctx context.Context, db store.Queryer, source string) (*entity.ArticleSource, error) {
+			if s, ok := sources[source]; ok {
+				return s, nil
+			}
+			return nil, store.ErrNotFound
+		},
+		SaveArticleSourceFunc: func(ctx context.Context, db store.Execer, s *entity.ArticleSource) error {
+			sources[s.Source] = s
+			return nil
+		},
+		AddArticleFunc: func(ctx context.Context, db store.Execer, a *entity.Article) error {
+			a.ID, a.Version = entity.ArticleID(len(articles)+1), 1
+			articles[a.ID] = a
+			return nil
+		},
+		GetArticleFunc: func(ctx context.Context, db store.Queryer, id entity.ArticleID) (*entity.Article, error) {
+			return articles[id], nil
+		},
+		ReplaceArticleFunc: func(ctx context.Context, db store.Execer, a *entity.Article) error {
+			a.Version++
+			articles[a.ID] = a
+			return nil
+		},
+		AddTagFunc: func(ctx context.Context, db store.Execer, tg *entity.Tag) error {
+			tagID++
+			tg.ID = tagID
+			return nil
+		},
+		AddArticleTagFunc: func(ctx context.Context, db store.Execer, articleID entity.ArticleID, tagID entity.TagID) error {
+			return nil
+		},
+		DeleteArticleTagsFunc: func(ctx context.Context, db store.Execer, articleID entity.ArticleID) error {
+			return nil
+		},
+		AddMediaFunc: func(ctx context.Context, db store.Execer, m *entity.Media) error {
+			return nil
+		},
+		AddCommentFunc: func(ctx context.Context, db store.Execer, c *entity.Comment) error {
+			return nil
+		},
+		RegisterUserFunc: func(ctx context.Context, db store.Execer, u *entity.User) error {
+			u.ID = 3
+			return nil
+		},
+	}, sources, articles
+}
+
+func TestImportArticles_ImportMarkdown(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	fsys := fstest.MapFS{
+		"20240801_開発環境の構築.md": {Data: []byte("# 開発環境の構築\n<img src=\"./images/top.png\" height=450>\n![なし](./images/missing.png)\n")},
+		"go.md":               {Data: []byte("---\ntitle: Go\nauthor: taro\ntags: [Go, Go, Docker]\ndraft: true\n---\n本文\n")},
+		"broken.md":           {Data: []byte("---\ntitle: broken\n")},
+		"images/top.png":      {Data: []byte("\x89PNG")},
+		".git/README.md":      {Data: []byte("# 対象外")},
+	}
+	repo, sources, articles := importRepo(t)
+	var uploaded []string
+	mp := &MediaPutterMock{PutFunc: func(ctx context.Context, key string, r io.Reader) error {
+		uploaded = append(uploaded, key)
+		return nil
+	}}
+	sut := &ImportArticles{Repo: repo, Media: mp, DefaultAuthor: "admin"}
+
+	// ドライランでは何も書き込まない
+	sut.DryRun = true
+	rep, err := sut.ImportMarkdown(ctx, fsys, "memo")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rep.Count(ImportCreated) != 2 || rep.Count(ImportFailed) != 1 {
+		t.Errorf("unexpected dry-run report: %+v", rep.Results)
+	}
+	if len(repo.AddArticleCalls()) != 0 || len(uploaded) != 0 {
+		t.Fatal("want no writes in dry-run")
+	}
+
+	sut.DryRun = false
+	rep, err = sut.ImportMarkdown(ctx, fsys, "memo")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rep.Count(ImportCreated) != 2 || rep.Count(ImportFailed) != 1 || len(rep.Results) != 3 {
+		t.Errorf("unexpected report: %+v", rep.Results)
+	}
+	memo := sources["memo/20240801_開発環境の構築.md"]
+	if memo == nil {
+		t.Fatalf("want source to be saved, but got %v", sources)
+	}
+	a := articles[memo.ArticleID]
+	if a.Title != "開発環境の構築" || a.AuthorID != 1 || a.CreatedAt.IsZero() {
+		t.Errorf("unexpected article: %+v", a)
+	}
+	// 画像はメディアストレージの URL に置き換え、見つからない画像は警告する
+	if len(uploaded) != 1 || !strings.Contains(a.Content, `src="/media/`+uploaded[0]+`"`) {
+		t.Errorf("want image to be uploaded and replaced, but got %v:\n%s", uploaded, a.Content)
+	}
+	for _, res := range rep.Results {
+		if res.Source == "memo/20240801_開発環境の構築.md" && len(res.Warnings) != 1 {
+			t.Errorf("want a warning for the missing image, but got %v", res.Warnings)
+		}
+	}
+	if got := articles[sources["memo/go.md"].ArticleID]; got.AuthorID != 2 || got.Status != entity.ArticleDraft {
+		t.Errorf("unexpected article: %+v", got)
+	}
+	if n := len(repo.AddArticleTagCalls()); n != 2 {
+		t.Errorf("want 2 tags without duplicates, but got %d", n)
+	}
+
+	// 同じ内容を取り込み直しても変更しない
+	rep, err = sut.ImportMarkdown(ctx, fsys, "memo")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rep.Count(ImportUnchanged) != 2 || len(repo.AddArticleCalls()) != 2 {
+		t.Errorf("want articles to be unchanged, but got %+v", rep.Results)
+	}
+
+	// 変更したファイルだけを更新する
+	fsys["go.md"] = &fstest.MapFile{Data: []byte("---\ntitle: Go 入門\n---\n本文\n")}
+	rep, err = sut.ImportMarkdown(ctx, fsys, "memo")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rep.Count(ImportUpdated) != 1 || rep.Count(ImportUnchanged) != 1 {
+		t.Errorf("unexpected report: %+v", rep.Results)
+	}
+	if got := articles[sources["memo/go.md"].ArticleID]; got.Title != "Go 入門" || got.Version != 2 {
+		t.Errorf("unexpected article: %+v", got)
+	}
+}
+
+// 記事ごとにトランザクションの中で保存し、トランザクションの db に書き込む
+func TestImportArticles_ImportMarkdown_Tx(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	fsys := fstest.MapFS{
+		"a.md":      {Data: []byte("---\ntitle: A\nslug: a\ntags: [Go]\n---\n本文\n")},
+		"b.md":      {Data: []byte("---\ntitle: B\nslug: a\n---\n本文\n")},
+		"broken.md": {Data: []byte("---\ntitle: broken\n")},
+	}
+	repo, _, _ := importRepo(t)
+	slugs := map[string]bool{}
+	repo.AddArticleFunc = func(ctx context.Context, db store.Execer, a *entity.Article) error {
+		if slugs[a.Slug] {
+			return store.ErrAlreadyExists
+		}
+		slugs[a.Slug] = true
+		a.ID = entity.ArticleID(len(slugs))
+		return nil
+	}
+	tx := &store.TracedDB{System: "tx"}
+	var rolledBack int
+	tr := &TransactorMock{InTxFunc: func(ctx context.Context, fn func(ctx context.Context, tx store.ExecQueryer) error, opts ...store.TxOption) error {
+		err := fn(ctx, tx)
+		if err != nil {
+			rolledBack++
+		}
+		return err
+	}}
+	sut := &ImportArticles{Repo: repo, Tx: tr}
+
+	rep, err := sut.ImportMarkdown(ctx, fsys, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rep.Count(ImportCreated) != 1 || rep.Count(ImportFailed) != 2 {
+		t.Errorf("unexpected report: %+v", rep.Results)
+	}
+	// 読み込めなかったファイルはトランザクションを開始しない
+	if n := len(tr.InTxCalls()); n != 2 {
+		t.Errorf("want 2 transactions, but got %d", n)
+	}
+	if rolledBack != 1 {
+		t.Errorf("want the conflicting article to be rolled back, but got %d", rolledBack)
+	}
+	for _, c := range repo.AddArticleCalls() {
+		if c.Db != tx {
+			t.Errorf("want article to be added in the transaction, but got %v", c.Db)
+		}
+	}
+	for _, c := range repo.SaveArticleSourceCalls() {
+		if c.Db != tx {
+			t.Errorf("want source to be saved in the transaction, but got %v", c.Db)
+		}
+	}
+}
+
+// デッドロックなどで実行し直したトランザクションでは、取り消されたタグを使わずに追加し直す
+func TestImportArticles_ImportMarkdown_TxRetry(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	fsys := fstest.MapFS{
+		"a.md": {Data: []byte("---\ntitle: A\nslug: a\ntags: [Go]\n---\n本文\n")},
+		"b.md": {Data: []byte("---\ntitle: B\nslug: b\ntags: [Go]\n---\n本文\n")},
+	}
+	repo, _, _ := importRepo(t)
+	errDeadlock := errors.New("deadlock")
+	var saved int
+	repo.SaveArticleSourceFunc = func(ctx context.Context, db store.Execer, s *entity.ArticleSource) error {
+		saved++
+		if saved == 1 {
+			return errDeadlock
+		}
+		return nil
+	}
+	var tagged []entity.TagID
+	repo.AddArticleTagFunc = func(ctx context.Context, db store.Execer, articleID entity.ArticleID, tagID entity.TagID) error {
+		tagged = append(tagged, tagID)
+		return nil
+	}
+	tr := &TransactorMock{InTxFunc: func(ctx context.Context, fn func(ctx context.Context, tx store.ExecQueryer) error, opts ...store.TxOption) error {
+		err := fn(ctx, nil)
+		if errors.Is(err, errDeadlock) {
+			err = fn(ctx, nil)
+		}
+		return err
+	}}
+	sut := &ImportArticles{Repo: repo, Tx: tr}
+
+	rep, err := sut.ImportMarkdown(ctx, fsys, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rep.Count(ImportCreated) != 2 {
+		t.Errorf("unexpected report: %+v", rep.Results)
+	}
+	// 1 回目に追加したタグ (ID 1) はロールバックされている
+	if n := len(repo.AddTagCalls()); n != 2 {
+		t.Errorf("want the tag to be added again after the retry, but got %d calls", n)
+	}
+	if want := []entity.TagID{1, 2, 2}; !slices.Equal(tagged, want) {
+		t.Errorf("want article tags %v, but got %v", want, tagged)
+	}
+}
@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/iinuma0710/react-go-blog/backend/entity"
+	"github.com/iinuma0710/react-go-blog/backend/markdown"
+	"github.com/iinuma0710/react-go-blog/backend/wxr"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// ユーザ名の長さの上限 (user テーブルの name 列に合わせる)
+const maxUserNameLength = 20
+
+// WordPress の投稿のステータスと記事のステータスの対応
+// 予約投稿や非公開の投稿は、公開されないように下書きとして取り込む
+var wordpressStatuses = map[string]entity.ArticleStatus{
+	"publish": entity.ArticlePublished,
+	"draft":   entity.ArticleDraft,
+	"pending": entity.ArticleDraft,
+	"future":  entity.ArticleDraft,
+	"private": entity.ArticleDraft,
+	"trash":   entity.ArticleWithdrawn,
+}
+
+// WordPress のエクスポートファイル (WXR) の投稿を記事として取り込む
+// 投稿者はユーザとして作成し、承認済みのコメントは記事を作成したときに追加する
+func (im *ImportArticles) ImportWXR(ctx context.Context, r io.Reader) (*ImportReport, error) {
+	ctx, span := tracer.Start(ctx, "service.ImportWXR", trace.WithAttributes(attribute.Bool("import.dry_run", im.DryRun)))
+	defer span.End()
+
+	exp, err := wxr.Parse(r)
+	if err != nil {
+		return nil, spanError(span, err)
+	}
+	st, err := im.begin(ctx)
+	if err != nil {
+		return nil, spanError(span, err)
+	}
+
+	rep := &ImportReport{DryRun: im.DryRun}
+	for _, au := range exp.Authors {
+		if err := im.addAuthor(ctx, st, rep, au); err != nil {
+			return rep, spanError(span, err)
+		}
+	}
+
+	for _, it := range exp.Items {
+		res := ImportResult{Source: it.Source(), Title: it.Title}
+		if reason := skipReason(&it); reason != "" {
+			res.Action, res.Reason = ImportSkipped, reason
+			rep.Results = append(rep.Results, res)
+			continue
+		}
+
+		item, err := readWXRItem(&it, st, &res)
+		if err == nil {
+			err = im.saveInTx(ctx, st, item, &res)
+		}
+		var fe *importError
+		if errors.As(err, &fe) {
+			res.Action, res.Err = ImportFailed, fe.err
+		} else if err != nil {
+			return rep, spanError(span, fmt.Errorf("failed to import %s: %w", res.Source, err))
+		}
+		rep.Results = append(rep.Results, res)
+	}
+
+	span.SetAttributes(attribute.Int("import.created", rep.Count(ImportCreated)), attribute.Int("import.skipped", rep.Count(ImportSkipped)))
+	return rep, nil
+}
+
+func skipReason(it *wxr.Item) string {
+	if it.PostType != "post" {
+		return fmt.Sprintf("post type %q is not supported", it.PostType)
+	}
+	if _, ok := wordpressStatuses[it.Status]; !ok {
+		return fmt.Sprintf("status %q is not supported", it.Status)
+	}
+	return ""
+}
+
+// 同じ名前 (大文字と小文字は区別しない) かメールアドレスのユーザがいればそのユーザを使い、いなければ作成する
+// 作成したユーザのパスワードはランダムにするので、ログインするにはパスワードの再設定が必要
+func (im *ImportArticles) addAuthor(ctx context.Context, st *importState, rep *ImportReport, au wxr.Author) error {
+	if id, ok := st.user(au.Login); ok {
+		st.users[au.Login] = id
+		return nil
+	}
+	if id, ok := st.emails[strings.ToLower(au.Email)]; ok && au.Email != "" {
+		st.users[au.Login] = id
+		return nil
+	}
+	// ユーザ名の長さの上限を超える投稿者は作成せず、記事は既定の作成者に置き換える
+	if utf8.RuneCountInString(au.Login) > maxUserNameLength {
+		rep.SkippedAuthors = append(rep.SkippedAuthors, au.Login)
+		return nil
+	}
+	if im.DryRun {
+		st.users[au.Login] = 0
+		rep.Users = append(rep.Users, au.Login)
+		return nil
+	}
+
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return err
+	}
+	pw, err := bcrypt.GenerateFromPassword([]byte(hex.EncodeToString(b)), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	u := &entity.User{Name: au.Login, Email: au.Email, Password: string(pw), Role: entity.RoleAuthor}
+	if err := im.Repo.RegisterUser(ctx, im.DB, u); err != nil {
+		return fmt.Errorf("failed to add user %q: %w", au.Login, err)
+	}
+	st.users[u.Name], st.emails[strings.ToLower(u.Email)] = u.ID, u.ID
+	rep.Users = append(rep.Users, au.Login)
+	return nil
+}
+
+func readWXRItem(it *wxr.Item, st *importState, res *ImportResult) (*importItem, error) {
+	content, raw, err := markdown.FromHTML(it.Content)
+	if err != nil {
+		return nil, failed("cannot convert content: %w", err)
+	}
+	if len(raw) > 0 {
+		res.Warnings = append(res.Warnings, fmt.Sprintf("kept as HTML: %s", strings.Join(raw, ", ")))
+	}
+
+	title := strings.TrimSpace(it.Title)
+	if title == "" {
+		title = fmt.Sprintf("Untitled (%d)", it.PostID)
+		res.Title = title
+		res.Warnings = append(res.Warnings, "empty title")
+	}
+	slug := it.Slug()
+	if utf8.RuneCountInString(slug) > 128 || strings.HasSuffix(slug, "__trashed") {
+		slug = ""
+	}
+	if it.Status != "publish" && it.Status != "draft" && it.Status != "trash" {
+		res.Warnings = append(res.Warnings, fmt.Sprintf("status %q imported as draft", it.Status))
+	}
+
+	// 内容が変わったかどうかはコメントを除いて判定する (コメントは更新しない)
+	snapshot := *it
+	snapshot.Comments = nil
+	b, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(b)
+
+	item := &importItem{
+		checksum: hex.EncodeToString(sum[:]),
+		article: &entity.Article{
+			Title:     title,
+			Content:   content,
+			Slug:      slug,
+			Status:    wordpressStatuses[it.Status],
+			CreatedAt: it.Date(),
+		},
+		author: it.Creator,
+		tags:   it.Tags(),
+	}
+
+	var skipped int
+	for _, c := range it.Comments {
+		if c.Approved != "1" || (c.Type != "" && c.Type != "comment") {
+			skipped++
+			continue
+		}
+		body, _, err := markdown.FromHTML(c.Content)
+		if err != nil {
+			body = c.Content
+		}
+		cm := &entity.Comment{Body: body, CreatedAt: c.CreatedAt()}
+		if id, ok := st.emails[strings.ToLower(c.AuthorEmail)]; ok && c.AuthorEmail != "" && id != 0 {
+			cm.UserID = id
+		} else {
+			cm.AuthorName = c.Author
+		}
+		item.comments = append(item.comments, cm)
+	}
+	if skipped > 0 {
+		res.Warnings = append(res.Warnings, fmt.Sprintf("skipped %d unapproved comments, pingbacks or trackbacks", skipped))
+	}
+	return item, nil
+}
@@ -0,0 +1,163 @@
+package service
+
+import (
+	"context"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/iinuma0710/react-go-blog/backend/entity"
+)
+
+func TestImportArticles_ImportWXR(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	repo, sources, articles := importRepo(t)
+	sut := &ImportArticles{Repo: repo, DefaultAuthor: "admin"}
+	run := func() *ImportReport {
+		t.Helper()
+		f, err := os.Open("../wxr/testdata/export.xml")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		rep, err := sut.ImportWXR(ctx, f)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return rep
+	}
+
+	// ドライランでは投稿者も作成しない
+	sut.DryRun = true
+	rep := run()
+	if rep.Count(ImportCreated) != 3 || rep.Count(ImportSkipped) != 2 || len(rep.Users) != 1 {
+		t.Errorf("unexpected dry-run report: %+v", rep)
+	}
+	if len(repo.RegisterUserCalls()) != 0 || len(repo.AddArticleCalls()) != 0 || len(repo.AddCommentCalls()) != 0 {
+		t.Fatal("want no writes in dry-run")
+	}
+
+	sut.DryRun = false
+	rep = run()
+	if rep.Count(ImportCreated) != 3 || rep.Count(ImportSkipped) != 2 {
+		t.Errorf("unexpected report: %+v", rep.Results)
+	}
+	// メールアドレスが一致する admin は作成しない
+	if calls := repo.RegisterUserCalls(); len(calls) != 1 || calls[0].U.Name != "hanako" || calls[0].U.Role != entity.RoleAuthor {
+		t.Errorf("want only hanako to be registered, but got %+v", calls)
+	}
+
+	want := map[string]struct {
+		title  string
+		slug   string
+		status entity.ArticleStatus
+		author entity.UserID
+	}{
+		"wordpress:https://old.example.com/?p=10": {"Go で HTTP サーバを立てる", "go-http-server", entity.ArticlePublished, 3},
+		"wordpress:https://old.example.com/?p=11": {"日本語のスラッグ", "下書き", entity.ArticleDraft, 1},
+		"wordpress:https://old.example.com/?p=12": {"削除済み", "", entity.ArticleWithdrawn, 1},
+	}
+	for src, w := range want {
+		s := sources[src]
+		if s == nil {
+			t.Errorf("want source %s to be saved", src)
+			continue
+		}
+		a := articles[s.ArticleID]
+		if a.Title != w.title || a.Slug != w.slug || a.Status != w.status || a.AuthorID != w.author || a.CreatedAt.IsZero() {
+			t.Errorf("%s: unexpected article: %+v", src, a)
+		}
+	}
+	if a := articles[sources["wordpress:https://old.example.com/?p=10"].ArticleID]; a.Content != "**net/http** を使います。\n\n<table><tbody><tr><td>1</td></tr></tbody></table>" {
+		t.Errorf("unexpected content: %q", a.Content)
+	}
+
+	// 承認済みのコメントだけを取り込み、登録済みのユーザのコメントはユーザに対応付ける
+	calls := repo.AddCommentCalls()
+	if len(calls) != 2 {
+		t.Fatalf("want 2 comments, but got %d", len(calls))
+	}
+	if c := calls[0].C; c.AuthorName != "読者" || c.UserID != 0 || c.Body != "参考になりました。" {
+		t.Errorf("unexpected comment: %+v", c)
+	}
+	if c := calls[1].C; c.UserID != 1 || c.AuthorName != "" {
+		t.Errorf("unexpected comment: %+v", c)
+	}
+	if n := len(repo.AddArticleTagCalls()); n != 2 {
+		t.Errorf("want 2 tags without uncategorized, but got %d", n)
+	}
+
+	// 取り込み直してもコメントを重複して追加しない
+	rep = run()
+	if rep.Count(ImportUnchanged) != 3 || len(repo.AddCommentCalls()) != 2 {
+		t.Errorf("want articles to be unchanged, but got %+v", rep.Results)
+	}
+}
+
+func TestImportArticles_ImportWXR_authors(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	// importRepo には admin と taro が登録済み
+	const doc = `<?xml version="1.0" encoding="UTF-8" ?>
+<rss version="2.0" xmlns:dc="http://purl.org/dc/elements/1.1/" xmlns:wp="http://wordpress.org/export/1.2/" xmlns:content="http://purl.org/rss/1.0/modules/content/">
+<channel>
+	<wp:author><wp:author_login>Taro</wp:author_login><wp:author_email>taro@old.example.com</wp:author_email></wp:author>
+	<wp:author><wp:author_login>a-very-long-login-name</wp:author_login><wp:author_email>long@example.com</wp:author_email></wp:author>
+	<item>
+		<title>A</title>
+		<dc:creator>Taro</dc:creator>
+		<guid isPermaLink="false">https://old.example.com/?p=1</guid>
+		<content:encoded>本文</content:encoded>
+		<wp:post_id>1</wp:post_id>
+		<wp:post_date>2020-04-01 09:00:00</wp:post_date>
+		<wp:status>publish</wp:status>
+		<wp:post_type>post</wp:post_type>
+	</item>
+	<item>
+		<title>B</title>
+		<dc:creator>a-very-long-login-name</dc:creator>
+		<guid isPermaLink="false">https://old.example.com/?p=2</guid>
+		<content:encoded>本文</content:encoded>
+		<wp:post_id>2</wp:post_id>
+		<wp:post_date>2020-04-01 09:00:00</wp:post_date>
+		<wp:status>publish</wp:status>
+		<wp:post_type>post</wp:post_type>
+	</item>
+</channel>
+</rss>`
+	repo, sources, articles := importRepo(t)
+	sut := &ImportArticles{Repo: repo, DefaultAuthor: "admin"}
+
+	rep, err := sut.ImportWXR(ctx, strings.NewReader(doc))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// 大文字と小文字だけが異なる登録済みのユーザは作成し直さない
+	if n := len(repo.RegisterUserCalls()); n != 0 {
+		t.Errorf("want no users to be registered, but got %d", n)
+	}
+	if len(rep.Users) != 0 {
+		t.Errorf("want no users in the report, but got %v", rep.Users)
+	}
+	// ユーザ名が長すぎる投稿者は報告し、記事は既定の作成者にする
+	if want := []string{"a-very-long-login-name"}; !slices.Equal(rep.SkippedAuthors, want) {
+		t.Errorf("want skipped authors %v, but got %v", want, rep.SkippedAuthors)
+	}
+	for src, want := range map[string]entity.UserID{
+		"wordpress:https://old.example.com/?p=1": 2,
+		"wordpress:https://old.example.com/?p=2": 1,
+	} {
+		s := sources[src]
+		if s == nil {
+			t.Errorf("want source %s to be saved", src)
+			continue
+		}
+		if got := articles[s.ArticleID].AuthorID; got != want {
+			t.Errorf("%s: want author %d, but got %d", src, want, got)
+		}
+	}
+}
@@ -2,12 +2,13 @@ package service
 
 import (
 	"context"
+	"io"
 
 	"github.com/iinuma0710/react-go-blog/backend/entity"
 	"github.com/iinuma0710/react-go-blog/backend/store"
 )
 
-//go:generate go run github.com/matryer/moq -out moq_test.go . ArticleAdder ArticleLister
+//go:generate go run github.com/matryer/moq -out moq_test.go . ArticleAdder ArticleLister ArticleGetter ArticleUpdater ArticleInvalidator UserRegister ArticleExporter ArticleImporter MediaPutter Transactor
 type ArticleAdder interface {
 	AddArticle(ctx context.Context, db store.Execer, a *entity.Article) error
 }
@@ -15,3 +16,55 @@ type ArticleAdder interface {
 type ArticleLister interface {
 	ListArticles(ctx context.Context, db store.Queryer) (entity.Articles, error)
 }
+
+type ArticleGetter interface {
+	GetArticle(ctx context.Context, db store.Queryer, id entity.ArticleID) (*entity.Article, error)
+}
+
+type ArticleUpdater interface {
+	ArticleGetter
+	UpdateArticle(ctx context.Context, db store.Execer, a *entity.Article) error
+}
+
+// 記事を書き込んだ後に、読み出し結果のキャッシュを破棄する
+type ArticleInvalidator interface {
+	InvalidateArticles(ids ...entity.ArticleID)
+}
+
+type UserRegister interface {
+	RegisterUser(ctx context.Context, db store.Execer, u *entity.User) error
+}
+
+// 書き出しに必要な記事と付随する情報をまとめて取得する
+type ArticleExporter interface {
+	ArticleLister
+	ListArticleTags(ctx context.Context, db store.Queryer) (map[entity.ArticleID][]string, error)
+	ListUsers(ctx context.Context, db store.Queryer) ([]*entity.User, error)
+}
+
+// 取り込んだ記事を保存する
+type ArticleImporter interface {
+	ArticleAdder
+	ArticleGetter
+	ReplaceArticle(ctx context.Context, db store.Execer, a *entity.Article) error
+	ListUsers(ctx context.Context, db store.Queryer) ([]*entity.User, error)
+	ListTags(ctx context.Context, db store.Queryer) ([]*entity.Tag, error)
+	AddTag(ctx context.Context, db store.Execer, t *entity.Tag) error
+	AddArticleTag(ctx context.Context, db store.Execer, articleID entity.ArticleID, tagID entity.TagID) error
+	DeleteArticleTags(ctx context.Context, db store.Execer, articleID entity.ArticleID) error
+	AddMedia(ctx context.Context, db store.Execer, m *entity.Media) error
+	GetArticleSource(ctx context.Context, db store.Queryer, source string) (*entity.ArticleSource, error)
+	SaveArticleSource(ctx context.Context, db store.Execer, s *entity.ArticleSource) error
+	AddComment(ctx context.Context, db store.Execer, c *entity.Comment) error
+	UserRegister
+}
+
+type MediaPutter interface {
+	Put(ctx context.Context, key string, r io.Reader) error
+}
+
+// 複数の書き込みを 1 つのトランザクションで実行する (store.Transactor または store.MemoryRepository)
+// fn の中で呼び出した場合は、外側のトランザクションの中のセーブポイントになる
+type Transactor interface {
+	InTx(ctx context.Context, fn func(ctx context.Context, tx store.ExecQueryer) error, opts ...store.TxOption) error
+}
@@ -14,9 +14,12 @@ type ListArticle struct {
 }
 
 func (l *ListArticle) ListArticles(ctx context.Context) (entity.Articles, error) {
+	ctx, span := tracer.Start(ctx, "service.ListArticles")
+	defer span.End()
+
 	as, err := l.Repo.ListArticles(ctx, l.DB)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list: %w", err)
+		return nil, spanError(span, fmt.Errorf("failed to list: %w", err))
 	}
 	return as, nil
 }
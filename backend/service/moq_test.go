@@ -7,6 +7,7 @@ import (
 	"context"
 	"github.com/iinuma0710/react-go-blog/backend/entity"
 	"github.com/iinuma0710/react-go-blog/backend/store"
+	"io"
 	"sync"
 )
 
@@ -159,3 +160,1431 @@ func (mock *ArticleListerMock) ListArticlesCalls() []struct {
 	mock.lockListArticles.RUnlock()
 	return calls
 }
+
+// Ensure, that ArticleGetterMock does implement ArticleGetter.
+// If this is not the case, regenerate this file with moq.
+var _ ArticleGetter = &ArticleGetterMock{}
+
+// ArticleGetterMock is a mock implementation of ArticleGetter.
+//
+//	func TestSomethingThatUsesArticleGetter(t *testing.T) {
+//
+//		// make and configure a mocked ArticleGetter
+//		mockedArticleGetter := &ArticleGetterMock{
+//			GetArticleFunc: func(ctx context.Context, db store.Queryer, id entity.ArticleID) (*entity.Article, error) {
+//				panic("mock out the GetArticle method")
+//			},
+//		}
+//
+//		// use mockedArticleGetter in code that requires ArticleGetter
+//		// and then make assertions.
+//
+//	}
+type ArticleGetterMock struct {
+	// GetArticleFunc mocks the GetArticle method.
+	GetArticleFunc func(ctx context.Context, db store.Queryer, id entity.ArticleID) (*entity.Article, error)
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// GetArticle holds details about calls to the GetArticle method.
+		GetArticle []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Db is the db argument value.
+			Db store.Queryer
+			// ID is the id argument value.
+			ID entity.ArticleID
+		}
+	}
+	lockGetArticle sync.RWMutex
+}
+
+// GetArticle calls GetArticleFunc.
+func (mock *ArticleGetterMock) GetArticle(ctx context.Context, db store.Queryer, id entity.ArticleID) (*entity.Article, error) {
+	if mock.GetArticleFunc == nil {
+		panic("ArticleGetterMock.GetArticleFunc: method is nil but ArticleGetter.GetArticle was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Db  store.Queryer
+		ID  entity.ArticleID
+	}{
+		Ctx: ctx,
+		Db:  db,
+		ID:  id,
+	}
+	mock.lockGetArticle.Lock()
+	mock.calls.GetArticle = append(mock.calls.GetArticle, callInfo)
+	mock.lockGetArticle.Unlock()
+	return mock.GetArticleFunc(ctx, db, id)
+}
+
+// GetArticleCalls gets all the calls that were made to GetArticle.
+// Check the length with:
+//
+//	len(mockedArticleGetter.GetArticleCalls())
+func (mock *ArticleGetterMock) GetArticleCalls() []struct {
+	Ctx context.Context
+	Db  store.Queryer
+	ID  entity.ArticleID
+} {
+	var calls []struct {
+		Ctx context.Context
+		Db  store.Queryer
+		ID  entity.ArticleID
+	}
+	mock.lockGetArticle.RLock()
+	calls = mock.calls.GetArticle
+	mock.lockGetArticle.RUnlock()
+	return calls
+}
+
+// Ensure, that ArticleUpdaterMock does implement ArticleUpdater.
+// If this is not the case, regenerate this file with moq.
+var _ ArticleUpdater = &ArticleUpdaterMock{}
+
+// ArticleUpdaterMock is a mock implementation of ArticleUpdater.
+//
+//	func TestSomethingThatUsesArticleUpdater(t *testing.T) {
+//
+//		// make and configure a mocked ArticleUpdater
+//		mockedArticleUpdater := &ArticleUpdaterMock{
+//			GetArticleFunc: func(ctx context.Context, db store.Queryer, id entity.ArticleID) (*entity.Article, error) {
+//				panic("mock out the GetArticle method")
+//			},
+//			UpdateArticleFunc: func(ctx context.Context, db store.Execer, a *entity.Article) error {
+//				panic("mock out the UpdateArticle method")
+//			},
+//		}
+//
+//		// use mockedArticleUpdater in code that requires ArticleUpdater
+//		// and then make assertions.
+//
+//	}
+type ArticleUpdaterMock struct {
+	// GetArticleFunc mocks the GetArticle method.
+	GetArticleFunc func(ctx context.Context, db store.Queryer, id entity.ArticleID) (*entity.Article, error)
+
+	// UpdateArticleFunc mocks the UpdateArticle method.
+	UpdateArticleFunc func(ctx context.Context, db store.Execer, a *entity.Article) error
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// GetArticle holds details about calls to the GetArticle method.
+		GetArticle []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Db is the db argument value.
+			Db store.Queryer
+			// ID is the id argument value.
+			ID entity.ArticleID
+		}
+		// UpdateArticle holds details about calls to the UpdateArticle method.
+		UpdateArticle []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Db is the db argument value.
+			Db store.Execer
+			// A is the a argument value.
+			A *entity.Article
+		}
+	}
+	lockGetArticle    sync.RWMutex
+	lockUpdateArticle sync.RWMutex
+}
+
+// GetArticle calls GetArticleFunc.
+func (mock *ArticleUpdaterMock) GetArticle(ctx context.Context, db store.Queryer, id entity.ArticleID) (*entity.Article, error) {
+	if mock.GetArticleFunc == nil {
+		panic("ArticleUpdaterMock.GetArticleFunc: method is nil but ArticleUpdater.GetArticle was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Db  store.Queryer
+		ID  entity.ArticleID
+	}{
+		Ctx: ctx,
+		Db:  db,
+		ID:  id,
+	}
+	mock.lockGetArticle.Lock()
+	mock.calls.GetArticle = append(mock.calls.GetArticle, callInfo)
+	mock.lockGetArticle.Unlock()
+	return mock.GetArticleFunc(ctx, db, id)
+}
+
+// GetArticleCalls gets all the calls that were made to GetArticle.
+// Check the length with:
+//
+//	len(mockedArticleUpdater.GetArticleCalls())
+func (mock *ArticleUpdaterMock) GetArticleCalls() []struct {
+	Ctx context.Context
+	Db  store.Queryer
+	ID  entity.ArticleID
+} {
+	var calls []struct {
+		Ctx context.Context
+		Db  store.Queryer
+		ID  entity.ArticleID
+	}
+	mock.lockGetArticle.RLock()
+	calls = mock.calls.GetArticle
+	mock.lockGetArticle.RUnlock()
+	return calls
+}
+
+// UpdateArticle calls UpdateArticleFunc.
+func (mock *ArticleUpdaterMock) UpdateArticle(ctx context.Context, db store.Execer, a *entity.Article) error {
+	if mock.UpdateArticleFunc == nil {
+		panic("ArticleUpdaterMock.UpdateArticleFunc: method is nil but ArticleUpdater.UpdateArticle was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Db  store.Execer
+		A   *entity.Article
+	}{
+		Ctx: ctx,
+		Db:  db,
+		A:   a,
+	}
+	mock.lockUpdateArticle.Lock()
+	mock.calls.UpdateArticle = append(mock.calls.UpdateArticle, callInfo)
+	mock.lockUpdateArticle.Unlock()
+	return mock.UpdateArticleFunc(ctx, db, a)
+}
+
+// UpdateArticleCalls gets all the calls that were made to UpdateArticle.
+// Check the length with:
+//
+//	len(mockedArticleUpdater.UpdateArticleCalls())
+func (mock *ArticleUpdaterMock) UpdateArticleCalls() []struct {
+	Ctx context.Context
+	Db  store.Execer
+	A   *entity.Article
+} {
+	var calls []struct {
+		Ctx context.Context
+		Db  store.Execer
+		A   *entity.Article
+	}
+	mock.lockUpdateArticle.RLock()
+	calls = mock.calls.UpdateArticle
+	mock.lockUpdateArticle.RUnlock()
+	return calls
+}
+
+// Ensure, that ArticleInvalidatorMock does implement ArticleInvalidator.
+// If this is not the case, regenerate this file with moq.
+var _ ArticleInvalidator = &ArticleInvalidatorMock{}
+
+// ArticleInvalidatorMock is a mock implementation of ArticleInvalidator.
+//
+//	func TestSomethingThatUsesArticleInvalidator(t *testing.T) {
+//
+//		// make and configure a mocked ArticleInvalidator
+//		mockedArticleInvalidator := &ArticleInvalidatorMock{
+//			InvalidateArticlesFunc: func(ids ...entity.ArticleID)  {
+//				panic("mock out the InvalidateArticles method")
+//			},
+//		}
+//
+//		// use mockedArticleInvalidator in code that requires ArticleInvalidator
+//		// and then make assertions.
+//
+//	}
+type ArticleInvalidatorMock struct {
+	// InvalidateArticlesFunc mocks the InvalidateArticles method.
+	InvalidateArticlesFunc func(ids ...entity.ArticleID)
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// InvalidateArticles holds details about calls to the InvalidateArticles method.
+		InvalidateArticles []struct {
+			// Ids is the ids argument value.
+			Ids []entity.ArticleID
+		}
+	}
+	lockInvalidateArticles sync.RWMutex
+}
+
+// InvalidateArticles calls InvalidateArticlesFunc.
+func (mock *ArticleInvalidatorMock) InvalidateArticles(ids ...entity.ArticleID) {
+	if mock.InvalidateArticlesFunc == nil {
+		panic("ArticleInvalidatorMock.InvalidateArticlesFunc: method is nil but ArticleInvalidator.InvalidateArticles was just called")
+	}
+	callInfo := struct {
+		Ids []entity.ArticleID
+	}{
+		Ids: ids,
+	}
+	mock.lockInvalidateArticles.Lock()
+	mock.calls.InvalidateArticles = append(mock.calls.InvalidateArticles, callInfo)
+	mock.lockInvalidateArticles.Unlock()
+	mock.InvalidateArticlesFunc(ids...)
+}
+
+// InvalidateArticlesCalls gets all the calls that were made to InvalidateArticles.
+// Check the length with:
+//
+//	len(mockedArticleInvalidator.InvalidateArticlesCalls())
+func (mock *ArticleInvalidatorMock) InvalidateArticlesCalls() []struct {
+	Ids []entity.ArticleID
+} {
+	var calls []struct {
+		Ids []entity.ArticleID
+	}
+	mock.lockInvalidateArticles.RLock()
+	calls = mock.calls.InvalidateArticles
+	mock.lockInvalidateArticles.RUnlock()
+	return calls
+}
+
+// Ensure, that UserRegisterMock does implement UserRegister.
+// If this is not the case, regenerate this file with moq.
+var _ UserRegister = &UserRegisterMock{}
+
+// UserRegisterMock is a mock implementation of UserRegister.
+//
+//	func TestSomethingThatUsesUserRegister(t *testing.T) {
+//
+//		// make and configure a mocked UserRegister
+//		mockedUserRegister := &UserRegisterMock{
+//			RegisterUserFunc: func(ctx context.Context, db store.Execer, u *entity.User) error {
+//				panic("mock out the RegisterUser method")
+//			},
+//		}
+//
+//		// use mockedUserRegister in code that requires UserRegister
+//		// and then make assertions.
+//
+//	}
+type UserRegisterMock struct {
+	// RegisterUserFunc mocks the RegisterUser method.
+	RegisterUserFunc func(ctx context.Context, db store.Execer, u *entity.User) error
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// RegisterUser holds details about calls to the RegisterUser method.
+		RegisterUser []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Db is the db argument value.
+			Db store.Execer
+			// U is the u argument value.
+			U *entity.User
+		}
+	}
+	lockRegisterUser sync.RWMutex
+}
+
+// RegisterUser calls RegisterUserFunc.
+func (mock *UserRegisterMock) RegisterUser(ctx context.Context, db store.Execer, u *entity.User) error {
+	if mock.RegisterUserFunc == nil {
+		panic("UserRegisterMock.RegisterUserFunc: method is nil but UserRegister.RegisterUser was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Db  store.Execer
+		U   *entity.User
+	}{
+		Ctx: ctx,
+		Db:  db,
+		U:   u,
+	}
+	mock.lockRegisterUser.Lock()
+	mock.calls.RegisterUser = append(mock.calls.RegisterUser, callInfo)
+	mock.lockRegisterUser.Unlock()
+	return mock.RegisterUserFunc(ctx, db, u)
+}
+
+// RegisterUserCalls gets all the calls that were made to RegisterUser.
+// Check the length with:
+//
+//	len(mockedUserRegister.RegisterUserCalls())
+func (mock *UserRegisterMock) RegisterUserCalls() []struct {
+	Ctx context.Context
+	Db  store.Execer
+	U   *entity.User
+} {
+	var calls []struct {
+		Ctx context.Context
+		Db  store.Execer
+		U   *entity.User
+	}
+	mock.lockRegisterUser.RLock()
+	calls = mock.calls.RegisterUser
+	mock.lockRegisterUser.RUnlock()
+	return calls
+}
+
+// Ensure, that ArticleExporterMock does implement ArticleExporter.
+// If this is not the case, regenerate this file with moq.
+var _ ArticleExporter = &ArticleExporterMock{}
+
+// ArticleExporterMock is a mock implementation of ArticleExporter.
+//
+//	func TestSomethingThatUsesArticleExporter(t *testing.T) {
+//
+//		// make and configure a mocked ArticleExporter
+//		mockedArticleExporter := &ArticleExporterMock{
+//			ListArticleTagsFunc: func(ctx context.Context, db store.Queryer) (map[entity.ArticleID][]string, error) {
+//				panic("mock out the ListArticleTags method")
+//			},
+//			ListArticlesFunc: func(ctx context.Context, db store.Queryer) (entity.Articles, error) {
+//				panic("mock out the ListArticles method")
+//			},
+//			ListUsersFunc: func(ctx context.Context, db store.Queryer) ([]*entity.User, error) {
+//				panic("mock out the ListUsers method")
+//			},
+//		}
+//
+//		// use mockedArticleExporter in code that requires ArticleExporter
+//		// and then make assertions.
+//
+//	}
+type ArticleExporterMock struct {
+	// ListArticleTagsFunc mocks the ListArticleTags method.
+	ListArticleTagsFunc func(ctx context.Context, db store.Queryer) (map[entity.ArticleID][]string, error)
+
+	// ListArticlesFunc mocks the ListArticles method.
+	ListArticlesFunc func(ctx context.Context, db store.Queryer) (entity.Articles, error)
+
+	// ListUsersFunc mocks the ListUsers method.
+	ListUsersFunc func(ctx context.Context, db store.Queryer) ([]*entity.User, error)
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// ListArticleTags holds details about calls to the ListArticleTags method.
+		ListArticleTags []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Db is the db argument value.
+			Db store.Queryer
+		}
+		// ListArticles holds details about calls to the ListArticles method.
+		ListArticles []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Db is the db argument value.
+			Db store.Queryer
+		}
+		// ListUsers holds details about calls to the ListUsers method.
+		ListUsers []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Db is the db argument value.
+			Db store.Queryer
+		}
+	}
+	lockListArticleTags sync.RWMutex
+	lockListArticles    sync.RWMutex
+	lockListUsers       sync.RWMutex
+}
+
+// ListArticleTags calls ListArticleTagsFunc.
+func (mock *ArticleExporterMock) ListArticleTags(ctx context.Context, db store.Queryer) (map[entity.ArticleID][]string, error) {
+	if mock.ListArticleTagsFunc == nil {
+		panic("ArticleExporterMock.ListArticleTagsFunc: method is nil but ArticleExporter.ListArticleTags was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Db  store.Queryer
+	}{
+		Ctx: ctx,
+		Db:  db,
+	}
+	mock.lockListArticleTags.Lock()
+	mock.calls.ListArticleTags = append(mock.calls.ListArticleTags, callInfo)
+	mock.lockListArticleTags.Unlock()
+	return mock.ListArticleTagsFunc(ctx, db)
+}
+
+// ListArticleTagsCalls gets all the calls that were made to ListArticleTags.
+// Check the length with:
+//
+//	len(mockedArticleExporter.ListArticleTagsCalls())
+func (mock *ArticleExporterMock) ListArticleTagsCalls() []struct {
+	Ctx context.Context
+	Db  store.Queryer
+} {
+	var calls []struct {
+		Ctx context.Context
+		Db  store.Queryer
+	}
+	mock.lockListArticleTags.RLock()
+	calls = mock.calls.ListArticleTags
+	mock.lockListArticleTags.RUnlock()
+	return calls
+}
+
+// ListArticles calls ListArticlesFunc.
+func (mock *ArticleExporterMock) ListArticles(ctx context.Context, db store.Queryer) (entity.Articles, error) {
+	if mock.ListArticlesFunc == nil {
+		panic("ArticleExporterMock.ListArticlesFunc: method is nil but ArticleExporter.ListArticles was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Db  store.Queryer
+	}{
+		Ctx: ctx,
+		Db:  db,
+	}
+	mock.lockListArticles.Lock()
+	mock.calls.ListArticles = append(mock.calls.ListArticles, callInfo)
+	mock.lockListArticles.Unlock()
+	return mock.ListArticlesFunc(ctx, db)
+}
+
+// ListArticlesCalls gets all the calls that were made to ListArticles.
+// Check the length with:
+//
+//	len(mockedArticleExporter.ListArticlesCalls())
+func (mock *ArticleExporterMock) ListArticlesCalls() []struct {
+	Ctx context.Context
+	Db  store.Queryer
+} {
+	var calls []struct {
+		Ctx context.Context
+		Db  store.Queryer
+	}
+	mock.lockListArticles.RLock()
+	calls = mock.calls.ListArticles
+	mock.lockListArticles.RUnlock()
+	return calls
+}
+
+// ListUsers calls ListUsersFunc.
+func (mock *ArticleExporterMock) ListUsers(ctx context.Context, db store.Queryer) ([]*entity.User, error) {
+	if mock.ListUsersFunc == nil {
+		panic("ArticleExporterMock.ListUsersFunc: method is nil but ArticleExporter.ListUsers was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Db  store.Queryer
+	}{
+		Ctx: ctx,
+		Db:  db,
+	}
+	mock.lockListUsers.Lock()
+	mock.calls.ListUsers = append(mock.calls.ListUsers, callInfo)
+	mock.lockListUsers.Unlock()
+	return mock.ListUsersFunc(ctx, db)
+}
+
+// ListUsersCalls gets all the calls that were made to ListUsers.
+// Check the length with:
+//
+//	len(mockedArticleExporter.ListUsersCalls())
+func (mock *ArticleExporterMock) ListUsersCalls() []struct {
+	Ctx context.Context
+	Db  store.Queryer
+} {
+	var calls []struct {
+		Ctx context.Context
+		Db  store.Queryer
+	}
+	mock.lockListUsers.RLock()
+	calls = mock.calls.ListUsers
+	mock.lockListUsers.RUnlock()
+	return calls
+}
+
+// Ensure, that ArticleImporterMock does implement ArticleImporter.
+// If this is not the case, regenerate this file with moq.
+var _ ArticleImporter = &ArticleImporterMock{}
+
+// ArticleImporterMock is a mock implementation of ArticleImporter.
+//
+//	func TestSomethingThatUsesArticleImporter(t *testing.T) {
+//
+//		// make and configure a mocked ArticleImporter
+//		mockedArticleImporter := &ArticleImporterMock{
+//			AddArticleFunc: func(ctx context.Context, db store.Execer, a *entity.Article) error {
+//				panic("mock out the AddArticle method")
+//			},
+//			AddArticleTagFunc: func(ctx context.Context, db store.Execer, articleID entity.ArticleID, tagID entity.TagID) error {
+//				panic("mock out the AddArticleTag method")
+//			},
+//			AddCommentFunc: func(ctx context.Context, db store.Execer, c *entity.Comment) error {
+//				panic("mock out the AddComment method")
+//			},
+//			AddMediaFunc: func(ctx context.Context, db store.Execer, m *entity.Media) error {
+//				panic("mock out the AddMedia method")
+//			},
+//			AddTagFunc: func(ctx context.Context, db store.Execer, t *entity.Tag) error {
+//				panic("mock out the AddTag method")
+//			},
+//			DeleteArticleTagsFunc: func(ctx context.Context, db store.Execer, articleID entity.ArticleID) error {
+//				panic("mock out the DeleteArticleTags method")
+//			},
+//			GetArticleFunc: func(ctx context.Context, db store.Queryer, id entity.ArticleID) (*entity.Article, error) {
+//				panic("mock out the GetArticle method")
+//			},
+//			GetArticleSourceFunc: func(ctx context.Context, db store.Queryer, source string) (*entity.ArticleSource, error) {
+//				panic("mock out the GetArticleSource method")
+//			},
+//			ListTagsFunc: func(ctx context.Context, db store.Queryer) ([]*entity.Tag, error) {
+//				panic("mock out the ListTags method")
+//			},
+//			ListUsersFunc: func(ctx context.Context, db store.Queryer) ([]*entity.User, error) {
+//				panic("mock out the ListUsers method")
+//			},
+//			RegisterUserFunc: func(ctx context.Context, db store.Execer, u *entity.User) error {
+//				panic("mock out the RegisterUser method")
+//			},
+//			ReplaceArticleFunc: func(ctx context.Context, db store.Execer, a *entity.Article) error {
+//				panic("mock out the ReplaceArticle method")
+//			},
+//			SaveArticleSourceFunc: func(ctx context.Context, db store.Execer, s *entity.ArticleSource) error {
+//				panic("mock out the SaveArticleSource method")
+//			},
+//		}
+//
+//		// use mockedArticleImporter in code that requires ArticleImporter
+//		// and then make assertions.
+//
+//	}
+type ArticleImporterMock struct {
+	// AddArticleFunc mocks the AddArticle method.
+	AddArticleFunc func(ctx context.Context, db store.Execer, a *entity.Article) error
+
+	// AddArticleTagFunc mocks the AddArticleTag method.
+	AddArticleTagFunc func(ctx context.Context, db store.Execer, articleID entity.ArticleID, tagID entity.TagID) error
+
+	// AddCommentFunc mocks the AddComment method.
+	AddCommentFunc func(ctx context.Context, db store.Execer, c *entity.Comment) error
+
+	// AddMediaFunc mocks the AddMedia method.
+	AddMediaFunc func(ctx context.Context, db store.Execer, m *entity.Media) error
+
+	// AddTagFunc mocks the AddTag method.
+	AddTagFunc func(ctx context.Context, db store.Execer, t *entity.Tag) error
+
+	// DeleteArticleTagsFunc mocks the DeleteArticleTags method.
+	DeleteArticleTagsFunc func(ctx context.Context, db store.Execer, articleID entity.ArticleID) error
+
+	// GetArticleFunc mocks the GetArticle method.
+	GetArticleFunc func(ctx context.Context, db store.Queryer, id entity.ArticleID) (*entity.Article, error)
+
+	// GetArticleSourceFunc mocks the GetArticleSource method.
+	GetArticleSourceFunc func(ctx context.Context, db store.Queryer, source string) (*entity.ArticleSource, error)
+
+	// ListTagsFunc mocks the ListTags method.
+	ListTagsFunc func(ctx context.Context, db store.Queryer) ([]*entity.Tag, error)
+
+	// ListUsersFunc mocks the ListUsers method.
+	ListUsersFunc func(ctx context.Context, db store.Queryer) ([]*entity.User, error)
+
+	// RegisterUserFunc mocks the RegisterUser method.
+	RegisterUserFunc func(ctx context.Context, db store.Execer, u *entity.User) error
+
+	// ReplaceArticleFunc mocks the ReplaceArticle method.
+	ReplaceArticleFunc func(ctx context.Context, db store.Execer, a *entity.Article) error
+
+	// SaveArticleSourceFunc mocks the SaveArticleSource method.
+	SaveArticleSourceFunc func(ctx context.Context, db store.Execer, s *entity.ArticleSource) error
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// AddArticle holds details about calls to the AddArticle method.
+		AddArticle []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Db is the db argument value.
+			Db store.Execer
+			// A is the a argument value.
+			A *entity.Article
+		}
+		// AddArticleTag holds details about calls to the AddArticleTag method.
+		AddArticleTag []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Db is the db argument value.
+			Db store.Execer
+			// ArticleID is the articleID argument value.
+			ArticleID entity.ArticleID
+			// TagID is the tagID argument value.
+			TagID entity.TagID
+		}
+		// AddComment holds details about calls to the AddComment method.
+		AddComment []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Db is the db argument value.
+			Db store.Execer
+			// C is the c argument value.
+			C *entity.Comment
+		}
+		// AddMedia holds details about calls to the AddMedia method.
+		AddMedia []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Db is the db argument value.
+			Db store.Execer
+			// M is the m argument value.
+			M *entity.Media
+		}
+		// AddTag holds details about calls to the AddTag method.
+		AddTag []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Db is the db argument value.
+			Db store.Execer
+			// T is the t argument value.
+			T *entity.Tag
+		}
+		// DeleteArticleTags holds details about calls to the DeleteArticleTags method.
+		DeleteArticleTags []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Db is the db argument value.
+			Db store.Execer
+			// ArticleID is the articleID argument value.
+			ArticleID entity.ArticleID
+		}
+		// GetArticle holds details about calls to the GetArticle method.
+		GetArticle []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Db is the db argument value.
+			Db store.Queryer
+			// ID is the id argument value.
+			ID entity.ArticleID
+		}
+		// GetArticleSource holds details about calls to the GetArticleSource method.
+		GetArticleSource []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Db is the db argument value.
+			Db store.Queryer
+			// Source is the source argument value.
+			Source string
+		}
+		// ListTags holds details about calls to the ListTags method.
+		ListTags []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Db is the db argument value.
+			Db store.Queryer
+		}
+		// ListUsers holds details about calls to the ListUsers method.
+		ListUsers []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Db is the db argument value.
+			Db store.Queryer
+		}
+		// RegisterUser holds details about calls to the RegisterUser method.
+		RegisterUser []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Db is the db argument value.
+			Db store.Execer
+			// U is the u argument value.
+			U *entity.User
+		}
+		// ReplaceArticle holds details about calls to the ReplaceArticle method.
+		ReplaceArticle []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Db is the db argument value.
+			Db store.Execer
+			// A is the a argument value.
+			A *entity.Article
+		}
+		// SaveArticleSource holds details about calls to the SaveArticleSource method.
+		SaveArticleSource []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Db is the db argument value.
+			Db store.Execer
+			// S is the s argument value.
+			S *entity.ArticleSource
+		}
+	}
+	lockAddArticle        sync.RWMutex
+	lockAddArticleTag     sync.RWMutex
+	lockAddComment        sync.RWMutex
+	lockAddMedia          sync.RWMutex
+	lockAddTag            sync.RWMutex
+	lockDeleteArticleTags sync.RWMutex
+	lockGetArticle        sync.RWMutex
+	lockGetArticleSource  sync.RWMutex
+	lockListTags          sync.RWMutex
+	lockListUsers         sync.RWMutex
+	lockRegisterUser      sync.RWMutex
+	lockReplaceArticle    sync.RWMutex
+	lockSaveArticleSource sync.RWMutex
+}
+
+// AddArticle calls AddArticleFunc.
+func (mock *ArticleImporterMock) AddArticle(ctx context.Context, db store.Execer, a *entity.Article) error {
+	if mock.AddArticleFunc == nil {
+		panic("ArticleImporterMock.AddArticleFunc: method is nil but ArticleImporter.AddArticle was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Db  store.Execer
+		A   *entity.Article
+	}{
+		Ctx: ctx,
+		Db:  db,
+		A:   a,
+	}
+	mock.lockAddArticle.Lock()
+	mock.calls.AddArticle = append(mock.calls.AddArticle, callInfo)
+	mock.lockAddArticle.Unlock()
+	return mock.AddArticleFunc(ctx, db, a)
+}
+
+// AddArticleCalls gets all the calls that were made to AddArticle.
+// Check the length with:
+//
+//	len(mockedArticleImporter.AddArticleCalls())
+func (mock *ArticleImporterMock) AddArticleCalls() []struct {
+	Ctx context.Context
+	Db  store.Execer
+	A   *entity.Article
+} {
+	var calls []struct {
+		Ctx context.Context
+		Db  store.Execer
+		A   *entity.Article
+	}
+	mock.lockAddArticle.RLock()
+	calls = mock.calls.AddArticle
+	mock.lockAddArticle.RUnlock()
+	return calls
+}
+
+// AddArticleTag calls AddArticleTagFunc.
+func (mock *ArticleImporterMock) AddArticleTag(ctx context.Context, db store.Execer, articleID entity.ArticleID, tagID entity.TagID) error {
+	if mock.AddArticleTagFunc == nil {
+		panic("ArticleImporterMock.AddArticleTagFunc: method is nil but ArticleImporter.AddArticleTag was just called")
+	}
+	callInfo := struct {
+		Ctx       context.Context
+		Db        store.Execer
+		ArticleID entity.ArticleID
+		TagID     entity.TagID
+	}{
+		Ctx:       ctx,
+		Db:        db,
+		ArticleID: articleID,
+		TagID:     tagID,
+	}
+	mock.lockAddArticleTag.Lock()
+	mock.calls.AddArticleTag = append(mock.calls.AddArticleTag, callInfo)
+	mock.lockAddArticleTag.Unlock()
+	return mock.AddArticleTagFunc(ctx, db, articleID, tagID)
+}
+
+// AddArticleTagCalls gets all the calls that were made to AddArticleTag.
+// Check the length with:
+//
+//	len(mockedArticleImporter.AddArticleTagCalls())
+func (mock *ArticleImporterMock) AddArticleTagCalls() []struct {
+	Ctx       context.Context
+	Db        store.Execer
+	ArticleID entity.ArticleID
+	TagID     entity.TagID
+} {
+	var calls []struct {
+		Ctx       context.Context
+		Db        store.Execer
+		ArticleID entity.ArticleID
+		TagID     entity.TagID
+	}
+	mock.lockAddArticleTag.RLock()
+	calls = mock.calls.AddArticleTag
+	mock.lockAddArticleTag.RUnlock()
+	return calls
+}
+
+// AddComment calls AddCommentFunc.
+func (mock *ArticleImporterMock) AddComment(ctx context.Context, db store.Execer, c *entity.Comment) error {
+	if mock.AddCommentFunc == nil {
+		panic("ArticleImporterMock.AddCommentFunc: method is nil but ArticleImporter.AddComment was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Db  store.Execer
+		C   *entity.Comment
+	}{
+		Ctx: ctx,
+		Db:  db,
+		C:   c,
+	}
+	mock.lockAddComment.Lock()
+	mock.calls.AddComment = append(mock.calls.AddComment, callInfo)
+	mock.lockAddComment.Unlock()
+	return mock.AddCommentFunc(ctx, db, c)
+}
+
+// AddCommentCalls gets all the calls that were made to AddComment.
+// Check the length with:
+//
+//	len(mockedArticleImporter.AddCommentCalls())
+func (mock *ArticleImporterMock) AddCommentCalls() []struct {
+	Ctx context.Context
+	Db  store.Execer
+	C   *entity.Comment
+} {
+	var calls []struct {
+		Ctx context.Context
+		Db  store.Execer
+		C   *entity.Comment
+	}
+	mock.lockAddComment.RLock()
+	calls = mock.calls.AddComment
+	mock.lockAddComment.RUnlock()
+	return calls
+}
+
+// AddMedia calls AddMediaFunc.
+func (mock *ArticleImporterMock) AddMedia(ctx context.Context, db store.Execer, m *entity.Media) error {
+	if mock.AddMediaFunc == nil {
+		panic("ArticleImporterMock.AddMediaFunc: method is nil but ArticleImporter.AddMedia was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Db  store.Execer
+		M   *entity.Media
+	}{
+		Ctx: ctx,
+		Db:  db,
+		M:   m,
+	}
+	mock.lockAddMedia.Lock()
+	mock.calls.AddMedia = append(mock.calls.AddMedia, callInfo)
+	mock.lockAddMedia.Unlock()
+	return mock.AddMediaFunc(ctx, db, m)
+}
+
+// AddMediaCalls gets all the calls that were made to AddMedia.
+// Check the length with:
+//
+//	len(mockedArticleImporter.AddMediaCalls())
+func (mock *ArticleImporterMock) AddMediaCalls() []struct {
+	Ctx context.Context
+	Db  store.Execer
+	M   *entity.Media
+} {
+	var calls []struct {
+		Ctx context.Context
+		Db  store.Execer
+		M   *entity.Media
+	}
+	mock.lockAddMedia.RLock()
+	calls = mock.calls.AddMedia
+	mock.lockAddMedia.RUnlock()
+	return calls
+}
+
+// AddTag calls AddTagFunc.
+func (mock *ArticleImporterMock) AddTag(ctx context.Context, db store.Execer, t *entity.Tag) error {
+	if mock.AddTagFunc == nil {
+		panic("ArticleImporterMock.AddTagFunc: method is nil but ArticleImporter.AddTag was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Db  store.Execer
+		T   *entity.Tag
+	}{
+		Ctx: ctx,
+		Db:  db,
+		T:   t,
+	}
+	mock.lockAddTag.Lock()
+	mock.calls.AddTag = append(mock.calls.AddTag, callInfo)
+	mock.lockAddTag.Unlock()
+	return mock.AddTagFunc(ctx, db, t)
+}
+
+// AddTagCalls gets all the calls that were made to AddTag.
+// Check the length with:
+//
+//	len(mockedArticleImporter.AddTagCalls())
+func (mock *ArticleImporterMock) AddTagCalls() []struct {
+	Ctx context.Context
+	Db  store.Execer
+	T   *entity.Tag
+} {
+	var calls []struct {
+		Ctx context.Context
+		Db  store.Execer
+		T   *entity.Tag
+	}
+	mock.lockAddTag.RLock()
+	calls = mock.calls.AddTag
+	mock.lockAddTag.RUnlock()
+	return calls
+}
+
+// DeleteArticleTags calls DeleteArticleTagsFunc.
+func (mock *ArticleImporterMock) DeleteArticleTags(ctx context.Context, db store.Execer, articleID entity.ArticleID) error {
+	if mock.DeleteArticleTagsFunc == nil {
+		panic("ArticleImporterMock.DeleteArticleTagsFunc: method is nil but ArticleImporter.DeleteArticleTags was just called")
+	}
+	callInfo := struct {
+		Ctx       context.Context
+		Db        store.Execer
+		ArticleID entity.ArticleID
+	}{
+		Ctx:       ctx,
+		Db:        db,
+		ArticleID: articleID,
+	}
+	mock.lockDeleteArticleTags.Lock()
+	mock.calls.DeleteArticleTags = append(mock.calls.DeleteArticleTags, callInfo)
+	mock.lockDeleteArticleTags.Unlock()
+	return mock.DeleteArticleTagsFunc(ctx, db, articleID)
+}
+
+// DeleteArticleTagsCalls gets all the calls that were made to DeleteArticleTags.
+// Check the length with:
+//
+//	len(mockedArticleImporter.DeleteArticleTagsCalls())
+func (mock *ArticleImporterMock) DeleteArticleTagsCalls() []struct {
+	Ctx       context.Context
+	Db        store.Execer
+	ArticleID entity.ArticleID
+} {
+	var calls []struct {
+		Ctx       context.Context
+		Db        store.Execer
+		ArticleID entity.ArticleID
+	}
+	mock.lockDeleteArticleTags.RLock()
+	calls = mock.calls.DeleteArticleTags
+	mock.lockDeleteArticleTags.RUnlock()
+	return calls
+}
+
+// GetArticle calls GetArticleFunc.
+func (mock *ArticleImporterMock) GetArticle(ctx context.Context, db store.Queryer, id entity.ArticleID) (*entity.Article, error) {
+	if mock.GetArticleFunc == nil {
+		panic("ArticleImporterMock.GetArticleFunc: method is nil but ArticleImporter.GetArticle was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Db  store.Queryer
+		ID  entity.ArticleID
+	}{
+		Ctx: ctx,
+		Db:  db,
+		ID:  id,
+	}
+	mock.lockGetArticle.Lock()
+	mock.calls.GetArticle = append(mock.calls.GetArticle, callInfo)
+	mock.lockGetArticle.Unlock()
+	return mock.GetArticleFunc(ctx, db, id)
+}
+
+// GetArticleCalls gets all the calls that were made to GetArticle.
+// Check the length with:
+//
+//	len(mockedArticleImporter.GetArticleCalls())
+func (mock *ArticleImporterMock) GetArticleCalls() []struct {
+	Ctx context.Context
+	Db  store.Queryer
+	ID  entity.ArticleID
+} {
+	var calls []struct {
+		Ctx context.Context
+		Db  store.Queryer
+		ID  entity.ArticleID
+	}
+	mock.lockGetArticle.RLock()
+	calls = mock.calls.GetArticle
+	mock.lockGetArticle.RUnlock()
+	return calls
+}
+
+// GetArticleSource calls GetArticleSourceFunc.
+func (mock *ArticleImporterMock) GetArticleSource(ctx context.Context, db store.Queryer, source string) (*entity.ArticleSource, error) {
+	if mock.GetArticleSourceFunc == nil {
+		panic("ArticleImporterMock.GetArticleSourceFunc: method is nil but ArticleImporter.GetArticleSource was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Db     store.Queryer
+		Source string
+	}{
+		Ctx:    ctx,
+		Db:     db,
+		Source: source,
+	}
+	mock.lockGetArticleSource.Lock()
+	mock.calls.GetArticleSource = append(mock.calls.GetArticleSource, callInfo)
+	mock.lockGetArticleSource.Unlock()
+	return mock.GetArticleSourceFunc(ctx, db, source)
+}
+
+// GetArticleSourceCalls gets all the calls that were made to GetArticleSource.
+// Check the length with:
+//
+//	len(mockedArticleImporter.GetArticleSourceCalls())
+func (mock *ArticleImporterMock) GetArticleSourceCalls() []struct {
+	Ctx    context.Context
+	Db     store.Queryer
+	Source string
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Db     store.Queryer
+		Source string
+	}
+	mock.lockGetArticleSource.RLock()
+	calls = mock.calls.GetArticleSource
+	mock.lockGetArticleSource.RUnlock()
+	return calls
+}
+
+// ListTags calls ListTagsFunc.
+func (mock *ArticleImporterMock) ListTags(ctx context.Context, db store.Queryer) ([]*entity.Tag, error) {
+	if mock.ListTagsFunc == nil {
+		panic("ArticleImporterMock.ListTagsFunc: method is nil but ArticleImporter.ListTags was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Db  store.Queryer
+	}{
+		Ctx: ctx,
+		Db:  db,
+	}
+	mock.lockListTags.Lock()
+	mock.calls.ListTags = append(mock.calls.ListTags, callInfo)
+	mock.lockListTags.Unlock()
+	return mock.ListTagsFunc(ctx, db)
+}
+
+// ListTagsCalls gets all the calls that were made to ListTags.
+// Check the length with:
+//
+//	len(mockedArticleImporter.ListTagsCalls())
+func (mock *ArticleImporterMock) ListTagsCalls() []struct {
+	Ctx context.Context
+	Db  store.Queryer
+} {
+	var calls []struct {
+		Ctx context.Context
+		Db  store.Queryer
+	}
+	mock.lockListTags.RLock()
+	calls = mock.calls.ListTags
+	mock.lockListTags.RUnlock()
+	return calls
+}
+
+// ListUsers calls ListUsersFunc.
+func (mock *ArticleImporterMock) ListUsers(ctx context.Context, db store.Queryer) ([]*entity.User, error) {
+	if mock.ListUsersFunc == nil {
+		panic("ArticleImporterMock.ListUsersFunc: method is nil but ArticleImporter.ListUsers was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Db  store.Queryer
+	}{
+		Ctx: ctx,
+		Db:  db,
+	}
+	mock.lockListUsers.Lock()
+	mock.calls.ListUsers = append(mock.calls.ListUsers, callInfo)
+	mock.lockListUsers.Unlock()
+	return mock.ListUsersFunc(ctx, db)
+}
+
+// ListUsersCalls gets all the calls that were made to ListUsers.
+// Check the length with:
+//
+//	len(mockedArticleImporter.ListUsersCalls())
+func (mock *ArticleImporterMock) ListUsersCalls() []struct {
+	Ctx context.Context
+	Db  store.Queryer
+} {
+	var calls []struct {
+		Ctx context.Context
+		Db  store.Queryer
+	}
+	mock.lockListUsers.RLock()
+	calls = mock.calls.ListUsers
+	mock.lockListUsers.RUnlock()
+	return calls
+}
+
+// RegisterUser calls RegisterUserFunc.
+func (mock *ArticleImporterMock) RegisterUser(ctx context.Context, db store.Execer, u *entity.User) error {
+	if mock.RegisterUserFunc == nil {
+		panic("ArticleImporterMock.RegisterUserFunc: method is nil but ArticleImporter.RegisterUser was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Db  store.Execer
+		U   *entity.User
+	}{
+		Ctx: ctx,
+		Db:  db,
+		U:   u,
+	}
+	mock.lockRegisterUser.Lock()
+	mock.calls.RegisterUser = append(mock.calls.RegisterUser, callInfo)
+	mock.lockRegisterUser.Unlock()
+	return mock.RegisterUserFunc(ctx, db, u)
+}
+
+// RegisterUserCalls gets all the calls that were made to RegisterUser.
+// Check the length with:
+//
+//	len(mockedArticleImporter.RegisterUserCalls())
+func (mock *ArticleImporterMock) RegisterUserCalls() []struct {
+	Ctx context.Context
+	Db  store.Execer
+	U   *entity.User
+} {
+	var calls []struct {
+		Ctx context.Context
+		Db  store.Execer
+		U   *entity.User
+	}
+	mock.lockRegisterUser.RLock()
+	calls = mock.calls.RegisterUser
+	mock.lockRegisterUser.RUnlock()
+	return calls
+}
+
+// ReplaceArticle calls ReplaceArticleFunc.
+func (mock *ArticleImporterMock) ReplaceArticle(ctx context.Context, db store.Execer, a *entity.Article) error {
+	if mock.ReplaceArticleFunc == nil {
+		panic("ArticleImporterMock.ReplaceArticleFunc: method is nil but ArticleImporter.ReplaceArticle was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Db  store.Execer
+		A   *entity.Article
+	}{
+		Ctx: ctx,
+		Db:  db,
+		A:   a,
+	}
+	mock.lockReplaceArticle.Lock()
+	mock.calls.ReplaceArticle = append(mock.calls.ReplaceArticle, callInfo)
+	mock.lockReplaceArticle.Unlock()
+	return mock.ReplaceArticleFunc(ctx, db, a)
+}
+
+// ReplaceArticleCalls gets all the calls that were made to ReplaceArticle.
+// Check the length with:
+//
+//	len(mockedArticleImporter.ReplaceArticleCalls())
+func (mock *ArticleImporterMock) ReplaceArticleCalls() []struct {
+	Ctx context.Context
+	Db  store.Execer
+	A   *entity.Article
+} {
+	var calls []struct {
+		Ctx context.Context
+		Db  store.Execer
+		A   *entity.Article
+	}
+	mock.lockReplaceArticle.RLock()
+	calls = mock.calls.ReplaceArticle
+	mock.lockReplaceArticle.RUnlock()
+	return calls
+}
+
+// SaveArticleSource calls SaveArticleSourceFunc.
+func (mock *ArticleImporterMock) SaveArticleSource(ctx context.Context, db store.Execer, s *entity.ArticleSource) error {
+	if mock.SaveArticleSourceFunc == nil {
+		panic("ArticleImporterMock.SaveArticleSourceFunc: method is nil but ArticleImporter.SaveArticleSource was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Db  store.Execer
+		S   *entity.ArticleSource
+	}{
+		Ctx: ctx,
+		Db:  db,
+		S:   s,
+	}
+	mock.lockSaveArticleSource.Lock()
+	mock.calls.SaveArticleSource = append(mock.calls.SaveArticleSource, callInfo)
+	mock.lockSaveArticleSource.Unlock()
+	return mock.SaveArticleSourceFunc(ctx, db, s)
+}
+
+// SaveArticleSourceCalls gets all the calls that were made to SaveArticleSource.
+// Check the length with:
+//
+//	len(mockedArticleImporter.SaveArticleSourceCalls())
+func (mock *ArticleImporterMock) SaveArticleSourceCalls() []struct {
+	Ctx context.Context
+	Db  store.Execer
+	S   *entity.ArticleSource
+} {
+	var calls []struct {
+		Ctx context.Context
+		Db  store.Execer
+		S   *entity.ArticleSource
+	}
+	mock.lockSaveArticleSource.RLock()
+	calls = mock.calls.SaveArticleSource
+	mock.lockSaveArticleSource.RUnlock()
+	return calls
+}
+
+// Ensure, that MediaPutterMock does implement MediaPutter.
+// If this is not the case, regenerate this file with moq.
+var _ MediaPutter = &MediaPutterMock{}
+
+// MediaPutterMock is a mock implementation of MediaPutter.
+//
+//	func TestSomethingThatUsesMediaPutter(t *testing.T) {
+//
+//		// make and configure a mocked MediaPutter
+//		mockedMediaPutter := &MediaPutterMock{
+//			PutFunc: func(ctx context.Context, key string, r io.Reader) error {
+//				panic("mock out the Put method")
+//			},
+//		}
+//
+//		// use mockedMediaPutter in code that requires MediaPutter
+//		// and then make assertions.
+//
+//	}
+type MediaPutterMock struct {
+	// PutFunc mocks the Put method.
+	PutFunc func(ctx context.Context, key string, r io.Reader) error
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// Put holds details about calls to the Put method.
+		Put []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Key is the key argument value.
+			Key string
+			// R is the r argument value.
+			R io.Reader
+		}
+	}
+	lockPut sync.RWMutex
+}
+
+// Put calls PutFunc.
+func (mock *MediaPutterMock) Put(ctx context.Context, key string, r io.Reader) error {
+	if mock.PutFunc == nil {
+		panic("MediaPutterMock.PutFunc: method is nil but MediaPutter.Put was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Key string
+		R   io.Reader
+	}{
+		Ctx: ctx,
+		Key: key,
+		R:   r,
+	}
+	mock.lockPut.Lock()
+	mock.calls.Put = append(mock.calls.Put, callInfo)
+	mock.lockPut.Unlock()
+	return mock.PutFunc(ctx, key, r)
+}
+
+// PutCalls gets all the calls that were made to Put.
+// Check the length with:
+//
+//	len(mockedMediaPutter.PutCalls())
+func (mock *MediaPutterMock) PutCalls() []struct {
+	Ctx context.Context
+	Key string
+	R   io.Reader
+} {
+	var calls []struct {
+		Ctx context.Context
+		Key string
+		R   io.Reader
+	}
+	mock.lockPut.RLock()
+	calls = mock.calls.Put
+	mock.lockPut.RUnlock()
+	return calls
+}
+
+// Ensure, that TransactorMock does implement Transactor.
+// If this is not the case, regenerate this file with moq.
+var _ Transactor = &TransactorMock{}
+
+// TransactorMock is a mock implementation of Transactor.
+//
+//	func TestSomethingThatUsesTransactor(t *testing.T) {
+//
+//		// make and configure a mocked Transactor
+//		mockedTransactor := &TransactorMock{
+//			InTxFunc: func(ctx context.Context, fn func(ctx context.Context, tx store.ExecQueryer) error, opts ...store.TxOption) error {
+//				panic("mock out the InTx method")
+//			},
+//		}
+//
+//		// use mockedTransactor in code that requires Transactor
+//		// and then make assertions.
+//
+//	}
+type TransactorMock struct {
+	// InTxFunc mocks the InTx method.
+	InTxFunc func(ctx context.Context, fn func(ctx context.Context, tx store.ExecQueryer) error, opts ...store.TxOption) error
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// InTx holds details about calls to the InTx method.
+		InTx []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Fn is the fn argument value.
+			Fn func(ctx context.Context, tx store.ExecQueryer) error
+			// Opts is the opts argument value.
+			Opts []store.TxOption
+		}
+	}
+	lockInTx sync.RWMutex
+}
+
+// InTx calls InTxFunc.
+func (mock *TransactorMock) InTx(ctx context.Context, fn func(ctx context.Context, tx store.ExecQueryer) error, opts ...store.TxOption) error {
+	if mock.InTxFunc == nil {
+		panic("TransactorMock.InTxFunc: method is nil but Transactor.InTx was just called")
+	}
+	callInfo := struct {
+		Ctx  context.Context
+		Fn   func(ctx context.Context, tx store.ExecQueryer) error
+		Opts []store.TxOption
+	}{
+		Ctx:  ctx,
+		Fn:   fn,
+		Opts: opts,
+	}
+	mock.lockInTx.Lock()
+	mock.calls.InTx = append(mock.calls.InTx, callInfo)
+	mock.lockInTx.Unlock()
+	return mock.InTxFunc(ctx, fn, opts...)
+}
+
+// InTxCalls gets all the calls that were made to InTx.
+// Check the length with:
+//
+//	len(mockedTransactor.InTxCalls())
+func (mock *TransactorMock) InTxCalls() []struct {
+	Ctx  context.Context
+	Fn   func(ctx context.Context, tx store.ExecQueryer) error
+	Opts []store.TxOption
+} {
+	var calls []struct {
+		Ctx  context.Context
+		Fn   func(ctx context.Context, tx store.ExecQueryer) error
+		Opts []store.TxOption
+	}
+	mock.lockInTx.RLock()
+	calls = mock.calls.InTx
+	mock.lockInTx.RUnlock()
+	return calls
+}
@@ -0,0 +1,40 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/iinuma0710/react-go-blog/backend/entity"
+	"github.com/iinuma0710/react-go-blog/backend/logger"
+	"github.com/iinuma0710/react-go-blog/backend/store"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type RegisterUser struct {
+	DB   store.Execer
+	Repo UserRegister
+}
+
+// パスワードはハッシュ化して保存する
+func (r *RegisterUser) RegisterUser(ctx context.Context, name, email, password string, role entity.Role) (*entity.User, error) {
+	ctx, span := tracer.Start(ctx, "service.RegisterUser")
+	defer span.End()
+
+	pw, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, spanError(span, fmt.Errorf("cannot hash password: %w", err))
+	}
+	u := &entity.User{
+		Name:     name,
+		Email:    email,
+		Password: string(pw),
+		Role:     role,
+	}
+
+	if err := r.Repo.RegisterUser(ctx, r.DB, u); err != nil {
+		return nil, spanError(span, fmt.Errorf("failed to register: %w", err))
+	}
+	logger.FromContext(ctx).Info("user registered", "user_id", u.ID, "role", u.Role)
+
+	return u, nil
+}
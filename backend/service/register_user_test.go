@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/iinuma0710/react-go-blog/backend/entity"
+	"github.com/iinuma0710/react-go-blog/backend/store"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestRegisterUser(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		repoErr error
+		wantErr error
+	}{
+		"ok":        {},
+		"duplicate": {repoErr: store.ErrAlreadyExists, wantErr: store.ErrAlreadyExists},
+	}
+
+	for n, tt := range tests {
+		tt := tt
+		t.Run(n, func(t *testing.T) {
+			t.Parallel()
+
+			var saved *entity.User
+			moq := &UserRegisterMock{
+				RegisterUserFunc: func(ctx context.Context, db store.Execer, u *entity.User) error {
+					saved = u
+					if tt.repoErr != nil {
+						return tt.repoErr
+					}
+					u.ID = 1
+					return nil
+				},
+			}
+			sut := &RegisterUser{Repo: moq}
+			got, err := sut.RegisterUser(context.Background(), "admin", "admin@example.com", "p@ssw0rd!", entity.RoleAdmin)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("want error %v, but got %v", tt.wantErr, err)
+			}
+			if err != nil {
+				return
+			}
+
+			// 平文のパスワードは保存しない
+			if saved.Password == "p@ssw0rd!" {
+				t.Error("password must be hashed")
+			}
+			if err := bcrypt.CompareHashAndPassword([]byte(saved.Password), []byte("p@ssw0rd!")); err != nil {
+				t.Errorf("hash does not match the password: %v", err)
+			}
+			if got.ID != 1 || got.Role != entity.RoleAdmin {
+				t.Errorf("unexpected user: %+v", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/iinuma0710/react-go-blog/backend/entity"
+	"github.com/iinuma0710/react-go-blog/backend/logger"
+	"github.com/iinuma0710/react-go-blog/backend/store"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type UpdateArticle struct {
+	DB    store.ExecQueryer
+	Repo  ArticleUpdater
+	Cache ArticleInvalidator
+	// 更新と存在確認をまとめるトランザクション (nil の場合は DB にそのまま書き込む)
+	Tx Transactor
+}
+
+// version にはクライアントが最後に取得した記事のバージョンを渡す
+// データベース上のバージョンと一致しない場合は store.ErrVersionConflict を返す
+func (ua *UpdateArticle) UpdateArticle(
+	ctx context.Context, id entity.ArticleID, version int64, title string, status entity.ArticleStatus,
+) (*entity.Article, error) {
+	ctx, span := tracer.Start(ctx, "service.UpdateArticle", trace.WithAttributes(attribute.Int64("article.id", int64(id))))
+	defer span.End()
+
+	a := &entity.Article{
+		ID:      id,
+		Title:   title,
+		Status:  status,
+		Version: version,
+	}
+
+	update := func(ctx context.Context, db store.ExecQueryer) error {
+		err := ua.Repo.UpdateArticle(ctx, db, a)
+		if errors.Is(err, store.ErrVersionConflict) {
+			// 更新件数が 0 件の場合、記事が存在しない可能性もあるので確認する
+			if _, gerr := ua.Repo.GetArticle(ctx, db, id); errors.Is(gerr, store.ErrNotFound) {
+				err = gerr
+			}
+		}
+		return err
+	}
+	var err error
+	if ua.Tx != nil {
+		// 更新と確認の間に記事が作り直されても、同じ時点の状態で判定する
+		err = ua.Tx.InTx(ctx, update)
+	} else {
+		err = update(ctx, ua.DB)
+	}
+	if ua.Cache != nil && (err == nil || errors.Is(err, store.ErrVersionConflict)) {
+		// 競合した場合もキャッシュの内容が古くなっている可能性があるので破棄する
+		ua.Cache.InvalidateArticles(id)
+	}
+	if err != nil {
+		return nil, spanError(span, fmt.Errorf("failed to update: %w", err))
+	}
+	logger.FromContext(ctx).Info("article updated", "article_id", a.ID, "version", a.Version)
+
+	return a, nil
+}
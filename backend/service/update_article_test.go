@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/iinuma0710/react-go-blog/backend/entity"
+	"github.com/iinuma0710/react-go-blog/backend/store"
+)
+
+func TestUpdateArticle(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		updateErr error
+		getErr    error
+		inTx      bool
+		wantErr   error
+	}{
+		"ok":       {},
+		"conflict": {updateErr: store.ErrVersionConflict, wantErr: store.ErrVersionConflict},
+		"notFound": {updateErr: store.ErrVersionConflict, getErr: store.ErrNotFound, wantErr: store.ErrNotFound},
+		// 更新と存在確認を同じトランザクションで実行する
+		"notFoundInTx": {updateErr: store.ErrVersionConflict, getErr: store.ErrNotFound, inTx: true, wantErr: store.ErrNotFound},
+	}
+
+	for n, tt := range tests {
+		tt := tt
+		t.Run(n, func(t *testing.T) {
+			t.Parallel()
+
+			moq := &ArticleUpdaterMock{
+				UpdateArticleFunc: func(ctx context.Context, db store.Execer, a *entity.Article) error {
+					if tt.updateErr != nil {
+						return tt.updateErr
+					}
+					a.Version++
+					return nil
+				},
+				GetArticleFunc: func(ctx context.Context, db store.Queryer, id entity.ArticleID) (*entity.Article, error) {
+					if tt.getErr != nil {
+						return nil, tt.getErr
+					}
+					return &entity.Article{ID: id, Version: 5}, nil
+				},
+			}
+
+			sut := &UpdateArticle{Repo: moq}
+			tx := &store.TracedDB{System: "tx"}
+			if tt.inTx {
+				sut.Tx = &TransactorMock{InTxFunc: func(ctx context.Context, fn func(ctx context.Context, tx store.ExecQueryer) error, opts ...store.TxOption) error {
+					return fn(ctx, tx)
+				}}
+			}
+			got, err := sut.UpdateArticle(context.Background(), 1, 3, "title", entity.ArticlePublished)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("want error %v, but got %v", tt.wantErr, err)
+			}
+			if tt.wantErr == nil && got.Version != 4 {
+				t.Errorf("want version 4, but got %d", got.Version)
+			}
+			if !tt.inTx {
+				return
+			}
+			for _, c := range moq.UpdateArticleCalls() {
+				if c.Db != tx {
+					t.Errorf("want article to be updated in the transaction, but got %v", c.Db)
+				}
+			}
+			for _, c := range moq.GetArticleCalls() {
+				if c.Db != tx {
+					t.Errorf("want article to be checked in the transaction, but got %v", c.Db)
+				}
+			}
+		})
+	}
+}
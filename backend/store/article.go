@@ -2,37 +2,140 @@ package store
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 
 	"github.com/iinuma0710/react-go-blog/backend/entity"
+	"github.com/iinuma0710/react-go-blog/backend/logger"
 )
 
+// 未設定のスラッグと作成者は NULL で保存し、読み出し時にゼロ値へ変換する
+const articleColumns = `id, title, content, COALESCE(slug, '') AS slug, status, version,
+	COALESCE(author_id, 0) AS author_id, created_at, updated_at`
+
 func (r *Repository) ListArticles(ctx context.Context, db Queryer) (entity.Articles, error) {
 	articles := entity.Articles{}
-	sql := `SELECT id, title, status, created_at FROM article;`
+	sql := `SELECT ` + articleColumns + ` FROM article;`
 
-	if err := db.SelectContext(ctx, &articles, sql); err != nil {
+	if err := db.SelectContext(ctx, &articles, rebind(db, sql)); err != nil {
 		return nil, err
 	}
 
 	return articles, nil
 }
 
+// 同じスラッグの記事が既に存在する場合は ErrAlreadyExists を返す
+// 作成日時が設定されている場合は、取り込み元の日時を残すためにそのまま使う
 func (r *Repository) AddArticle(ctx context.Context, db Execer, a *entity.Article) error {
-	a.CreatedAt = r.Clocker.Now()
+	if a.CreatedAt.IsZero() {
+		a.CreatedAt = r.Clocker.Now()
+	}
+	if a.UpdatedAt.IsZero() {
+		a.UpdatedAt = a.CreatedAt
+	}
 	sql := `INSERT INTO article
-		(title, status, created_at)
-		VALUES (?, ?, ?)`
+		(title, content, slug, status, author_id, created_at, updated_at)
+		VALUES (?, ?, NULLIF(?, ''), ?, NULLIF(?, 0), ?, ?)`
+
+	id, err := insertID(ctx, db, sql, a.Title, a.Content, a.Slug, a.Status, a.AuthorID, a.CreatedAt, a.UpdatedAt)
+	if err != nil {
+		if isDuplicate(err) {
+			return ErrAlreadyExists
+		}
+		return err
+	}
+
+	a.ID = entity.ArticleID(id)
+	a.Version = 1
+	return nil
+}
+
+func (r *Repository) GetArticle(ctx context.Context, db Queryer, id entity.ArticleID) (*entity.Article, error) {
+	a := &entity.Article{}
+	// database/sql パッケージと名前が衝突するので query とする
+	query := `SELECT ` + articleColumns + ` FROM article WHERE id = ?;`
+
+	if err := db.GetContext(ctx, a, rebind(db, query), id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
 
-	result, err := db.ExecContext(ctx, sql, a.Title, a.Status, a.CreatedAt)
+	return a, nil
+}
+
+// a.Version と一致するバージョンのレコードのみを更新し、バージョンを 1 つ進める
+// バージョンの比較は UPDATE 文の中で行うので、同時に更新されても上書きは発生しない
+func (r *Repository) UpdateArticle(ctx context.Context, db Execer, a *entity.Article) error {
+	updatedAt := r.Clocker.Now()
+	sql := `UPDATE article
+		SET title = ?, status = ?, updated_at = ?, version = version + 1
+		WHERE id = ? AND version = ?`
+
+	result, err := db.ExecContext(ctx, rebind(db, sql), a.Title, a.Status, updatedAt, a.ID, a.Version)
 	if err != nil {
 		return err
 	}
 
-	id, err := result.LastInsertId()
+	n, err := result.RowsAffected()
 	if err != nil {
 		return err
 	}
+	if n == 0 {
+		logger.FromContext(ctx).Debug("article version conflict", "article_id", a.ID, "version", a.Version)
+		return ErrVersionConflict
+	}
 
-	a.ID = entity.ArticleID(id)
+	a.Version++
+	a.UpdatedAt = updatedAt
 	return nil
 }
+
+// 本文やスラッグも含めて a の内容で置き換える (取り込み直したときに使う)
+// バージョンの扱いは UpdateArticle と同じ
+func (r *Repository) ReplaceArticle(ctx context.Context, db Execer, a *entity.Article) error {
+	updatedAt := r.Clocker.Now()
+	sql := `UPDATE article
+		SET title = ?, content = ?, slug = NULLIF(?, ''), status = ?, author_id = NULLIF(?, 0),
+			updated_at = ?, version = version + 1
+		WHERE id = ? AND version = ?`
+
+	result, err := db.ExecContext(ctx, rebind(db, sql), a.Title, a.Content, a.Slug, a.Status, a.AuthorID, updatedAt, a.ID, a.Version)
+	if err != nil {
+		if isDuplicate(err) {
+			return ErrAlreadyExists
+		}
+		return err
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrVersionConflict
+	}
+
+	a.Version++
+	a.UpdatedAt = updatedAt
+	return nil
+}
+
+// ステータスごとの記事数を集計する
+func (r *Repository) CountArticlesByStatus(ctx context.Context, db Queryer) (map[entity.ArticleStatus]int64, error) {
+	var rows []struct {
+		Status entity.ArticleStatus `db:"status"`
+		Count  int64                `db:"count"`
+	}
+	query := `SELECT status, COUNT(*) AS count FROM article GROUP BY status;`
+	if err := db.SelectContext(ctx, &rows, rebind(db, query)); err != nil {
+		return nil, err
+	}
+
+	counts := make(map[entity.ArticleStatus]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, nil
+}
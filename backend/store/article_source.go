@@ -0,0 +1,46 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/iinuma0710/react-go-blog/backend/entity"
+)
+
+func (r *Repository) GetArticleSource(ctx context.Context, db Queryer, source string) (*entity.ArticleSource, error) {
+	s := &entity.ArticleSource{}
+	query := `SELECT source, article_id, checksum, created_at, updated_at FROM article_source WHERE source = ?;`
+
+	if err := db.GetContext(ctx, s, rebind(db, query), source); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return s, nil
+}
+
+// 取り込み元ごとに 1 件だけ保存し、既に存在する場合は記事とハッシュ値を更新する
+func (r *Repository) SaveArticleSource(ctx context.Context, db Execer, s *entity.ArticleSource) error {
+	now := r.Clocker.Now()
+	query := `INSERT INTO article_source
+		(source, article_id, checksum, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE article_id = VALUES(article_id), checksum = VALUES(checksum), updated_at = VALUES(updated_at)`
+	if driverName(db) != DriverMySQL {
+		query = `INSERT INTO article_source
+		(source, article_id, checksum, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (source) DO UPDATE SET article_id = excluded.article_id, checksum = excluded.checksum, updated_at = excluded.updated_at`
+	}
+
+	if _, err := db.ExecContext(ctx, rebind(db, query), s.Source, s.ArticleID, s.Checksum, now, now); err != nil {
+		return err
+	}
+	if s.CreatedAt.IsZero() {
+		s.CreatedAt = now
+	}
+	s.UpdatedAt = now
+	return nil
+}
@@ -0,0 +1,65 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/iinuma0710/react-go-blog/backend/clock"
+	"github.com/iinuma0710/react-go-blog/backend/entity"
+	"github.com/jmoiron/sqlx"
+)
+
+func TestRepository_GetArticleSource(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	mock.ExpectQuery(`SELECT source, article_id, checksum, created_at, updated_at FROM article_source WHERE source = \?`).
+		WithArgs("memo/missing.md").
+		WillReturnRows(sqlmock.NewRows([]string{"source", "article_id", "checksum", "created_at", "updated_at"}))
+
+	xdb := sqlx.NewDb(db, "mysql")
+	r := &Repository{}
+	if _, err := r.GetArticleSource(ctx, xdb, "memo/missing.md"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("want ErrNotFound, but got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRepository_SaveArticleSource(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	c := clock.FixedClocker{}
+	s := &entity.ArticleSource{Source: "memo/go.md", ArticleID: 3, Checksum: "abc"}
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	// 同じ取り込み元は上書きする
+	mock.ExpectExec(`INSERT INTO article_source .* ON DUPLICATE KEY UPDATE`).
+		WithArgs(s.Source, s.ArticleID, s.Checksum, c.Now(), c.Now()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	xdb := sqlx.NewDb(db, "mysql")
+	r := &Repository{Clocker: c}
+	if err := r.SaveArticleSource(ctx, xdb, s); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !s.UpdatedAt.Equal(c.Now()) {
+		t.Errorf("want updated_at %v, but got %v", c.Now(), s.UpdatedAt)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
@@ -2,6 +2,7 @@ package store
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
@@ -25,29 +26,35 @@ func prepareArticles(ctx context.Context, t *testing.T, con Execer) entity.Artic
 		{
 			Title:     "wants article 1",
 			Status:    "published",
+			Version:   1,
 			CreatedAt: c.Now(),
+			UpdatedAt: c.Now(),
 		},
 		{
 			Title:     "wants article 1",
 			Status:    "draft",
+			Version:   1,
 			CreatedAt: c.Now(),
+			UpdatedAt: c.Now(),
 		},
 		{
 			Title:     "wants article 3",
 			Status:    "withdrawn",
+			Version:   1,
 			CreatedAt: c.Now(),
+			UpdatedAt: c.Now(),
 		},
 	}
 
 	result, err := con.ExecContext(ctx, `
-		INSERT INTO article (title, status, created_at)
+		INSERT INTO article (title, content, status, created_at, updated_at)
 		VALUES
-			(?, ?, ?),
-			(?, ?, ?),
-			(?, ?, ?);`,
-		wants[0].Title, wants[0].Status, wants[0].CreatedAt,
-		wants[1].Title, wants[1].Status, wants[1].CreatedAt,
-		wants[2].Title, wants[2].Status, wants[2].CreatedAt,
+			(?, '', ?, ?, ?),
+			(?, '', ?, ?, ?),
+			(?, '', ?, ?, ?);`,
+		wants[0].Title, wants[0].Status, wants[0].CreatedAt, wants[0].UpdatedAt,
+		wants[1].Title, wants[1].Status, wants[1].CreatedAt, wants[1].UpdatedAt,
+		wants[2].Title, wants[2].Status, wants[2].CreatedAt, wants[2].UpdatedAt,
 	)
 	if err != nil {
 		t.Fatal(err)
@@ -107,8 +114,8 @@ func TestRepository_AddArticle(t *testing.T) {
 
 	mock.ExpectExec(
 		// エスケープが必要
-		`INSERT INTO article \(title, status, created_at\) VALUES \(\?, \?, \?\)`,
-	).WithArgs(okTask.Title, okTask.Status, c.Now()).
+		`INSERT INTO article \(title, content, slug, status, author_id, created_at, updated_at\) VALUES \(\?, \?, NULLIF\(\?, ''\), \?, NULLIF\(\?, 0\), \?, \?\)`,
+	).WithArgs(okTask.Title, okTask.Content, okTask.Slug, okTask.Status, okTask.AuthorID, c.Now(), c.Now()).
 		WillReturnResult((sqlmock.NewResult(wantID, 1)))
 
 	xdb := sqlx.NewDb(db, "mysql")
@@ -117,3 +124,95 @@ func TestRepository_AddArticle(t *testing.T) {
 		t.Errorf("want no error, but got %v", err)
 	}
 }
+
+func TestRepository_UpdateArticle(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		affected    int64
+		wantErr     error
+		wantVersion int64
+	}{
+		"ok":       {affected: 1, wantErr: nil, wantVersion: 4},
+		"conflict": {affected: 0, wantErr: ErrVersionConflict, wantVersion: 3},
+	}
+
+	for n, tt := range tests {
+		tt := tt
+		t.Run(n, func(t *testing.T) {
+			t.Parallel()
+			ctx := context.Background()
+
+			a := &entity.Article{
+				ID:      10,
+				Title:   "updated article",
+				Status:  entity.ArticlePublished,
+				Version: 3,
+			}
+
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatal(err)
+			}
+			t.Cleanup(func() { db.Close() })
+
+			// バージョンの比較が UPDATE 文の WHERE 句に含まれていることを確認
+			mock.ExpectExec(
+				`UPDATE article SET title = \?, status = \?, updated_at = \?, version = version \+ 1 WHERE id = \? AND version = \?`,
+			).WithArgs(a.Title, a.Status, clock.FixedClocker{}.Now(), a.ID, int64(3)).
+				WillReturnResult(sqlmock.NewResult(0, tt.affected))
+
+			xdb := sqlx.NewDb(db, "mysql")
+			r := &Repository{Clocker: clock.FixedClocker{}}
+			if err := r.UpdateArticle(ctx, xdb, a); !errors.Is(err, tt.wantErr) {
+				t.Errorf("want error %v, but got %v", tt.wantErr, err)
+			}
+			if a.Version != tt.wantVersion {
+				t.Errorf("want version %d, but got %d", tt.wantVersion, a.Version)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestRepository_ReplaceArticle(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	c := clock.FixedClocker{}
+	a := &entity.Article{
+		ID:       10,
+		Title:    "imported article",
+		Content:  "本文",
+		Slug:     "imported",
+		Status:   entity.ArticlePublished,
+		AuthorID: 2,
+		Version:  3,
+	}
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	// 本文とスラッグも更新する
+	mock.ExpectExec(
+		`UPDATE article SET title = \?, content = \?, slug = NULLIF\(\?, ''\), status = \?, author_id = NULLIF\(\?, 0\), updated_at = \?, version = version \+ 1 WHERE id = \? AND version = \?`,
+	).WithArgs(a.Title, a.Content, a.Slug, a.Status, a.AuthorID, c.Now(), a.ID, int64(3)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	xdb := sqlx.NewDb(db, "mysql")
+	r := &Repository{Clocker: c}
+	if err := r.ReplaceArticle(ctx, xdb, a); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if a.Version != 4 {
+		t.Errorf("want version 4, but got %d", a.Version)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
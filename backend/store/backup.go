@@ -0,0 +1,118 @@
+package store
+
+import (
+	"context"
+
+	"github.com/iinuma0710/react-go-blog/backend/entity"
+)
+
+// バックアップの対象のテーブルにレコードが 1 件でもあれば true を返す
+func (r *Repository) HasBackupData(ctx context.Context, db Queryer) (bool, error) {
+	query := `SELECT EXISTS (SELECT 1 FROM ` + userTable + `) OR EXISTS (SELECT 1 FROM tag)
+		OR EXISTS (SELECT 1 FROM article) OR EXISTS (SELECT 1 FROM article_tag)
+		OR EXISTS (SELECT 1 FROM comment) OR EXISTS (SELECT 1 FROM media)
+		OR EXISTS (SELECT 1 FROM article_source);`
+
+	var found bool
+	if err := db.GetContext(ctx, &found, rebind(db, query)); err != nil {
+		return false, err
+	}
+	return found, nil
+}
+
+func (r *Repository) ListArticleTagIDs(ctx context.Context, db Queryer) ([]entity.ArticleTag, error) {
+	ats := []entity.ArticleTag{}
+	query := `SELECT article_id, tag_id FROM article_tag ORDER BY article_id, tag_id;`
+
+	if err := db.SelectContext(ctx, &ats, rebind(db, query)); err != nil {
+		return nil, err
+	}
+	return ats, nil
+}
+
+func (r *Repository) ListComments(ctx context.Context, db Queryer) ([]*entity.Comment, error) {
+	comments := []*entity.Comment{}
+	query := `SELECT id, article_id, COALESCE(user_id, 0) AS user_id, author_name, body, created_at
+		FROM comment ORDER BY id;`
+
+	if err := db.SelectContext(ctx, &comments, rebind(db, query)); err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
+
+func (r *Repository) ListMedia(ctx context.Context, db Queryer) ([]*entity.Media, error) {
+	media := []*entity.Media{}
+	query := `SELECT storage_key, content_type, size, sha256, created_at FROM media ORDER BY storage_key;`
+
+	if err := db.SelectContext(ctx, &media, rebind(db, query)); err != nil {
+		return nil, err
+	}
+	return media, nil
+}
+
+func (r *Repository) ListArticleSources(ctx context.Context, db Queryer) ([]*entity.ArticleSource, error) {
+	sources := []*entity.ArticleSource{}
+	query := `SELECT source, article_id, checksum, created_at, updated_at FROM article_source ORDER BY source;`
+
+	if err := db.SelectContext(ctx, &sources, rebind(db, query)); err != nil {
+		return nil, err
+	}
+	return sources, nil
+}
+
+// 以下はバックアップから復元するためのメソッドで、識別子と日時をバックアップの値のまま保存する
+
+func (r *Repository) RestoreUser(ctx context.Context, db Execer, u *entity.User) error {
+	query := `INSERT INTO ` + userTable + `
+		(id, name, email, password, role, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`
+	if _, err := db.ExecContext(ctx, rebind(db, query), u.ID, u.Name, u.Email, u.Password, u.Role, u.CreatedAt, u.UpdatedAt); err != nil {
+		return err
+	}
+	return syncSequence(ctx, db, "user")
+}
+
+func (r *Repository) RestoreTag(ctx context.Context, db Execer, t *entity.Tag) error {
+	query := `INSERT INTO tag (id, name, created_at) VALUES (?, ?, ?)`
+	if _, err := db.ExecContext(ctx, rebind(db, query), t.ID, t.Name, t.CreatedAt); err != nil {
+		return err
+	}
+	return syncSequence(ctx, db, "tag")
+}
+
+func (r *Repository) RestoreArticle(ctx context.Context, db Execer, a *entity.Article) error {
+	query := `INSERT INTO article
+		(id, title, content, slug, status, version, author_id, created_at, updated_at)
+		VALUES (?, ?, ?, NULLIF(?, ''), ?, ?, NULLIF(?, 0), ?, ?)`
+	if _, err := db.ExecContext(ctx, rebind(db, query), a.ID, a.Title, a.Content, a.Slug, a.Status, a.Version, a.AuthorID, a.CreatedAt, a.UpdatedAt); err != nil {
+		return err
+	}
+	return syncSequence(ctx, db, "article")
+}
+
+func (r *Repository) RestoreComment(ctx context.Context, db Execer, c *entity.Comment) error {
+	query := `INSERT INTO comment
+		(id, article_id, user_id, author_name, body, created_at)
+		VALUES (?, ?, NULLIF(?, 0), ?, ?, ?)`
+	if _, err := db.ExecContext(ctx, rebind(db, query), c.ID, c.ArticleID, c.UserID, c.AuthorName, c.Body, c.CreatedAt); err != nil {
+		return err
+	}
+	return syncSequence(ctx, db, "comment")
+}
+
+func (r *Repository) RestoreMedia(ctx context.Context, db Execer, m *entity.Media) error {
+	query := `INSERT INTO media
+		(storage_key, content_type, size, sha256, created_at)
+		VALUES (?, ?, ?, ?, ?)`
+	_, err := db.ExecContext(ctx, rebind(db, query), m.Key, m.ContentType, m.Size, m.SHA256, m.CreatedAt)
+	return err
+}
+
+func (r *Repository) RestoreArticleSource(ctx context.Context, db Execer, s *entity.ArticleSource) error {
+	query := `INSERT INTO article_source
+		(source, article_id, checksum, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?)`
+	_, err := db.ExecContext(ctx, rebind(db, query), s.Source, s.ArticleID, s.Checksum, s.CreatedAt, s.UpdatedAt)
+	return err
+}
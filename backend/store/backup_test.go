@@ -0,0 +1,100 @@
+package store
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/go-cmp/cmp"
+	"github.com/iinuma0710/react-go-blog/backend/clock"
+	"github.com/iinuma0710/react-go-blog/backend/entity"
+	"github.com/jmoiron/sqlx"
+)
+
+func TestRepository_HasBackupData(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	mock.ExpectQuery(`SELECT EXISTS \(SELECT 1 FROM .user.\)`).
+		WillReturnRows(sqlmock.NewRows([]string{"found"}).AddRow(true))
+
+	r := &Repository{}
+	found, err := r.HasBackupData(ctx, sqlx.NewDb(db, "mysql"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !found {
+		t.Error("want true, but got false")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRepository_ListComments(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	c := clock.FixedClocker{}
+	want := []*entity.Comment{
+		{ID: 1, ArticleID: 2, UserID: 3, Body: "ユーザ", CreatedAt: c.Now()},
+		{ID: 2, ArticleID: 2, AuthorName: "読者", Body: "ゲスト", CreatedAt: c.Now()},
+	}
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	rows := sqlmock.NewRows([]string{"id", "article_id", "user_id", "author_name", "body", "created_at"})
+	for _, cm := range want {
+		rows.AddRow(cm.ID, cm.ArticleID, cm.UserID, cm.AuthorName, cm.Body, cm.CreatedAt)
+	}
+	mock.ExpectQuery(`SELECT id, article_id, COALESCE\(user_id, 0\) AS user_id`).WillReturnRows(rows)
+
+	r := &Repository{}
+	got, err := r.ListComments(ctx, sqlx.NewDb(db, "mysql"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("differs: (-want +got)\n%s", d)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRepository_RestoreArticle(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	c := clock.FixedClocker{}
+	// 識別子、バージョン、日時は Clocker を使わずにそのまま保存する
+	a := &entity.Article{
+		ID: 5, Title: "記事", Content: "本文", Status: entity.ArticlePublished, Version: 3,
+		CreatedAt: c.Now(), UpdatedAt: c.Now().AddDate(0, 0, 1),
+	}
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	mock.ExpectExec(`INSERT INTO article`).
+		WithArgs(a.ID, a.Title, a.Content, a.Slug, a.Status, a.Version, a.AuthorID, a.CreatedAt, a.UpdatedAt).
+		WillReturnResult(sqlmock.NewResult(5, 1))
+
+	r := &Repository{}
+	if err := r.RestoreArticle(ctx, sqlx.NewDb(db, "mysql"), a); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,25 @@
+package store
+
+import (
+	"context"
+
+	"github.com/iinuma0710/react-go-blog/backend/entity"
+)
+
+// 作成日時が設定されている場合は、取り込み元の日時を残すためにそのまま使う
+func (r *Repository) AddComment(ctx context.Context, db Execer, c *entity.Comment) error {
+	if c.CreatedAt.IsZero() {
+		c.CreatedAt = r.Clocker.Now()
+	}
+	sql := `INSERT INTO comment
+		(article_id, user_id, author_name, body, created_at)
+		VALUES (?, NULLIF(?, 0), ?, ?, ?)`
+
+	id, err := insertID(ctx, db, sql, c.ArticleID, c.UserID, c.AuthorName, c.Body, c.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	c.ID = entity.CommentID(id)
+	return nil
+}
@@ -0,0 +1,40 @@
+package store
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/iinuma0710/react-go-blog/backend/clock"
+	"github.com/iinuma0710/react-go-blog/backend/entity"
+	"github.com/jmoiron/sqlx"
+)
+
+func TestRepository_AddComment(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	c := clock.FixedClocker{}
+	cm := &entity.Comment{ArticleID: 3, UserID: 2, Body: "とても参考になりました。"}
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	mock.ExpectExec(`INSERT INTO comment`).
+		WithArgs(cm.ArticleID, cm.UserID, cm.AuthorName, cm.Body, c.Now()).
+		WillReturnResult(sqlmock.NewResult(7, 1))
+
+	xdb := sqlx.NewDb(db, "mysql")
+	r := &Repository{Clocker: c}
+	if err := r.AddComment(ctx, xdb, cm); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cm.ID != 7 {
+		t.Errorf("want id 7, but got %d", cm.ID)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,428 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/iinuma0710/react-go-blog/backend/clock"
+	"github.com/iinuma0710/react-go-blog/backend/entity"
+	"github.com/iinuma0710/react-go-blog/backend/testutil"
+)
+
+// すべてのデータベースで同じ結果になることを、実際のデータベースに対して確認する
+// SQLite はテストケースごとに新しいファイルを使い、MySQL は接続できる場合だけトランザクションの中で実行する
+// PostgreSQL は接続できる場合だけ、テストケースごとに新しいスキーマで実行する
+var conformanceCases = map[string]func(t *testing.T, db ExecQueryer){
+	"user":           conformUser,
+	"article":        conformArticle,
+	"tag":            conformTag,
+	"comment":        conformComment,
+	"media":          conformMedia,
+	"media_in_tx":    conformMediaInTx,
+	"article_source": conformArticleSource,
+	"idempotency":    conformIdempotency,
+	"restore":        conformRestore,
+}
+
+func TestRepository_Conformance(t *testing.T) {
+	t.Parallel()
+
+	backends := map[string]func(t *testing.T) ExecQueryer{
+		"sqlite": func(t *testing.T) ExecQueryer { return testutil.OpenSQLiteForTest(t) },
+		"mysql": func(t *testing.T) ExecQueryer {
+			tx, err := testutil.OpenMySQLOrSkip(t).BeginTxx(context.Background(), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			t.Cleanup(func() { _ = tx.Rollback() })
+			return tx
+		},
+		"postgres": func(t *testing.T) ExecQueryer { return testutil.OpenPostgresOrSkip(t) },
+	}
+
+	for bn, open := range backends {
+		t.Run(bn, func(t *testing.T) {
+			t.Parallel()
+			for cn, run := range conformanceCases {
+				t.Run(cn, func(t *testing.T) {
+					run(t, open(t))
+				})
+			}
+		})
+	}
+}
+
+var conformClock = clock.FixedClocker{}
+
+func conformUser(t *testing.T, db ExecQueryer) {
+	ctx := context.Background()
+	r := &Repository{Clocker: conformClock}
+
+	u := &entity.User{Name: "cf-alice", Email: "alice@example.com", Password: "hash", Role: entity.RoleAuthor}
+	if err := r.RegisterUser(ctx, db, u); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if u.ID == 0 {
+		t.Error("want id to be set")
+	}
+	// 名前の一意制約は大文字と小文字を区別しない
+	if err := r.RegisterUser(ctx, db, &entity.User{Name: "CF-Alice", Role: entity.RoleAuthor}); !errors.Is(err, ErrAlreadyExists) {
+		t.Errorf("want ErrAlreadyExists, but got %v", err)
+	}
+
+	users, err := r.ListUsers(ctx, db)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := find(users, func(x *entity.User) bool { return x.ID == u.ID }); got == nil {
+		t.Errorf("want user %d to be listed", u.ID)
+	} else if d := cmp.Diff(u, got); d != "" {
+		t.Errorf("differs: (-want +got)\n%s", d)
+	}
+}
+
+func conformArticle(t *testing.T, db ExecQueryer) {
+	ctx := context.Background()
+	r := &Repository{Clocker: conformClock}
+
+	a := &entity.Article{Title: "記事", Content: "本文", Slug: "cf-post", Status: entity.ArticleDraft, AuthorID: 1}
+	if err := r.AddArticle(ctx, db, a); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// スラッグのない記事はいくつでも追加できる
+	for range 2 {
+		if err := r.AddArticle(ctx, db, &entity.Article{Title: "スラッグなし", Status: entity.ArticlePublished}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if err := r.AddArticle(ctx, db, &entity.Article{Title: "重複", Slug: "cf-post", Status: entity.ArticleDraft}); !errors.Is(err, ErrAlreadyExists) {
+		t.Errorf("want ErrAlreadyExists, but got %v", err)
+	}
+
+	got, err := r.GetArticle(ctx, db, a.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := &entity.Article{
+		ID: a.ID, Title: "記事", Content: "本文", Slug: "cf-post", Status: entity.ArticleDraft, Version: 1, AuthorID: 1,
+		CreatedAt: conformClock.Now(), UpdatedAt: conformClock.Now(),
+	}
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("differs: (-want +got)\n%s", d)
+	}
+	if _, err := r.GetArticle(ctx, db, a.ID+100); !errors.Is(err, ErrNotFound) {
+		t.Errorf("want ErrNotFound, but got %v", err)
+	}
+
+	// 古いバージョンでは更新できない
+	got.Status = entity.ArticlePublished
+	if err := r.UpdateArticle(ctx, db, got); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	stale := *want
+	if err := r.UpdateArticle(ctx, db, &stale); !errors.Is(err, ErrVersionConflict) {
+		t.Errorf("want ErrVersionConflict, but got %v", err)
+	}
+	got.Content, got.Slug = "書き換えた本文", ""
+	if err := r.ReplaceArticle(ctx, db, got); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, err = r.GetArticle(ctx, db, a.ID); err != nil {
+		t.Fatal(err)
+	}
+	if got.Version != 3 || got.Content != "書き換えた本文" || got.Slug != "" || got.Status != entity.ArticlePublished {
+		t.Errorf("unexpected article: %+v", got)
+	}
+
+	articles, err := r.ListArticles(ctx, db)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if find(articles, func(x *entity.Article) bool { return x.ID == a.ID }) == nil {
+		t.Errorf("want article %d to be listed", a.ID)
+	}
+	counts, err := r.CountArticlesByStatus(ctx, db)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if counts[entity.ArticlePublished] < 3 {
+		t.Errorf("want at least 3 published articles, but got %v", counts)
+	}
+}
+
+func conformTag(t *testing.T, db ExecQueryer) {
+	ctx := context.Background()
+	r := &Repository{Clocker: conformClock}
+
+	a := &entity.Article{Title: "記事", Status: entity.ArticlePublished}
+	if err := r.AddArticle(ctx, db, a); err != nil {
+		t.Fatal(err)
+	}
+	var ids []entity.TagID
+	for _, name := range []string{"cf-go", "cf-docker"} {
+		tg := &entity.Tag{Name: name}
+		if err := r.AddTag(ctx, db, tg); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := r.AddArticleTag(ctx, db, a.ID, tg.ID); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		ids = append(ids, tg.ID)
+	}
+	if err := r.AddTag(ctx, db, &entity.Tag{Name: "CF-Go"}); !errors.Is(err, ErrAlreadyExists) {
+		t.Errorf("want ErrAlreadyExists, but got %v", err)
+	}
+
+	tags, err := r.ListArticleTags(ctx, db)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if d := cmp.Diff([]string{"cf-docker", "cf-go"}, tags[a.ID]); d != "" {
+		t.Errorf("differs: (-want +got)\n%s", d)
+	}
+	ats, err := r.ListArticleTagIDs(ctx, db)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n := len(filter(ats, func(at entity.ArticleTag) bool { return at.ArticleID == a.ID })); n != 2 {
+		t.Errorf("want 2 article tags, but got %d", n)
+	}
+
+	if err := r.DeleteArticleTags(ctx, db, a.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tags, err = r.ListArticleTags(ctx, db); err != nil || len(tags[a.ID]) != 0 {
+		t.Errorf("want tags to be deleted, but got %v (%v)", tags[a.ID], err)
+	}
+	all, err := r.ListTags(ctx, db)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n := len(filter(all, func(tg *entity.Tag) bool { return tg.ID == ids[0] || tg.ID == ids[1] })); n != 2 {
+		t.Errorf("want tags to remain, but got %d", n)
+	}
+}
+
+func conformComment(t *testing.T, db ExecQueryer) {
+	ctx := context.Background()
+	r := &Repository{Clocker: conformClock}
+
+	created := conformClock.Now().Add(-time.Hour)
+	want := []*entity.Comment{
+		{ArticleID: 1, UserID: 2, Body: "ユーザ"},
+		{ArticleID: 1, AuthorName: "読者", Body: "ゲスト", CreatedAt: created},
+	}
+	for _, c := range want {
+		if err := r.AddComment(ctx, db, c); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	got, err := r.ListComments(ctx, db)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got = filter(got, func(c *entity.Comment) bool { return c.ID == want[0].ID || c.ID == want[1].ID })
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("differs: (-want +got)\n%s", d)
+	}
+}
+
+func conformMedia(t *testing.T, db ExecQueryer) {
+	ctx := context.Background()
+	r := &Repository{Clocker: conformClock}
+
+	m := &entity.Media{Key: "cf0123456789abcd/top.png", ContentType: "image/png", Size: 4, SHA256: "abc"}
+	if err := r.AddMedia(ctx, db, m); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := r.AddMedia(ctx, db, &entity.Media{Key: m.Key}); !errors.Is(err, ErrAlreadyExists) {
+		t.Errorf("want ErrAlreadyExists, but got %v", err)
+	}
+	ms, err := r.ListMedia(ctx, db)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if d := cmp.Diff(m, find(ms, func(x *entity.Media) bool { return x.Key == m.Key })); d != "" {
+		t.Errorf("differs: (-want +got)\n%s", d)
+	}
+}
+
+// 重複したファイルを追加しても、同じトランザクションで書き込みを続けてコミットできる
+// (PostgreSQL では失敗した文があるとトランザクション全体が中断される)
+func conformMediaInTx(t *testing.T, db ExecQueryer) {
+	ctx := context.Background()
+	r := &Repository{Clocker: conformClock}
+
+	// MySQL は既にトランザクションの中で実行している
+	tx := db
+	var commit func() error
+	if b, ok := db.(Beginner); ok {
+		x, err := b.BeginTxx(ctx, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { _ = x.Rollback() })
+		tx, commit = x, x.Commit
+	}
+
+	m := &entity.Media{Key: "cf0123456789abcd/dup.png", ContentType: "image/png", Size: 4, SHA256: "abc"}
+	if err := r.AddMedia(ctx, tx, m); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := r.AddMedia(ctx, tx, &entity.Media{Key: m.Key}); !errors.Is(err, ErrAlreadyExists) {
+		t.Errorf("want ErrAlreadyExists, but got %v", err)
+	}
+	if err := r.AddTag(ctx, tx, &entity.Tag{Name: "cf-after-dup"}); err != nil {
+		t.Fatalf("want to continue the transaction, but got %v", err)
+	}
+	if commit != nil {
+		if err := commit(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+}
+
+func conformArticleSource(t *testing.T, db ExecQueryer) {
+	ctx := context.Background()
+	r := &Repository{Clocker: conformClock}
+
+	if _, err := r.GetArticleSource(ctx, db, "cf/post.md"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("want ErrNotFound, but got %v", err)
+	}
+	// 同じ取り込み元を保存し直すと、記事とハッシュ値を更新する
+	for _, s := range []*entity.ArticleSource{
+		{Source: "cf/post.md", ArticleID: 1, Checksum: "old"},
+		{Source: "cf/post.md", ArticleID: 2, Checksum: "new"},
+	} {
+		if err := r.SaveArticleSource(ctx, db, s); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	got, err := r.GetArticleSource(ctx, db, "cf/post.md")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := &entity.ArticleSource{Source: "cf/post.md", ArticleID: 2, Checksum: "new", CreatedAt: conformClock.Now(), UpdatedAt: conformClock.Now()}
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("differs: (-want +got)\n%s", d)
+	}
+	all, err := r.ListArticleSources(ctx, db)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n := len(filter(all, func(s *entity.ArticleSource) bool { return s.Source == "cf/post.md" })); n != 1 {
+		t.Errorf("want 1 source, but got %d", n)
+	}
+}
+
+func conformIdempotency(t *testing.T, db ExecQueryer) {
+	ctx := context.Background()
+	c := &testClock{now: conformClock.Now()}
+	ir := &IdempotencyRepository{DB: db, Clocker: c}
+
+	rec := &entity.IdempotencyRecord{Key: "cf-key", RequestHash: "hash"}
+	if err := ir.Reserve(ctx, rec, time.Minute); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := ir.Reserve(ctx, &entity.IdempotencyRecord{Key: "cf-key"}, time.Minute); !errors.Is(err, ErrAlreadyExists) {
+		t.Errorf("want ErrAlreadyExists, but got %v", err)
+	}
+	rec.Status, rec.ContentType, rec.Body = 201, "application/json", []byte(`{"id":1}`)
+	if err := ir.Complete(ctx, rec); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got, err := ir.Get(ctx, "cf-key")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if d := cmp.Diff(rec, got); d != "" {
+		t.Errorf("differs: (-want +got)\n%s", d)
+	}
+
+	// 期限切れのキーは登録し直せる
+	c.now = c.now.Add(2 * time.Minute)
+	if err := ir.Reserve(ctx, &entity.IdempotencyRecord{Key: "cf-key", RequestHash: "other"}, time.Minute); err != nil {
+		t.Errorf("want expired key to be reserved again, but got %v", err)
+	}
+	if err := ir.Delete(ctx, "cf-key"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := ir.Get(ctx, "cf-key"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("want ErrNotFound, but got %v", err)
+	}
+}
+
+func conformRestore(t *testing.T, db ExecQueryer) {
+	ctx := context.Background()
+	r := &Repository{Clocker: conformClock}
+
+	// 識別子と日時はそのまま保存し、復元後に追加したレコードは続きの識別子になる
+	at := conformClock.Now().Add(-24 * time.Hour)
+	u := &entity.User{ID: 900001, Name: "cf-restored", Email: "r@example.com", Password: "hash", Role: entity.RoleAdmin, CreatedAt: at, UpdatedAt: at}
+	if err := r.RestoreUser(ctx, db, u); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	a := &entity.Article{ID: 900002, Title: "復元", Status: entity.ArticlePublished, Version: 7, CreatedAt: at, UpdatedAt: at}
+	if err := r.RestoreArticle(ctx, db, a); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := r.RestoreComment(ctx, db, &entity.Comment{ID: 900003, ArticleID: a.ID, AuthorName: "読者", Body: "本文", CreatedAt: at}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := r.RestoreTag(ctx, db, &entity.Tag{ID: 900004, Name: "cf-restored", CreatedAt: at}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := r.RestoreMedia(ctx, db, &entity.Media{Key: "cf-restored.png", CreatedAt: at}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := r.RestoreArticleSource(ctx, db, &entity.ArticleSource{Source: "cf/restored.md", ArticleID: a.ID, CreatedAt: at, UpdatedAt: at}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, err := r.GetArticle(ctx, db, a.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if d := cmp.Diff(a, got); d != "" {
+		t.Errorf("differs: (-want +got)\n%s", d)
+	}
+	next := &entity.Article{Title: "次", Status: entity.ArticleDraft}
+	if err := r.AddArticle(ctx, db, next); err != nil {
+		t.Fatal(err)
+	}
+	if next.ID <= a.ID {
+		t.Errorf("want id after %d, but got %d", a.ID, next.ID)
+	}
+	found, err := r.HasBackupData(ctx, db)
+	if err != nil || !found {
+		t.Errorf("want data to be found, but got %v (%v)", found, err)
+	}
+}
+
+// 呼び出し側で時刻を進められる Clocker
+type testClock struct{ now time.Time }
+
+func (c *testClock) Now() time.Time { return c.now }
+
+func find[T any](s []T, f func(T) bool) T {
+	var zero T
+	for _, v := range s {
+		if f(v) {
+			return v
+		}
+	}
+	return zero
+}
+
+func filter[T any](s []T, f func(T) bool) []T {
+	var out []T
+	for _, v := range s {
+		if f(v) {
+			out = append(out, v)
+		}
+	}
+	return out
+}
@@ -0,0 +1,179 @@
+package store
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/iinuma0710/react-go-blog/backend/config"
+	"github.com/iinuma0710/react-go-blog/backend/logger"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// 疎通確認の待ち時間の上限
+const pingTimeout = 2 * time.Second
+
+// 接続を試行し直すまでの待ち時間
+// 試行ごとに倍にして max で打ち止めにし、複数のサーバが同時に試行し直さないよう半分までの範囲でずらす
+type backoff struct {
+	initial, max time.Duration
+}
+
+var defaultBackoff = backoff{initial: 500 * time.Millisecond, max: 10 * time.Second}
+
+// n 回目 (0 始まり) の試行に失敗した後の待ち時間
+func (b backoff) delay(n int) time.Duration {
+	d := b.initial
+	for i := 0; i < n && d < b.max; i++ {
+		d *= 2
+	}
+	d = min(d, b.max)
+	return d/2 + rand.N(d/2+1)
+}
+
+// 接続先ごとの設定から database/sql のコネクタを作成する
+// socket を指定した場合は host と port の代わりに UNIX ドメインソケットで接続する
+func connector(cfg *config.Config, host string, port int, socket string) (driver.Connector, error) {
+	if cfg.DBDriver == "postgres" {
+		return pq.NewConnector(postgresDSN(cfg, host, port, socket))
+	}
+	mc, err := mysqlConfig(cfg, host, port, socket)
+	if err != nil {
+		return nil, err
+	}
+	return mysql.NewConnector(mc)
+}
+
+// MySQL の接続設定を作成する
+func mysqlConfig(cfg *config.Config, host string, port int, socket string) (*mysql.Config, error) {
+	mc := mysql.NewConfig()
+	mc.User = cfg.DBUser
+	mc.Passwd = cfg.DBPassword
+	mc.DBName = cfg.DBName
+	// 時刻情報の取得に必須
+	mc.ParseTime = true
+	if socket != "" {
+		mc.Net, mc.Addr = "unix", socket
+	} else {
+		mc.Net, mc.Addr = "tcp", net.JoinHostPort(host, strconv.Itoa(port))
+	}
+
+	switch cfg.DBTLSMode {
+	case "require":
+		tc, err := dbTLSConfig(cfg)
+		if err != nil {
+			return nil, err
+		}
+		tc.InsecureSkipVerify = true
+		mc.TLS = tc
+	case "verify-full":
+		tc, err := dbTLSConfig(cfg)
+		if err != nil {
+			return nil, err
+		}
+		// サーバ名を省略した場合は、ドライバが接続先のホスト名を使う
+		tc.ServerName = cfg.DBTLSServerName
+		mc.TLS = tc
+	}
+	return mc, nil
+}
+
+// MySQL との通信に使う TLS の設定を作成する
+// BLOG_DATABASE_TLS_CA_FILE を指定した場合はその CA だけを信頼し、
+// BLOG_DATABASE_TLS_CERT_FILE と BLOG_DATABASE_TLS_KEY_FILE を指定した場合はクライアント証明書を提示する
+func dbTLSConfig(cfg *config.Config) (*tls.Config, error) {
+	tc := &tls.Config{MinVersion: tls.VersionTLS12}
+	if cfg.DBTLSCAFile != "" {
+		pem, err := os.ReadFile(cfg.DBTLSCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read BLOG_DATABASE_TLS_CA_FILE: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", cfg.DBTLSCAFile)
+		}
+		tc.RootCAs = pool
+	}
+	if cfg.DBTLSCertFile != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.DBTLSCertFile, cfg.DBTLSKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("cannot load database client certificate: %w", err)
+		}
+		tc.Certificates = []tls.Certificate{cert}
+	}
+	return tc, nil
+}
+
+// コネクションプールの上限を設定する
+func setPoolLimits(db *sql.DB, cfg *config.Config) {
+	db.SetMaxOpenConns(cfg.DBMaxOpenConns)
+	db.SetMaxIdleConns(cfg.DBMaxIdleConns)
+	db.SetConnMaxLifetime(cfg.DBConnMaxLifetime)
+}
+
+// 疎通を確認できるまで、最大 maxTrial 回まで間隔を空けて接続を試行する
+// ctx が終了した場合は待たずに諦める
+func open(ctx context.Context, driverName string, c driver.Connector, cfg *config.Config, maxTrial int, b backoff) (*sqlx.DB, func(), error) {
+	l := logger.FromContext(ctx)
+	db := sql.OpenDB(c)
+	setPoolLimits(db, cfg)
+
+	var err error
+	for i := 0; i < maxTrial; i++ {
+		l.Info("database connection trial", "driver", driverName, "trial", i+1)
+		if err = ping(ctx, db); err == nil {
+			return sqlx.NewDb(db, driverName), func() { _ = db.Close() }, nil
+		}
+		if i == maxTrial-1 {
+			break
+		}
+
+		d := b.delay(i)
+		l.Warn("database ping failed", "error", err, "retry_in", d)
+		timer := time.NewTimer(d)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			_ = db.Close()
+			return nil, func() {}, fmt.Errorf("cannot connect to database: %w (last error: %v)", ctx.Err(), err)
+		case <-timer.C:
+		}
+	}
+
+	_ = db.Close()
+	if err == nil {
+		err = errors.New("no connection trial")
+	}
+	return nil, func() {}, fmt.Errorf("cannot connect to database after %d trials: %w", maxTrial, err)
+}
+
+func ping(ctx context.Context, db *sql.DB) error {
+	ctx, cancel := context.WithTimeout(ctx, pingTimeout)
+	defer cancel()
+	return db.PingContext(ctx)
+}
+
+// host または host:port の形式の接続先を分解する (ポートを省略した場合は port を使う)
+func splitHostPort(hostport string, port int) (string, int, error) {
+	host, p, err := net.SplitHostPort(hostport)
+	if err != nil {
+		// ポートを省略した場合
+		return hostport, port, nil
+	}
+	n, err := strconv.Atoi(p)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid port in %q: %w", hostport, err)
+	}
+	return host, n, nil
+}
@@ -0,0 +1,242 @@
+package store
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"database/sql/driver"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/iinuma0710/react-go-blog/backend/config"
+)
+
+// 自己署名の CA 証明書をファイルに書き出す
+func writeCACert(t *testing.T) string {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "blog-database-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(f, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+func TestMySQLConfig(t *testing.T) {
+	t.Parallel()
+
+	ca := writeCACert(t)
+	tests := map[string]struct {
+		cfg        config.Config
+		socket     string
+		wantNet    string
+		wantAddr   string
+		wantTLS    bool
+		wantVerify bool
+		wantErr    bool
+	}{
+		"tcp":  {wantNet: "tcp", wantAddr: "db:3306"},
+		"unix": {socket: "/var/run/mysqld/mysqld.sock", wantNet: "unix", wantAddr: "/var/run/mysqld/mysqld.sock"},
+		"require": {
+			cfg:     config.Config{DBTLSMode: "require"},
+			wantNet: "tcp", wantAddr: "db:3306", wantTLS: true,
+		},
+		"verifyFull": {
+			cfg:     config.Config{DBTLSMode: "verify-full", DBTLSCAFile: ca, DBTLSServerName: "db.example.com"},
+			wantNet: "tcp", wantAddr: "db:3306", wantTLS: true, wantVerify: true,
+		},
+		"unixVerifyFull": {
+			cfg:     config.Config{DBTLSMode: "verify-full", DBTLSCAFile: ca, DBTLSServerName: "db.example.com"},
+			socket:  "/var/run/mysqld/mysqld.sock",
+			wantNet: "unix", wantAddr: "/var/run/mysqld/mysqld.sock", wantTLS: true, wantVerify: true,
+		},
+		"missingCA": {
+			cfg:     config.Config{DBTLSMode: "verify-full", DBTLSCAFile: "testdata/missing.pem"},
+			wantErr: true,
+		},
+	}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := tt.cfg
+			cfg.DBUser, cfg.DBPassword, cfg.DBName = "blog", "secret", "blog"
+			mc, err := mysqlConfig(&cfg, "db", 3306, tt.socket)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("want error %v, but got %v", tt.wantErr, err)
+			}
+			if err != nil {
+				return
+			}
+			if mc.Net != tt.wantNet || mc.Addr != tt.wantAddr {
+				t.Errorf("want %s(%s), but got %s(%s)", tt.wantNet, tt.wantAddr, mc.Net, mc.Addr)
+			}
+			if !mc.ParseTime {
+				t.Error("want parseTime enabled")
+			}
+			if (mc.TLS != nil) != tt.wantTLS {
+				t.Fatalf("want TLS %v, but got %v", tt.wantTLS, mc.TLS)
+			}
+			if mc.TLS == nil {
+				return
+			}
+			if mc.TLS.InsecureSkipVerify == tt.wantVerify {
+				t.Errorf("want verification %v, but got InsecureSkipVerify %v", tt.wantVerify, mc.TLS.InsecureSkipVerify)
+			}
+			if tt.cfg.DBTLSCAFile != "" && mc.TLS.RootCAs == nil {
+				t.Error("want root CAs from BLOG_DATABASE_TLS_CA_FILE")
+			}
+			if mc.TLS.ServerName != tt.cfg.DBTLSServerName {
+				t.Errorf("want server name %q, but got %q", tt.cfg.DBTLSServerName, mc.TLS.ServerName)
+			}
+		})
+	}
+}
+
+func TestPostgresDSN(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		cfg    config.Config
+		socket string
+		want   string
+	}{
+		"tcp": {
+			cfg:  config.Config{DBTLSMode: "disable"},
+			want: "postgres://blog:secret@db:5432/blog?sslmode=disable",
+		},
+		"unix": {
+			cfg:    config.Config{DBTLSMode: "verify-full"},
+			socket: "/var/run/postgresql",
+			want:   "postgres://blog:secret@/blog?host=%2Fvar%2Frun%2Fpostgresql&port=5432&sslmode=disable",
+		},
+		"verifyFull": {
+			cfg:  config.Config{DBTLSMode: "verify-full", DBTLSCAFile: "/certs/ca.pem", DBTLSCertFile: "/certs/client.pem", DBTLSKeyFile: "/certs/client.key"},
+			want: "postgres://blog:secret@db:5432/blog?sslcert=%2Fcerts%2Fclient.pem&sslkey=%2Fcerts%2Fclient.key&sslmode=verify-full&sslrootcert=%2Fcerts%2Fca.pem",
+		},
+	}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := tt.cfg
+			cfg.DBUser, cfg.DBPassword, cfg.DBName = "blog", "secret", "blog"
+			if got := postgresDSN(&cfg, "db", 5432, tt.socket); got != tt.want {
+				t.Errorf("want %q, but got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestBackoff_Delay(t *testing.T) {
+	t.Parallel()
+
+	b := backoff{initial: 100 * time.Millisecond, max: time.Second}
+	for n, want := range []time.Duration{100, 200, 400, 800, 1000, 1000} {
+		want *= time.Millisecond
+		for range 20 {
+			if d := b.delay(n); d < want/2 || d > want {
+				t.Errorf("want delay of trial %d between %s and %s, but got %s", n, want/2, want, d)
+			}
+		}
+	}
+}
+
+// failures 回だけ接続に失敗するコネクタ
+type flakyConnector struct {
+	drv      driver.Driver
+	dsn      string
+	failures int32
+	trials   atomic.Int32
+}
+
+var errConnRefused = errors.New("connection refused")
+
+func (c *flakyConnector) Connect(context.Context) (driver.Conn, error) {
+	if c.trials.Add(1) <= c.failures {
+		return nil, errConnRefused
+	}
+	return c.drv.Open(c.dsn)
+}
+
+func (c *flakyConnector) Driver() driver.Driver { return c.drv }
+
+func TestOpen(t *testing.T) {
+	t.Parallel()
+
+	b := backoff{initial: time.Millisecond, max: 2 * time.Millisecond}
+	tests := map[string]struct {
+		failures   int32
+		cancel     bool
+		wantErr    error
+		wantTrials int32
+	}{
+		"firstTrial": {wantTrials: 1},
+		"retry":      {failures: 2, wantTrials: 3},
+		// 最後の試行のエラーを返す
+		"giveUp": {failures: 5, wantErr: errConnRefused, wantTrials: 3},
+		// 終了したコンテキストでは待たずに諦める
+		"canceled": {failures: 5, cancel: true, wantErr: context.Canceled},
+	}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			t.Parallel()
+
+			dsn := "open_" + n
+			sdb, _, err := sqlmock.NewWithDSN(dsn)
+			if err != nil {
+				t.Fatal(err)
+			}
+			t.Cleanup(func() { sdb.Close() })
+			c := &flakyConnector{drv: sdb.Driver(), dsn: dsn, failures: tt.failures}
+
+			ctx := context.Background()
+			if tt.cancel {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithCancel(ctx)
+				cancel()
+			}
+			cfg := &config.Config{DBMaxOpenConns: 5, DBMaxIdleConns: 2, DBConnMaxLifetime: time.Minute}
+			db, cleanup, err := open(ctx, DriverMySQL, c, cfg, 3, b)
+			defer cleanup()
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("want error %v, but got %v", tt.wantErr, err)
+			}
+			if got := c.trials.Load(); got != tt.wantTrials {
+				t.Errorf("want %d trials, but got %d", tt.wantTrials, got)
+			}
+			if err != nil {
+				return
+			}
+			if got := db.Stats().MaxOpenConnections; got != 5 {
+				t.Errorf("want max open connections 5, but got %d", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// sqlx.NewDb に渡すドライバ名
+// 方言の違いは、引数の Execer / Queryer のドライバ名で切り替える
+const (
+	DriverMySQL    = "mysql"
+	DriverSQLite   = "sqlite3"
+	DriverPostgres = "postgres"
+)
+
+// MySQL の重複キーエラーのエラー番号
+const mysqlErrDupEntry = 1062
+
+// PostgreSQL の一意制約違反のエラーコード
+const pqErrUniqueViolation = "23505"
+
+// トランザクションを実行し直せば成功する可能性のあるエラー
+const (
+	mysqlErrLockWaitTimeout = 1205
+	mysqlErrLockDeadlock    = 1213
+	pqErrSerialization      = "40001"
+	pqErrDeadlock           = "40P01"
+)
+
+// db のドライバ名を返す
+// sqlx.DB / sqlx.Tx 以外でドライバ名を持たない場合は MySQL とみなす
+func driverName(db any) string {
+	if d, ok := db.(interface{ DriverName() string }); ok {
+		return d.DriverName()
+	}
+	return DriverMySQL
+}
+
+// MySQL 向けに書いたクエリを db の方言に合わせる
+// クエリは ? のプレースホルダとバッククォートで囲んだ識別子で書き、
+// PostgreSQL では $n のプレースホルダとダブルクォートに置き換える
+func rebind(db any, query string) string {
+	if driverName(db) != DriverPostgres {
+		return query
+	}
+	return sqlx.Rebind(sqlx.DOLLAR, strings.ReplaceAll(query, "`", `"`))
+}
+
+// INSERT 文を実行し、自動採番された id を返す
+// PostgreSQL のドライバは LastInsertId に対応していないので、RETURNING id で受け取る
+func insertID(ctx context.Context, db Execer, query string, args ...any) (int64, error) {
+	if driverName(db) == DriverPostgres {
+		q, ok := db.(Queryer)
+		if !ok {
+			return 0, fmt.Errorf("%T cannot return inserted id", db)
+		}
+		var id int64
+		err := q.QueryRowxContext(ctx, rebind(db, query+" RETURNING id"), args...).Scan(&id)
+		return id, err
+	}
+
+	result, err := db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+// 一意制約の column が重複する行は挿入せずに読み飛ばす INSERT 文の末尾
+// 重複をエラーにしないので、PostgreSQL でもトランザクションが中断されずに後続の文を実行できる
+// 挿入したかどうかは RowsAffected が 0 かどうかで判定する
+func onConflictDoNothing(db any, column string) string {
+	if driverName(db) == DriverMySQL {
+		// INSERT IGNORE は重複以外のエラーも警告にしてしまうので、何も変えない更新にする
+		return fmt.Sprintf(" ON DUPLICATE KEY UPDATE `%[1]s` = `%[1]s`", column)
+	}
+	return fmt.Sprintf(" ON CONFLICT (`%s`) DO NOTHING", column)
+}
+
+// 識別子を指定して復元した後に、table の採番が既存の id と重ならないようにする
+// MySQL と SQLite は挿入した id に合わせて自動で進むので、PostgreSQL のシーケンスだけを更新する
+func syncSequence(ctx context.Context, db Execer, table string) error {
+	if driverName(db) != DriverPostgres {
+		return nil
+	}
+	query := fmt.Sprintf(`SELECT setval(pg_get_serial_sequence('"%[1]s"', 'id'), GREATEST(MAX(id), 1)) FROM "%[1]s"`, table)
+	_, err := db.ExecContext(ctx, query)
+	return err
+}
+
+// 一意制約に違反したエラーか
+func isDuplicate(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == mysqlErrDupEntry
+	}
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErr.Code == pqErrUniqueViolation
+	}
+	// SQLite のエラーはメッセージで判定する
+	// (cgo を無効にしてビルドしても、SQLite を使わなければ動作するようにドライバの型は参照しない)
+	return err != nil && (strings.HasPrefix(err.Error(), "UNIQUE constraint failed") ||
+		strings.HasPrefix(err.Error(), "PRIMARY KEY constraint failed"))
+}
+
+// デッドロックやロック待ちのタイムアウトで失敗したエラーか
+func isRetryable(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == mysqlErrLockDeadlock || mysqlErr.Number == mysqlErrLockWaitTimeout
+	}
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErr.Code == pqErrSerialization || pqErr.Code == pqErrDeadlock
+	}
+	return false
+}
@@ -0,0 +1,88 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/iinuma0710/react-go-blog/backend/clock"
+	"github.com/iinuma0710/react-go-blog/backend/entity"
+	"github.com/jmoiron/sqlx"
+)
+
+func TestRebind(t *testing.T) {
+	t.Parallel()
+
+	query := "SELECT id FROM `user` WHERE name = ? AND role = ?"
+	tests := map[string]struct {
+		driver string
+		want   string
+	}{
+		"mysql":    {driver: DriverMySQL, want: query},
+		"sqlite":   {driver: DriverSQLite, want: query},
+		"postgres": {driver: DriverPostgres, want: `SELECT id FROM "user" WHERE name = $1 AND role = $2`},
+	}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			t.Parallel()
+
+			db := sqlx.NewDb(nil, tt.driver)
+			if got := rebind(db, query); got != tt.want {
+				t.Errorf("want %q, but got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+// PostgreSQL では LastInsertId の代わりに RETURNING id で識別子を受け取る
+func TestRepository_AddArticle_Postgres(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	c := clock.FixedClocker{}
+	a := &entity.Article{Title: "タイトル", Content: "本文", Status: entity.ArticleDraft}
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	mock.ExpectQuery(regexp.QuoteMeta(`VALUES ($1, $2, NULLIF($3, ''), $4, NULLIF($5, 0), $6, $7) RETURNING id`)).
+		WithArgs(a.Title, a.Content, a.Slug, a.Status, a.AuthorID, c.Now(), c.Now()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(12))
+
+	r := &Repository{Clocker: c}
+	if err := r.AddArticle(ctx, sqlx.NewDb(db, DriverPostgres), a); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if a.ID != 12 {
+		t.Errorf("want id 12, but got %d", a.ID)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+// PostgreSQL では重複したファイルを ON CONFLICT で読み飛ばし、トランザクションを中断させない
+func TestRepository_AddMedia_Postgres(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	mock.ExpectExec(regexp.QuoteMeta(`VALUES ($1, $2, $3, $4, $5) ON CONFLICT ("storage_key") DO NOTHING`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	r := &Repository{Clocker: clock.FixedClocker{}}
+	if err := r.AddMedia(ctx, sqlx.NewDb(db, DriverPostgres), &entity.Media{Key: "0123456789abcdef/top.png"}); !errors.Is(err, ErrAlreadyExists) {
+		t.Errorf("want ErrAlreadyExists, but got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,186 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/iinuma0710/react-go-blog/backend/clock"
+	"github.com/iinuma0710/react-go-blog/backend/entity"
+)
+
+// 期限切れレコードを一度に削除する最大件数
+const idempotencyPurgeLimit = 100
+
+// 冪等性キーをデータベースに保存する
+// 複数のインスタンスで同じキーを共有する場合はこちらを使う
+type IdempotencyRepository struct {
+	DB      ExecQueryer
+	Clocker clock.Clocker
+}
+
+// キーを処理中の状態で登録する
+// 有効期限内の同じキーがすでに存在する場合は ErrAlreadyExists を返す
+func (ir *IdempotencyRepository) Reserve(ctx context.Context, rec *entity.IdempotencyRecord, ttl time.Duration) error {
+	now := ir.Clocker.Now()
+
+	// 期限切れのレコードは同じキーでも再利用できるように削除しておく
+	if _, err := ir.DB.ExecContext(ctx,
+		rebind(ir.DB, `DELETE FROM idempotency_key WHERE idempotency_key = ? AND expires_at < ?`), rec.Key, now,
+	); err != nil {
+		return err
+	}
+
+	rec.Status = 0
+	rec.CreatedAt = now
+	rec.ExpiresAt = now.Add(ttl)
+	sql := `INSERT INTO idempotency_key
+		(idempotency_key, request_hash, status, content_type, body, expires_at, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`
+	_, err := ir.DB.ExecContext(ctx, rebind(ir.DB, sql),
+		rec.Key, rec.RequestHash, rec.Status, rec.ContentType, rec.Body, rec.ExpiresAt, rec.CreatedAt,
+	)
+	if isDuplicate(err) {
+		return ErrAlreadyExists
+	}
+	return err
+}
+
+// 期限切れのレコードを削除し、削除した件数を返す
+// テーブルを長くロックしないよう、idempotencyPurgeLimit 件ずつ削除する
+func (ir *IdempotencyRepository) Purge(ctx context.Context) (int64, error) {
+	query := `DELETE FROM idempotency_key WHERE expires_at < ? LIMIT ?`
+	if driverName(ir.DB) != DriverMySQL {
+		// SQLite (標準のビルド) と PostgreSQL の DELETE は LIMIT を指定できない
+		query = `DELETE FROM idempotency_key WHERE idempotency_key IN
+			(SELECT idempotency_key FROM idempotency_key WHERE expires_at < ? LIMIT ?)`
+	}
+	now := ir.Clocker.Now()
+	var total int64
+	for {
+		res, err := ir.DB.ExecContext(ctx, rebind(ir.DB, query), now, idempotencyPurgeLimit)
+		if err != nil {
+			return total, err
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return total, err
+		}
+		total += n
+		if n < idempotencyPurgeLimit {
+			return total, nil
+		}
+	}
+}
+
+// 有効期限内のレコードを取得する
+func (ir *IdempotencyRepository) Get(ctx context.Context, key string) (*entity.IdempotencyRecord, error) {
+	rec := &entity.IdempotencyRecord{}
+	query := `SELECT idempotency_key, request_hash, status, content_type, body, expires_at, created_at
+		FROM idempotency_key WHERE idempotency_key = ? AND expires_at >= ?`
+	if err := ir.DB.GetContext(ctx, rec, rebind(ir.DB, query), key, ir.Clocker.Now()); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return rec, nil
+}
+
+// 処理中のレコードにレスポンスを保存する
+func (ir *IdempotencyRepository) Complete(ctx context.Context, rec *entity.IdempotencyRecord) error {
+	sql := `UPDATE idempotency_key SET status = ?, content_type = ?, body = ? WHERE idempotency_key = ?`
+	_, err := ir.DB.ExecContext(ctx, rebind(ir.DB, sql), rec.Status, rec.ContentType, rec.Body, rec.Key)
+	return err
+}
+
+// 再試行できるようにレコードを削除する
+func (ir *IdempotencyRepository) Delete(ctx context.Context, key string) error {
+	_, err := ir.DB.ExecContext(ctx, rebind(ir.DB, `DELETE FROM idempotency_key WHERE idempotency_key = ?`), key)
+	return err
+}
+
+// 冪等性キーをメモリ上に保存する
+// 単一インスタンスでの運用や開発環境向け
+type IdempotencyMemory struct {
+	Clocker clock.Clocker
+
+	mu      sync.Mutex
+	records map[string]entity.IdempotencyRecord
+}
+
+func NewIdempotencyMemory(c clock.Clocker) *IdempotencyMemory {
+	return &IdempotencyMemory{
+		Clocker: c,
+		records: map[string]entity.IdempotencyRecord{},
+	}
+}
+
+func (im *IdempotencyMemory) Reserve(ctx context.Context, rec *entity.IdempotencyRecord, ttl time.Duration) error {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	now := im.Clocker.Now()
+	// 期限切れのレコードは同じキーでも再利用できる
+	if r, ok := im.records[rec.Key]; ok && !r.ExpiresAt.Before(now) {
+		return ErrAlreadyExists
+	}
+
+	rec.Status = 0
+	rec.CreatedAt = now
+	rec.ExpiresAt = now.Add(ttl)
+	im.records[rec.Key] = *rec
+	return nil
+}
+
+// 期限切れのレコードを削除し、削除した件数を返す
+func (im *IdempotencyMemory) Purge(ctx context.Context) (int64, error) {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	now := im.Clocker.Now()
+	var n int64
+	for k, r := range im.records {
+		if r.ExpiresAt.Before(now) {
+			delete(im.records, k)
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (im *IdempotencyMemory) Get(ctx context.Context, key string) (*entity.IdempotencyRecord, error) {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	r, ok := im.records[key]
+	if !ok || r.ExpiresAt.Before(im.Clocker.Now()) {
+		return nil, ErrNotFound
+	}
+	return &r, nil
+}
+
+func (im *IdempotencyMemory) Complete(ctx context.Context, rec *entity.IdempotencyRecord) error {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	r, ok := im.records[rec.Key]
+	if !ok {
+		return ErrNotFound
+	}
+	r.Status = rec.Status
+	r.ContentType = rec.ContentType
+	r.Body = append([]byte(nil), rec.Body...)
+	im.records[rec.Key] = r
+	return nil
+}
+
+func (im *IdempotencyMemory) Delete(ctx context.Context, key string) error {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	delete(im.records, key)
+	return nil
+}
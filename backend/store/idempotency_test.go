@@ -0,0 +1,148 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
+	"github.com/iinuma0710/react-go-blog/backend/clock"
+	"github.com/iinuma0710/react-go-blog/backend/entity"
+	"github.com/jmoiron/sqlx"
+)
+
+// 任意の時刻を返せるテスト用の Clocker
+type stubClocker struct{ now time.Time }
+
+func (s *stubClocker) Now() time.Time { return s.now }
+
+func TestIdempotencyMemory(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	c := &stubClocker{now: clock.FixedClocker{}.Now()}
+	sut := NewIdempotencyMemory(c)
+
+	rec := &entity.IdempotencyRecord{Key: "key", RequestHash: "hash"}
+	if err := sut.Reserve(ctx, rec, time.Minute); err != nil {
+		t.Fatalf("want no error, but got %v", err)
+	}
+	if err := sut.Reserve(ctx, &entity.IdempotencyRecord{Key: "key"}, time.Minute); !errors.Is(err, ErrAlreadyExists) {
+		t.Fatalf("want ErrAlreadyExists, but got %v", err)
+	}
+
+	rec.Status = 201
+	rec.Body = []byte(`{"id":1}`)
+	if err := sut.Complete(ctx, rec); err != nil {
+		t.Fatalf("want no error, but got %v", err)
+	}
+	got, err := sut.Get(ctx, "key")
+	if err != nil {
+		t.Fatalf("want no error, but got %v", err)
+	}
+	if !got.Completed() || string(got.Body) != `{"id":1}` || got.RequestHash != "hash" {
+		t.Errorf("unexpected record: %+v", got)
+	}
+
+	// 有効期限が切れたら同じキーを再利用できる
+	c.now = c.now.Add(2 * time.Minute)
+	if _, err := sut.Get(ctx, "key"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("want ErrNotFound, but got %v", err)
+	}
+	if err := sut.Reserve(ctx, &entity.IdempotencyRecord{Key: "key"}, time.Minute); err != nil {
+		t.Errorf("want no error, but got %v", err)
+	}
+
+	// 期限切れのレコードだけを削除する
+	if err := sut.Reserve(ctx, &entity.IdempotencyRecord{Key: "old"}, time.Nanosecond); err != nil {
+		t.Fatalf("want no error, but got %v", err)
+	}
+	c.now = c.now.Add(time.Second)
+	if n, err := sut.Purge(ctx); err != nil || n != 1 {
+		t.Errorf("want 1 record purged, but got %d (%v)", n, err)
+	}
+	if _, err := sut.Get(ctx, "key"); err != nil {
+		t.Errorf("want no error, but got %v", err)
+	}
+}
+
+func TestIdempotencyRepository_Reserve(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		insertErr error
+		wantErr   error
+	}{
+		"ok":        {},
+		"duplicate": {insertErr: &mysql.MySQLError{Number: 1062}, wantErr: ErrAlreadyExists},
+	}
+
+	for n, tt := range tests {
+		tt := tt
+		t.Run(n, func(t *testing.T) {
+			t.Parallel()
+			ctx := context.Background()
+
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatal(err)
+			}
+			t.Cleanup(func() { db.Close() })
+
+			c := clock.FixedClocker{}
+			mock.ExpectExec(`DELETE FROM idempotency_key WHERE idempotency_key = \? AND expires_at < \?`).
+				WithArgs("key", c.Now()).
+				WillReturnResult(sqlmock.NewResult(0, 0))
+			exp := mock.ExpectExec(`INSERT INTO idempotency_key`).
+				WithArgs("key", "hash", 0, "", sqlmock.AnyArg(), c.Now().Add(time.Hour), c.Now())
+			if tt.insertErr != nil {
+				exp.WillReturnError(tt.insertErr)
+			} else {
+				exp.WillReturnResult(sqlmock.NewResult(0, 1))
+			}
+
+			sut := &IdempotencyRepository{DB: sqlx.NewDb(db, "mysql"), Clocker: c}
+			rec := &entity.IdempotencyRecord{Key: "key", RequestHash: "hash"}
+			if err := sut.Reserve(ctx, rec, time.Hour); !errors.Is(err, tt.wantErr) {
+				t.Errorf("want error %v, but got %v", tt.wantErr, err)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestIdempotencyRepository_Purge(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	// 上限の件数を削除できた場合は、残りがなくなるまで繰り返す
+	c := clock.FixedClocker{}
+	mock.ExpectExec(`DELETE FROM idempotency_key WHERE expires_at < \? LIMIT \?`).
+		WithArgs(c.Now(), idempotencyPurgeLimit).
+		WillReturnResult(sqlmock.NewResult(0, idempotencyPurgeLimit))
+	mock.ExpectExec(`DELETE FROM idempotency_key WHERE expires_at < \? LIMIT \?`).
+		WithArgs(c.Now(), idempotencyPurgeLimit).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	sut := &IdempotencyRepository{DB: sqlx.NewDb(db, "mysql"), Clocker: c}
+	n, err := sut.Purge(ctx)
+	if err != nil {
+		t.Fatalf("want no error, but got %v", err)
+	}
+	if n != idempotencyPurgeLimit+3 {
+		t.Errorf("want %d records purged, but got %d", idempotencyPurgeLimit+3, n)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
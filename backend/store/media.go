@@ -0,0 +1,32 @@
+package store
+
+import (
+	"context"
+
+	"github.com/iinuma0710/react-go-blog/backend/entity"
+)
+
+// 同じキーのファイルが既に存在する場合は ErrAlreadyExists を返す
+// 重複しても SQL のエラーにはしないので、トランザクションの中でそのまま続けられる
+func (r *Repository) AddMedia(ctx context.Context, db Execer, m *entity.Media) error {
+	m.CreatedAt = r.Clocker.Now()
+	sql := `INSERT INTO media
+		(storage_key, content_type, size, sha256, created_at)
+		VALUES (?, ?, ?, ?, ?)` + onConflictDoNothing(db, "storage_key")
+
+	result, err := db.ExecContext(ctx, rebind(db, sql), m.Key, m.ContentType, m.Size, m.SHA256, m.CreatedAt)
+	if err != nil {
+		if isDuplicate(err) {
+			return ErrAlreadyExists
+		}
+		return err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrAlreadyExists
+	}
+	return nil
+}
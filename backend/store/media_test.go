@@ -0,0 +1,62 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
+	"github.com/iinuma0710/react-go-blog/backend/clock"
+	"github.com/iinuma0710/react-go-blog/backend/entity"
+	"github.com/jmoiron/sqlx"
+)
+
+func TestRepository_AddMedia(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		affected int64
+		execErr  error
+		wantErr  error
+	}{
+		"ok": {affected: 1},
+		// 重複した行は挿入されない
+		"duplicate": {wantErr: ErrAlreadyExists},
+		"dupEntry":  {execErr: &mysql.MySQLError{Number: mysqlErrDupEntry}, wantErr: ErrAlreadyExists},
+	}
+
+	for n, tt := range tests {
+		tt := tt
+		t.Run(n, func(t *testing.T) {
+			t.Parallel()
+			ctx := context.Background()
+
+			c := clock.FixedClocker{}
+			m := &entity.Media{Key: "0123456789abcdef/top.png", ContentType: "image/png", Size: 4, SHA256: "abc"}
+
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatal(err)
+			}
+			t.Cleanup(func() { db.Close() })
+
+			e := mock.ExpectExec(`INSERT INTO media .* ON DUPLICATE KEY UPDATE`).
+				WithArgs(m.Key, m.ContentType, m.Size, m.SHA256, c.Now())
+			if tt.execErr != nil {
+				e.WillReturnError(tt.execErr)
+			} else {
+				e.WillReturnResult(sqlmock.NewResult(0, tt.affected))
+			}
+
+			xdb := sqlx.NewDb(db, "mysql")
+			r := &Repository{Clocker: c}
+			if err := r.AddMedia(ctx, xdb, m); !errors.Is(err, tt.wantErr) {
+				t.Errorf("want error %v, but got %v", tt.wantErr, err)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,386 @@
+package store
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/iinuma0710/react-go-blog/backend/clock"
+	"github.com/iinuma0710/react-go-blog/backend/entity"
+)
+
+// データベースを使わずに、メモリ上にデータを保持するリポジトリ
+// Repository と同じメソッドを持ち、引数の db は使わない (nil でもよい)
+// 開発環境での動作確認や、データベースを用意しないテストで使う
+type MemoryRepository struct {
+	Clocker clock.Clocker
+
+	mu   sync.RWMutex
+	data memoryData
+}
+
+// メモリ上に保持するデータ
+// 一覧の順序が実行ごとに変わらないよう、取り出すときは識別子の順に並べる
+type memoryData struct {
+	lastArticleID entity.ArticleID
+	lastUserID    entity.UserID
+	lastTagID     entity.TagID
+	lastCommentID entity.CommentID
+
+	articles    map[entity.ArticleID]entity.Article
+	users       map[entity.UserID]entity.User
+	tags        map[entity.TagID]entity.Tag
+	articleTags map[entity.ArticleTag]struct{}
+	comments    map[entity.CommentID]entity.Comment
+	media       map[string]entity.Media
+	sources     map[string]entity.ArticleSource
+}
+
+func NewMemoryRepository(c clock.Clocker) *MemoryRepository {
+	return &MemoryRepository{Clocker: c, data: newMemoryData()}
+}
+
+func newMemoryData() memoryData {
+	return memoryData{
+		articles:    map[entity.ArticleID]entity.Article{},
+		users:       map[entity.UserID]entity.User{},
+		tags:        map[entity.TagID]entity.Tag{},
+		articleTags: map[entity.ArticleTag]struct{}{},
+		comments:    map[entity.CommentID]entity.Comment{},
+		media:       map[string]entity.Media{},
+		sources:     map[string]entity.ArticleSource{},
+	}
+}
+
+// ロールバックのための複製 (値はすべてコピーで保持しているので、マップだけを複製すればよい)
+func (d memoryData) clone() memoryData {
+	d.articles = maps.Clone(d.articles)
+	d.users = maps.Clone(d.users)
+	d.tags = maps.Clone(d.tags)
+	d.articleTags = maps.Clone(d.articleTags)
+	d.comments = maps.Clone(d.comments)
+	d.media = maps.Clone(d.media)
+	d.sources = maps.Clone(d.sources)
+	return d
+}
+
+type memoryTxKey struct{}
+
+// fn の中での変更を 1 つのトランザクションとして扱う (Transactor と同じ使い方ができる)
+// fn がエラーを返すかパニックした場合は、fn の中での変更をすべて取り消す
+// トランザクションは 1 つずつ実行し、実行中は他の読み書きを待たせる
+// fn には ctx を引き継いだコンテキストと nil の tx を渡し、そのコンテキストでの呼び出しは同じトランザクションとして扱う
+// 入れ子で呼び出した場合は、内側の fn が失敗すると内側での変更だけを取り消す (opts は使わない)
+func (m *MemoryRepository) InTx(ctx context.Context, fn func(ctx context.Context, tx ExecQueryer) error, opts ...TxOption) (err error) {
+	if !m.inTx(ctx) {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		ctx = context.WithValue(ctx, memoryTxKey{}, m)
+	}
+
+	snapshot := m.data.clone()
+	committed := false
+	defer func() {
+		if !committed {
+			m.data = snapshot
+		}
+	}()
+
+	if err := fn(ctx, nil); err != nil {
+		return err
+	}
+	committed = true
+	return nil
+}
+
+func (m *MemoryRepository) inTx(ctx context.Context) bool {
+	tx, _ := ctx.Value(memoryTxKey{}).(*MemoryRepository)
+	return tx == m
+}
+
+// 書き込み用のロックを取得する
+// トランザクションの中ではロックを取得済みなので何もしない
+func (m *MemoryRepository) lock(ctx context.Context) func() {
+	if m.inTx(ctx) {
+		return func() {}
+	}
+	m.mu.Lock()
+	return m.mu.Unlock
+}
+
+// 読み出し用のロックを取得する
+func (m *MemoryRepository) rlock(ctx context.Context) func() {
+	if m.inTx(ctx) {
+		return func() {}
+	}
+	m.mu.RLock()
+	return m.mu.RUnlock
+}
+
+// MySQL の照合順序に合わせ、一意制約は大文字と小文字を区別しない
+func sameKey(a, b string) bool {
+	return strings.EqualFold(a, b)
+}
+
+func (m *MemoryRepository) ListArticles(ctx context.Context, db Queryer) (entity.Articles, error) {
+	defer m.rlock(ctx)()
+
+	articles := entity.Articles{}
+	for _, id := range sortedKeys(m.data.articles) {
+		a := m.data.articles[id]
+		articles = append(articles, &a)
+	}
+	return articles, nil
+}
+
+// 同じスラッグの記事が既に存在する場合は ErrAlreadyExists を返す
+// 作成日時が設定されている場合は、取り込み元の日時を残すためにそのまま使う
+func (m *MemoryRepository) AddArticle(ctx context.Context, db Execer, a *entity.Article) error {
+	defer m.lock(ctx)()
+
+	if m.slugTaken(a.Slug, 0) {
+		return ErrAlreadyExists
+	}
+	if a.CreatedAt.IsZero() {
+		a.CreatedAt = m.Clocker.Now()
+	}
+	if a.UpdatedAt.IsZero() {
+		a.UpdatedAt = a.CreatedAt
+	}
+	m.data.lastArticleID++
+	a.ID = m.data.lastArticleID
+	a.Version = 1
+	m.data.articles[a.ID] = *a
+	return nil
+}
+
+func (m *MemoryRepository) slugTaken(slug string, except entity.ArticleID) bool {
+	if slug == "" {
+		return false
+	}
+	for id, a := range m.data.articles {
+		if id != except && sameKey(a.Slug, slug) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *MemoryRepository) GetArticle(ctx context.Context, db Queryer, id entity.ArticleID) (*entity.Article, error) {
+	defer m.rlock(ctx)()
+
+	a, ok := m.data.articles[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &a, nil
+}
+
+// a.Version と一致するバージョンの場合のみ更新し、バージョンを 1 つ進める
+func (m *MemoryRepository) UpdateArticle(ctx context.Context, db Execer, a *entity.Article) error {
+	defer m.lock(ctx)()
+
+	cur, ok := m.data.articles[a.ID]
+	if !ok || cur.Version != a.Version {
+		return ErrVersionConflict
+	}
+	cur.Title = a.Title
+	cur.Status = a.Status
+	cur.UpdatedAt = m.Clocker.Now()
+	cur.Version++
+	m.data.articles[a.ID] = cur
+
+	a.Version = cur.Version
+	a.UpdatedAt = cur.UpdatedAt
+	return nil
+}
+
+// 本文やスラッグも含めて a の内容で置き換える
+func (m *MemoryRepository) ReplaceArticle(ctx context.Context, db Execer, a *entity.Article) error {
+	defer m.lock(ctx)()
+
+	cur, ok := m.data.articles[a.ID]
+	if !ok || cur.Version != a.Version {
+		return ErrVersionConflict
+	}
+	if m.slugTaken(a.Slug, a.ID) {
+		return ErrAlreadyExists
+	}
+	next := *a
+	next.CreatedAt = cur.CreatedAt
+	next.UpdatedAt = m.Clocker.Now()
+	next.Version++
+	m.data.articles[a.ID] = next
+
+	a.Version = next.Version
+	a.UpdatedAt = next.UpdatedAt
+	return nil
+}
+
+// ステータスごとの記事数を集計する
+func (m *MemoryRepository) CountArticlesByStatus(ctx context.Context, db Queryer) (map[entity.ArticleStatus]int64, error) {
+	defer m.rlock(ctx)()
+
+	counts := map[entity.ArticleStatus]int64{}
+	for _, a := range m.data.articles {
+		counts[a.Status]++
+	}
+	return counts, nil
+}
+
+// 同じ名前のユーザが既に存在する場合は ErrAlreadyExists を返す
+func (m *MemoryRepository) RegisterUser(ctx context.Context, db Execer, u *entity.User) error {
+	defer m.lock(ctx)()
+
+	for _, cur := range m.data.users {
+		if sameKey(cur.Name, u.Name) {
+			return ErrAlreadyExists
+		}
+	}
+	u.CreatedAt = m.Clocker.Now()
+	u.UpdatedAt = m.Clocker.Now()
+	m.data.lastUserID++
+	u.ID = m.data.lastUserID
+	m.data.users[u.ID] = *u
+	return nil
+}
+
+func (m *MemoryRepository) ListUsers(ctx context.Context, db Queryer) ([]*entity.User, error) {
+	defer m.rlock(ctx)()
+
+	users := []*entity.User{}
+	for _, id := range sortedKeys(m.data.users) {
+		u := m.data.users[id]
+		users = append(users, &u)
+	}
+	return users, nil
+}
+
+// 同じ名前のタグが既に存在する場合は ErrAlreadyExists を返す
+func (m *MemoryRepository) AddTag(ctx context.Context, db Execer, t *entity.Tag) error {
+	defer m.lock(ctx)()
+
+	for _, cur := range m.data.tags {
+		if sameKey(cur.Name, t.Name) {
+			return ErrAlreadyExists
+		}
+	}
+	t.CreatedAt = m.Clocker.Now()
+	m.data.lastTagID++
+	t.ID = m.data.lastTagID
+	m.data.tags[t.ID] = *t
+	return nil
+}
+
+// 記事にタグを付ける
+func (m *MemoryRepository) AddArticleTag(ctx context.Context, db Execer, articleID entity.ArticleID, tagID entity.TagID) error {
+	defer m.lock(ctx)()
+
+	at := entity.ArticleTag{ArticleID: articleID, TagID: tagID}
+	if _, ok := m.data.articleTags[at]; ok {
+		return ErrAlreadyExists
+	}
+	m.data.articleTags[at] = struct{}{}
+	return nil
+}
+
+// 記事ごとのタグ名を名前順に取得する
+func (m *MemoryRepository) ListArticleTags(ctx context.Context, db Queryer) (map[entity.ArticleID][]string, error) {
+	defer m.rlock(ctx)()
+
+	tags := make(map[entity.ArticleID][]string)
+	for at := range m.data.articleTags {
+		if t, ok := m.data.tags[at.TagID]; ok {
+			tags[at.ArticleID] = append(tags[at.ArticleID], t.Name)
+		}
+	}
+	for _, names := range tags {
+		sort.Strings(names)
+	}
+	return tags, nil
+}
+
+func (m *MemoryRepository) ListTags(ctx context.Context, db Queryer) ([]*entity.Tag, error) {
+	defer m.rlock(ctx)()
+
+	tags := []*entity.Tag{}
+	for _, id := range sortedKeys(m.data.tags) {
+		t := m.data.tags[id]
+		tags = append(tags, &t)
+	}
+	return tags, nil
+}
+
+// 記事に付いているタグをすべて外す
+func (m *MemoryRepository) DeleteArticleTags(ctx context.Context, db Execer, articleID entity.ArticleID) error {
+	defer m.lock(ctx)()
+
+	for at := range m.data.articleTags {
+		if at.ArticleID == articleID {
+			delete(m.data.articleTags, at)
+		}
+	}
+	return nil
+}
+
+// 作成日時が設定されている場合は、取り込み元の日時を残すためにそのまま使う
+func (m *MemoryRepository) AddComment(ctx context.Context, db Execer, c *entity.Comment) error {
+	defer m.lock(ctx)()
+
+	if c.CreatedAt.IsZero() {
+		c.CreatedAt = m.Clocker.Now()
+	}
+	m.data.lastCommentID++
+	c.ID = m.data.lastCommentID
+	m.data.comments[c.ID] = *c
+	return nil
+}
+
+// 同じキーのファイルが既に存在する場合は ErrAlreadyExists を返す
+func (m *MemoryRepository) AddMedia(ctx context.Context, db Execer, md *entity.Media) error {
+	defer m.lock(ctx)()
+
+	if _, ok := m.data.media[md.Key]; ok {
+		return ErrAlreadyExists
+	}
+	md.CreatedAt = m.Clocker.Now()
+	m.data.media[md.Key] = *md
+	return nil
+}
+
+func (m *MemoryRepository) GetArticleSource(ctx context.Context, db Queryer, source string) (*entity.ArticleSource, error) {
+	defer m.rlock(ctx)()
+
+	s, ok := m.data.sources[source]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &s, nil
+}
+
+// 取り込み元ごとに 1 件だけ保存し、既に存在する場合は記事とハッシュ値を更新する
+func (m *MemoryRepository) SaveArticleSource(ctx context.Context, db Execer, s *entity.ArticleSource) error {
+	defer m.lock(ctx)()
+
+	now := m.Clocker.Now()
+	created := now
+	if cur, ok := m.data.sources[s.Source]; ok {
+		created = cur.CreatedAt
+	}
+	if s.CreatedAt.IsZero() {
+		s.CreatedAt = now
+	}
+	s.UpdatedAt = now
+	saved := *s
+	saved.CreatedAt = created
+	m.data.sources[s.Source] = saved
+	return nil
+}
+
+// マップのキーを昇順に返す
+func sortedKeys[K ~int64, V any](m map[K]V) []K {
+	return slices.Sorted(maps.Keys(m))
+}
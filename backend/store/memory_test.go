@@ -0,0 +1,219 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/iinuma0710/react-go-blog/backend/clock"
+	"github.com/iinuma0710/react-go-blog/backend/entity"
+)
+
+func TestMemoryRepository_Article(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	c := clock.FixedClocker{}
+	m := NewMemoryRepository(c)
+
+	for _, title := range []string{"1 件目", "2 件目", "3 件目"} {
+		if err := m.AddArticle(ctx, nil, &entity.Article{Title: title, Slug: title, Status: entity.ArticleDraft}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	// スラッグの一意制約は大文字と小文字を区別しない
+	if err := m.AddArticle(ctx, nil, &entity.Article{Title: "重複", Slug: "1 件目"}); !errors.Is(err, ErrAlreadyExists) {
+		t.Errorf("want ErrAlreadyExists, but got %v", err)
+	}
+
+	got, err := m.ListArticles(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := entity.Articles{
+		{ID: 1, Title: "1 件目", Slug: "1 件目", Status: entity.ArticleDraft, Version: 1, CreatedAt: c.Now(), UpdatedAt: c.Now()},
+		{ID: 2, Title: "2 件目", Slug: "2 件目", Status: entity.ArticleDraft, Version: 1, CreatedAt: c.Now(), UpdatedAt: c.Now()},
+		{ID: 3, Title: "3 件目", Slug: "3 件目", Status: entity.ArticleDraft, Version: 1, CreatedAt: c.Now(), UpdatedAt: c.Now()},
+	}
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("differs: (-want +got)\n%s", d)
+	}
+
+	// 返した記事を書き換えても、保存した内容は変わらない
+	got[0].Title = "書き換え"
+	a, err := m.GetArticle(ctx, nil, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Title != "1 件目" {
+		t.Errorf("want stored title unchanged, but got %q", a.Title)
+	}
+
+	a.Status = entity.ArticlePublished
+	if err := m.UpdateArticle(ctx, nil, a); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if a.Version != 2 {
+		t.Errorf("want version 2, but got %d", a.Version)
+	}
+	a.Version = 1
+	if err := m.UpdateArticle(ctx, nil, a); !errors.Is(err, ErrVersionConflict) {
+		t.Errorf("want ErrVersionConflict, but got %v", err)
+	}
+	if _, err := m.GetArticle(ctx, nil, 99); !errors.Is(err, ErrNotFound) {
+		t.Errorf("want ErrNotFound, but got %v", err)
+	}
+
+	counts, err := m.CountArticlesByStatus(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := cmp.Diff(map[entity.ArticleStatus]int64{entity.ArticleDraft: 2, entity.ArticlePublished: 1}, counts); d != "" {
+		t.Errorf("differs: (-want +got)\n%s", d)
+	}
+}
+
+func TestMemoryRepository_InTx(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	errFail := errors.New("fail")
+
+	tests := map[string]struct {
+		fn        func(ctx context.Context, m *MemoryRepository) error
+		wantErr   error
+		wantPanic bool
+		wantCount int
+	}{
+		"commit": {
+			fn: func(ctx context.Context, m *MemoryRepository) error {
+				return m.AddArticle(ctx, nil, &entity.Article{Title: "追加"})
+			},
+			wantCount: 2,
+		},
+		"rollbackOnError": {
+			fn: func(ctx context.Context, m *MemoryRepository) error {
+				if err := m.AddArticle(ctx, nil, &entity.Article{Title: "追加"}); err != nil {
+					return err
+				}
+				return errFail
+			},
+			wantErr:   errFail,
+			wantCount: 1,
+		},
+		"rollbackOnPanic": {
+			fn: func(ctx context.Context, m *MemoryRepository) error {
+				_ = m.AddArticle(ctx, nil, &entity.Article{Title: "追加"})
+				panic("boom")
+			},
+			wantPanic: true,
+			wantCount: 1,
+		},
+		// 入れ子のトランザクションは外側にまとめて取り消す
+		"nested": {
+			fn: func(ctx context.Context, m *MemoryRepository) error {
+				if err := m.InTx(ctx, func(ctx context.Context, tx ExecQueryer) error {
+					return m.AddArticle(ctx, tx, &entity.Article{Title: "内側"})
+				}); err != nil {
+					return err
+				}
+				return errFail
+			},
+			wantErr:   errFail,
+			wantCount: 1,
+		},
+		// 内側のトランザクションが失敗した場合は、内側の変更だけを取り消す
+		"nestedRollback": {
+			fn: func(ctx context.Context, m *MemoryRepository) error {
+				if err := m.AddArticle(ctx, nil, &entity.Article{Title: "外側"}); err != nil {
+					return err
+				}
+				err := m.InTx(ctx, func(ctx context.Context, tx ExecQueryer) error {
+					if err := m.AddArticle(ctx, tx, &entity.Article{Title: "内側"}); err != nil {
+						return err
+					}
+					return errFail
+				})
+				if !errors.Is(err, errFail) {
+					return fmt.Errorf("want errFail, but got %v", err)
+				}
+				return nil
+			},
+			wantCount: 2,
+		},
+	}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			t.Parallel()
+
+			m := NewMemoryRepository(clock.FixedClocker{})
+			if err := m.AddArticle(ctx, nil, &entity.Article{Title: "既存"}); err != nil {
+				t.Fatal(err)
+			}
+
+			func() {
+				defer func() {
+					if r := recover(); (r != nil) != tt.wantPanic {
+						t.Errorf("want panic %v, but got %v", tt.wantPanic, r)
+					}
+				}()
+				if err := m.InTx(ctx, func(ctx context.Context, tx ExecQueryer) error { return tt.fn(ctx, m) }); !errors.Is(err, tt.wantErr) {
+					t.Errorf("want error %v, but got %v", tt.wantErr, err)
+				}
+			}()
+
+			got, err := m.ListArticles(ctx, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != tt.wantCount {
+				t.Errorf("want %d articles, but got %d", tt.wantCount, len(got))
+			}
+			// 取り消した記事の識別子は再利用する
+			a := &entity.Article{Title: "後から追加"}
+			if err := m.AddArticle(ctx, nil, a); err != nil {
+				t.Fatal(err)
+			}
+			if a.ID != entity.ArticleID(tt.wantCount+1) {
+				t.Errorf("want id %d, but got %d", tt.wantCount+1, a.ID)
+			}
+		})
+	}
+}
+
+// 複数のゴルーチンから同時に書き込んでも、識別子が重複しない
+func TestMemoryRepository_Concurrent(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	m := NewMemoryRepository(clock.FixedClocker{})
+
+	const n = 50
+	var wg sync.WaitGroup
+	for range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := m.AddArticle(ctx, nil, &entity.Article{Title: "同時"}); err != nil {
+				t.Error(err)
+			}
+			if _, err := m.ListArticles(ctx, nil); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	got, err := m.ListArticles(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != n {
+		t.Fatalf("want %d articles, but got %d", n, len(got))
+	}
+	for i, a := range got {
+		if a.ID != entity.ArticleID(i+1) {
+			t.Errorf("want id %d at %d, but got %d", i+1, i, a.ID)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package store
+
+import (
+	"net"
+	"net/url"
+	"strconv"
+
+	"github.com/iinuma0710/react-go-blog/backend/config"
+	_ "github.com/lib/pq"
+)
+
+// PostgreSQL の接続文字列を作成する
+// 接続先は MySQL と同じ BLOG_DATABASE_* の設定を使う
+// socket を指定した場合は、そのディレクトリにある UNIX ドメインソケットで接続する
+func postgresDSN(cfg *config.Config, host string, port int, socket string) string {
+	u := url.URL{
+		Scheme: "postgres",
+		User:   url.UserPassword(cfg.DBUser, cfg.DBPassword),
+		Host:   net.JoinHostPort(host, strconv.Itoa(port)),
+		Path:   "/" + cfg.DBName,
+	}
+	q := url.Values{}
+	if socket != "" {
+		// ソケットのファイル名にはポート番号が含まれるので、ポートは残しておく
+		u.Host = ""
+		q.Set("host", socket)
+		q.Set("port", strconv.Itoa(port))
+	}
+
+	mode := cfg.DBTLSMode
+	if mode == "" || socket != "" {
+		// UNIX ドメインソケットでは TLS を使わない
+		mode = "disable"
+	}
+	q.Set("sslmode", mode)
+	if mode != "disable" {
+		if cfg.DBTLSCAFile != "" {
+			q.Set("sslrootcert", cfg.DBTLSCAFile)
+		}
+		if cfg.DBTLSCertFile != "" {
+			q.Set("sslcert", cfg.DBTLSCertFile)
+			q.Set("sslkey", cfg.DBTLSKeyFile)
+		}
+	}
+	u.RawQuery = q.Encode()
+	return u.String()
+}
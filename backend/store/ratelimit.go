@@ -0,0 +1,85 @@
+package store
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/iinuma0710/react-go-blog/backend/clock"
+	"github.com/iinuma0710/react-go-blog/backend/entity"
+)
+
+// 満杯になったバケットを掃除する間隔 (Take の呼び出し回数)
+const rateLimitSweepInterval = 1000
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+	limit  entity.RateLimit
+}
+
+// トークンバケットをメモリ上で管理する
+// 複数のインスタンスで共有する場合は同じインターフェースで外部ストアを実装する
+type RateLimitMemory struct {
+	Clocker clock.Clocker
+
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	calls   int
+}
+
+func NewRateLimitMemory(c clock.Clocker) *RateLimitMemory {
+	return &RateLimitMemory{
+		Clocker: c,
+		buckets: map[string]*bucket{},
+	}
+}
+
+// key のバケットからトークンを 1 つ取り出す
+func (rm *RateLimitMemory) Take(ctx context.Context, key string, limit entity.RateLimit) (entity.RateLimitResult, error) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+
+	now := rm.Clocker.Now()
+	rm.calls++
+	if rm.calls%rateLimitSweepInterval == 0 {
+		rm.sweep(now)
+	}
+
+	burst := float64(limit.Burst)
+	b, ok := rm.buckets[key]
+	if !ok {
+		b = &bucket{tokens: burst, last: now}
+		rm.buckets[key] = b
+	}
+	b.limit = limit
+
+	// 前回からの経過時間に応じてトークンを補充する
+	b.tokens = math.Min(burst, b.tokens+now.Sub(b.last).Seconds()*limit.Rate)
+	b.last = now
+
+	res := entity.RateLimitResult{Limit: limit.Burst}
+	if b.tokens >= 1 {
+		b.tokens--
+		res.Allowed = true
+	} else {
+		res.RetryAfter = secondsToDuration((1 - b.tokens) / limit.Rate)
+	}
+	res.Remaining = int(math.Floor(b.tokens))
+	res.Reset = secondsToDuration((burst - b.tokens) / limit.Rate)
+	return res, nil
+}
+
+// 満杯まで補充されているバケットは新しく作り直すのと同じなので削除する
+func (rm *RateLimitMemory) sweep(now time.Time) {
+	for k, b := range rm.buckets {
+		if b.tokens+now.Sub(b.last).Seconds()*b.limit.Rate >= float64(b.limit.Burst) {
+			delete(rm.buckets, k)
+		}
+	}
+}
+
+func secondsToDuration(s float64) time.Duration {
+	return time.Duration(math.Ceil(s * float64(time.Second)))
+}
@@ -0,0 +1,45 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/iinuma0710/react-go-blog/backend/clock"
+	"github.com/iinuma0710/react-go-blog/backend/entity"
+)
+
+func TestRateLimitMemory_Take(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	c := &stubClocker{now: clock.FixedClocker{}.Now()}
+	sut := NewRateLimitMemory(c)
+	limit := entity.RateLimit{Rate: 2, Burst: 3}
+
+	// バースト数まではすぐに取り出せる
+	for i := 0; i < 3; i++ {
+		res, _ := sut.Take(ctx, "a", limit)
+		if !res.Allowed || res.Remaining != 2-i {
+			t.Fatalf("take %d: unexpected result %+v", i, res)
+		}
+	}
+	res, _ := sut.Take(ctx, "a", limit)
+	if res.Allowed {
+		t.Fatal("want to be limited")
+	}
+	if res.RetryAfter != 500*time.Millisecond {
+		t.Errorf("want retry after 500ms, but got %v", res.RetryAfter)
+	}
+
+	// 別のキーには影響しない
+	if res, _ := sut.Take(ctx, "b", limit); !res.Allowed {
+		t.Error("want another key to be allowed")
+	}
+
+	// 0.5 秒でトークンが 1 つ補充される
+	c.now = c.now.Add(500 * time.Millisecond)
+	if res, _ := sut.Take(ctx, "a", limit); !res.Allowed {
+		t.Error("want to be allowed after refill")
+	}
+}
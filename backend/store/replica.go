@@ -0,0 +1,261 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"net"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/iinuma0710/react-go-blog/backend/logger"
+	"github.com/jmoiron/sqlx"
+)
+
+// 読み出し専用のレプリカ
+type Replica struct {
+	DB *sqlx.DB
+	// ログに出力する接続先の名前
+	Name    string
+	healthy atomic.Bool
+}
+
+// レプリカが読み出しに使える状態か
+func (r *Replica) Healthy() bool {
+	return r.healthy.Load()
+}
+
+// プライマリとレプリカをまとめた ExecQueryer
+// 書き込みとトランザクションはプライマリで実行し、読み出しは正常なレプリカに順番に振り分ける
+// 正常なレプリカがない場合や、WithSession のコンテキストで書き込んだ後の読み出しはプライマリで実行する
+type Cluster struct {
+	Primary  *sqlx.DB
+	Replicas []*Replica
+	// 許容するレプリケーションの遅延 (超えたレプリカには振り分けない)
+	MaxLag time.Duration
+	next   atomic.Uint64
+}
+
+var (
+	_ ExecQueryer = (*Cluster)(nil)
+	_ Beginner    = (*Cluster)(nil)
+)
+
+// 方言を切り替えられるよう、プライマリのドライバ名を返す
+func (c *Cluster) DriverName() string {
+	return c.Primary.DriverName()
+}
+
+// プライマリとすべてのレプリカを閉じる
+func (c *Cluster) Close() error {
+	errs := []error{c.Primary.Close()}
+	for _, r := range c.Replicas {
+		errs = append(errs, r.DB.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// リクエストごとの読み出しの状態
+type session struct {
+	written atomic.Bool
+}
+
+type sessionKey struct{}
+
+// 書き込んだ内容をその後の読み出しで必ず読めるよう、ctx を 1 つのリクエストとして扱う
+// 返したコンテキストで Cluster に書き込むと、以降の読み出しはプライマリで実行する
+func WithSession(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(sessionKey{}).(*session); ok {
+		return ctx
+	}
+	return context.WithValue(ctx, sessionKey{}, &session{})
+}
+
+type primaryKey struct{}
+
+// ctx での読み出しを、書き込みの有無に関わらずプライマリで実行する
+// 読み出した内容をもとに書き込むリクエストや、キャッシュに格納する値の読み出しで、レプリカの遅延による古い値を使わないようにする
+// 元の ctx のセッションには影響しない
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(WithSession(ctx), primaryKey{}, true)
+}
+
+func markWritten(ctx context.Context) {
+	if s, ok := ctx.Value(sessionKey{}).(*session); ok {
+		s.written.Store(true)
+	}
+}
+
+func written(ctx context.Context) bool {
+	if p, _ := ctx.Value(primaryKey{}).(bool); p {
+		return true
+	}
+	s, ok := ctx.Value(sessionKey{}).(*session)
+	return ok && s.written.Load()
+}
+
+// 読み出しに使うレプリカを選ぶ (プライマリで実行する場合は nil)
+func (c *Cluster) replica(ctx context.Context) *Replica {
+	if len(c.Replicas) == 0 || written(ctx) {
+		return nil
+	}
+	start := c.next.Add(1)
+	for i := range c.Replicas {
+		r := c.Replicas[(start+uint64(i))%uint64(len(c.Replicas))]
+		if r.Healthy() {
+			return r
+		}
+	}
+	return nil
+}
+
+// レプリカでの読み出しに失敗した場合は、接続の問題であればレプリカを切り離してプライマリで実行し直す
+func read[T any](ctx context.Context, c *Cluster, fn func(db *sqlx.DB) (T, error)) (T, error) {
+	r := c.replica(ctx)
+	if r == nil {
+		return fn(c.Primary)
+	}
+	v, err := fn(r.DB)
+	if err == nil || !isConnError(err) || ctx.Err() != nil {
+		return v, err
+	}
+	if r.healthy.CompareAndSwap(true, false) {
+		logger.FromContext(ctx).Warn("replica disconnected, falling back to primary", "replica", r.Name, "error", err)
+	}
+	return fn(c.Primary)
+}
+
+func (c *Cluster) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	markWritten(ctx)
+	return c.Primary.ExecContext(ctx, query, args...)
+}
+
+func (c *Cluster) NamedExecContext(ctx context.Context, query string, arg interface{}) (sql.Result, error) {
+	markWritten(ctx)
+	return c.Primary.NamedExecContext(ctx, query, arg)
+}
+
+func (c *Cluster) BeginTxx(ctx context.Context, opts *sql.TxOptions) (*sqlx.Tx, error) {
+	markWritten(ctx)
+	return c.Primary.BeginTxx(ctx, opts)
+}
+
+// 準備した文で書き込む場合もあるので、プライマリで準備する
+func (c *Cluster) PreparexContext(ctx context.Context, query string) (*sqlx.Stmt, error) {
+	return c.Primary.PreparexContext(ctx, query)
+}
+
+func (c *Cluster) QueryxContext(ctx context.Context, query string, args ...any) (*sqlx.Rows, error) {
+	return read(ctx, c, func(db *sqlx.DB) (*sqlx.Rows, error) {
+		return db.QueryxContext(ctx, query, args...)
+	})
+}
+
+// *sqlx.Row はエラーを Scan まで遅らせるので、失敗してもプライマリでは実行し直さない
+func (c *Cluster) QueryRowxContext(ctx context.Context, query string, args ...any) *sqlx.Row {
+	if r := c.replica(ctx); r != nil {
+		return r.DB.QueryRowxContext(ctx, query, args...)
+	}
+	return c.Primary.QueryRowxContext(ctx, query, args...)
+}
+
+func (c *Cluster) GetContext(ctx context.Context, dest interface{}, query string, args ...any) error {
+	_, err := read(ctx, c, func(db *sqlx.DB) (struct{}, error) {
+		return struct{}{}, db.GetContext(ctx, dest, query, args...)
+	})
+	return err
+}
+
+func (c *Cluster) SelectContext(ctx context.Context, dest interface{}, query string, args ...any) error {
+	_, err := read(ctx, c, func(db *sqlx.DB) (struct{}, error) {
+		return struct{}{}, db.SelectContext(ctx, dest, query, args...)
+	})
+	return err
+}
+
+// 接続先に到達できないことによるエラーか
+func isConnError(err error) bool {
+	var netErr net.Error
+	return errors.Is(err, driver.ErrBadConn) || errors.Is(err, mysql.ErrInvalidConn) || errors.As(err, &netErr)
+}
+
+// interval ごとにレプリカの疎通と遅延を確認し、振り分けるレプリカを切り替える
+// ctx が終了するまで戻らないので、ゴルーチンで実行する
+func (c *Cluster) Monitor(ctx context.Context, interval, timeout time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		c.CheckReplicas(ctx, timeout)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// すべてのレプリカの疎通と遅延を確認する
+func (c *Cluster) CheckReplicas(ctx context.Context, timeout time.Duration) {
+	l := logger.FromContext(ctx)
+	for _, r := range c.Replicas {
+		cctx, cancel := context.WithTimeout(ctx, timeout)
+		lag, err := replicationLag(cctx, r.DB)
+		cancel()
+		if err == nil && lag > c.MaxLag {
+			err = errReplicaLagging
+		}
+
+		ok := err == nil
+		if r.healthy.Swap(ok) == ok {
+			continue
+		}
+		if ok {
+			l.Info("replica available", "replica", r.Name, "lag", lag)
+		} else {
+			l.Warn("replica unavailable, reading from primary", "replica", r.Name, "lag", lag, "error", err)
+		}
+	}
+}
+
+var errReplicaLagging = errors.New("replication lag exceeds the limit")
+
+// レプリケーションの遅延を返す
+// 遅延を取得できない場合 (レプリケーションが停止している場合など) はエラーを返す
+func replicationLag(ctx context.Context, db *sqlx.DB) (time.Duration, error) {
+	if driverName(db) == DriverPostgres {
+		// 受信した WAL をすべて適用済みの場合は、更新がないだけなので遅延はない
+		var sec float64
+		err := db.GetContext(ctx, &sec, `SELECT CASE
+			WHEN NOT pg_is_in_recovery() OR pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+			ELSE COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0)
+		END`)
+		return time.Duration(sec * float64(time.Second)), err
+	}
+
+	rows, err := db.QueryxContext(ctx, "SHOW REPLICA STATUS")
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		// レプリケーションを設定していないサーバ (マネージドサービスの読み出し専用エンドポイントなど)
+		return 0, rows.Err()
+	}
+	status := map[string]any{}
+	if err := rows.MapScan(status); err != nil {
+		return 0, err
+	}
+	// レプリケーションが停止している場合は NULL になる
+	switch sec := status["Seconds_Behind_Source"].(type) {
+	case []byte:
+		n, err := strconv.ParseInt(string(sec), 10, 64)
+		return time.Duration(n) * time.Second, err
+	case int64:
+		return time.Duration(sec) * time.Second, nil
+	default:
+		return 0, errors.New("replication is not running")
+	}
+}
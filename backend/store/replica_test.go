@@ -0,0 +1,260 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+)
+
+func newMockDB(t *testing.T) (*sqlx.DB, sqlmock.Sqlmock) {
+	t.Helper()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+		db.Close()
+	})
+	return sqlx.NewDb(db, DriverMySQL), mock
+}
+
+func TestCluster_Route(t *testing.T) {
+	t.Parallel()
+
+	connErr := &net.OpError{Op: "read", Net: "tcp", Err: errors.New("connection reset by peer")}
+	read := func(ctx context.Context, c *Cluster) error {
+		var n int
+		return c.GetContext(ctx, &n, "SELECT COUNT(*) FROM article")
+	}
+	write := func(ctx context.Context, c *Cluster) error {
+		_, err := c.ExecContext(ctx, "UPDATE article SET title = ?", "title")
+		return err
+	}
+	count := func() *sqlmock.Rows { return sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(1) }
+
+	tests := map[string]struct {
+		healthy       bool
+		ctx           func(ctx context.Context) context.Context
+		run           func(ctx context.Context, c *Cluster) error
+		expectPrimary func(mock sqlmock.Sqlmock)
+		expectReplica func(mock sqlmock.Sqlmock)
+		wantHealthy   bool
+	}{
+		"readFromReplica": {
+			healthy: true,
+			run:     read,
+			expectReplica: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT COUNT`).WillReturnRows(count())
+			},
+			wantHealthy: true,
+		},
+		"writeToPrimary": {
+			healthy: true,
+			run:     write,
+			expectPrimary: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(`UPDATE article`).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			wantHealthy: true,
+		},
+		// 同じセッションで書き込んだ後はプライマリから読み出す
+		"readYourWrites": {
+			healthy: true,
+			ctx:     WithSession,
+			run: func(ctx context.Context, c *Cluster) error {
+				if err := write(ctx, c); err != nil {
+					return err
+				}
+				return read(ctx, c)
+			},
+			expectPrimary: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(`UPDATE article`).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery(`SELECT COUNT`).WillReturnRows(count())
+			},
+			wantHealthy: true,
+		},
+		// セッションがなければ、書き込んだ後もレプリカから読み出す
+		"withoutSession": {
+			healthy: true,
+			run: func(ctx context.Context, c *Cluster) error {
+				if err := write(ctx, c); err != nil {
+					return err
+				}
+				return read(ctx, c)
+			},
+			expectPrimary: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(`UPDATE article`).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			expectReplica: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT COUNT`).WillReturnRows(count())
+			},
+			wantHealthy: true,
+		},
+		"forcePrimary": {
+			healthy: true,
+			ctx:     WithPrimary,
+			run:     read,
+			expectPrimary: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT COUNT`).WillReturnRows(count())
+			},
+			wantHealthy: true,
+		},
+		// プライマリから読み出しても、元のセッションは書き込んだことにならない
+		"forcePrimaryKeepsSession": {
+			healthy: true,
+			ctx:     WithSession,
+			run: func(ctx context.Context, c *Cluster) error {
+				if err := read(WithPrimary(ctx), c); err != nil {
+					return err
+				}
+				return read(ctx, c)
+			},
+			expectPrimary: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT COUNT`).WillReturnRows(count())
+			},
+			expectReplica: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT COUNT`).WillReturnRows(count())
+			},
+			wantHealthy: true,
+		},
+		"unhealthyReplica": {
+			run: read,
+			expectPrimary: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT COUNT`).WillReturnRows(count())
+			},
+		},
+		// 接続できなかったレプリカは切り離し、プライマリで実行し直す
+		"fallbackOnConnError": {
+			healthy: true,
+			run:     read,
+			expectReplica: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT COUNT`).WillReturnError(connErr)
+			},
+			expectPrimary: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT COUNT`).WillReturnRows(count())
+			},
+		},
+	}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			t.Parallel()
+
+			primary, pmock := newMockDB(t)
+			replica, rmock := newMockDB(t)
+			if tt.expectPrimary != nil {
+				tt.expectPrimary(pmock)
+			}
+			if tt.expectReplica != nil {
+				tt.expectReplica(rmock)
+			}
+			r := &Replica{DB: replica, Name: "replica"}
+			r.healthy.Store(tt.healthy)
+			c := &Cluster{Primary: primary, Replicas: []*Replica{r}}
+
+			ctx := context.Background()
+			if tt.ctx != nil {
+				ctx = tt.ctx(ctx)
+			}
+			if err := tt.run(ctx, c); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if r.Healthy() != tt.wantHealthy {
+				t.Errorf("want healthy %v, but got %v", tt.wantHealthy, r.Healthy())
+			}
+		})
+	}
+}
+
+func TestCluster_CheckReplicas(t *testing.T) {
+	t.Parallel()
+
+	status := func(lag any) *sqlmock.Rows {
+		// ドライバは数値も []byte で返す
+		return sqlmock.NewRows([]string{"Replica_IO_Running", "Seconds_Behind_Source"}).AddRow([]byte("Yes"), lag)
+	}
+	tests := map[string]struct {
+		expect func(mock sqlmock.Sqlmock)
+		want   bool
+	}{
+		"ok": {
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SHOW REPLICA STATUS`).WillReturnRows(status([]byte("1")))
+			},
+			want: true,
+		},
+		"lagging": {
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SHOW REPLICA STATUS`).WillReturnRows(status([]byte("10")))
+			},
+		},
+		// レプリケーションが停止している
+		"stopped": {
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SHOW REPLICA STATUS`).WillReturnRows(status(nil))
+			},
+		},
+		"notReplica": {
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SHOW REPLICA STATUS`).WillReturnRows(sqlmock.NewRows([]string{"Seconds_Behind_Source"}))
+			},
+			want: true,
+		},
+		"unreachable": {
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SHOW REPLICA STATUS`).WillReturnError(errors.New("connection refused"))
+			},
+		},
+	}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			t.Parallel()
+
+			replica, mock := newMockDB(t)
+			tt.expect(mock)
+			r := &Replica{DB: replica, Name: "replica"}
+			r.healthy.Store(!tt.want)
+			c := &Cluster{Replicas: []*Replica{r}, MaxLag: 5 * time.Second}
+
+			c.CheckReplicas(context.Background(), time.Second)
+			if r.Healthy() != tt.want {
+				t.Errorf("want healthy %v, but got %v", tt.want, r.Healthy())
+			}
+		})
+	}
+}
+
+func TestSplitHostPort(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		in       string
+		wantHost string
+		wantPort int
+		wantErr  bool
+	}{
+		"hostOnly":    {in: "replica1", wantHost: "replica1", wantPort: 3306},
+		"withPort":    {in: "replica1:3307", wantHost: "replica1", wantPort: 3307},
+		"ipv6":        {in: "[::1]:3307", wantHost: "::1", wantPort: 3307},
+		"invalidPort": {in: "replica1:port", wantErr: true},
+	}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			t.Parallel()
+
+			host, port, err := splitHostPort(tt.in, 3306)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("want error %v, but got %v", tt.wantErr, err)
+			}
+			if host != tt.wantHost || port != tt.wantPort {
+				t.Errorf("want %s:%d, but got %s:%d", tt.wantHost, tt.wantPort, host, port)
+			}
+		})
+	}
+}
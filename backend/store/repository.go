@@ -4,7 +4,6 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
-	"time"
 
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/iinuma0710/react-go-blog/backend/clock"
@@ -12,60 +11,54 @@ import (
 	"github.com/jmoiron/sqlx"
 )
 
-func New(ctx context.Context, cfg *config.Config, maxTrial int) (*sqlx.DB, func(), error) {
-	// 接続先のデータベースのパス
-	path := fmt.Sprintf(
-		// parseTime=true は時刻情報の取得に必須
-		"%s:%s@tcp(%s:%d)/%s?parseTime=true",
-		cfg.DBUser,
-		cfg.DBPassword,
-		cfg.DBHost,
-		cfg.DBPort,
-		cfg.DBName,
-	)
-	
-	var db *sql.DB
-	var err error
-	for i := 0; i < maxTrial; i++ {
-		fmt.Printf("mysql connection trial: %d", i + 1)
-
-		// database/sql の Open メソッドで接続
-		db, err = sql.Open("mysql", path)
+// 設定に応じてデータベースに接続する
+// BLOG_DATABASE_REPLICA_HOSTS が指定されている場合は、読み出し用のレプリカにも接続する
+func New(ctx context.Context, cfg *config.Config, maxTrial int) (*Cluster, func(), error) {
+	if cfg.DBDriver == "sqlite" {
+		db, cleanup, err := openSQLite(ctx, cfg.DBFile)
 		if err != nil {
-			fmt.Printf("sql.Open method failed: %v", err)
-			time.Sleep(time.Second * 2)
-			continue
-		}
-
-		// *sql.DB.PingContest メソッドで疎通確認
-		ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
-		defer cancel()
-		if err := db.PingContext(ctx); err != nil {
-			fmt.Printf("*sql.DB.PingContext method failed: %v", err)
-			time.Sleep(time.Second * 2)
-			continue
+			return nil, cleanup, err
 		}
-
-		// ここまでエラーがなければ接続確認できているので接続試行のループを抜ける
-		break
+		return &Cluster{Primary: db}, cleanup, nil
 	}
 
-	// 何らかのエラーで接続できなかった場合の処理
+	driverName := DriverMySQL
+	if cfg.DBDriver == "postgres" {
+		driverName = DriverPostgres
+	}
+	conn, err := connector(cfg, cfg.DBHost, cfg.DBPort, cfg.DBSocket)
+	if err != nil {
+		return nil, func() {}, err
+	}
+	db, cleanup, err := open(ctx, driverName, conn, cfg, maxTrial, defaultBackoff)
 	if err != nil {
-		if db != nil {
-			return nil, func() { _ = db.Close() }, fmt.Errorf("Cannot open sql connection: %v", err)
-		} else {
-			return nil, func() {}, fmt.Errorf("Cannot confirm sql connection: %v", err)
+		return nil, cleanup, err
+	}
+
+	c := &Cluster{Primary: db, MaxLag: cfg.DBReplicaMaxLag}
+	for _, h := range cfg.DBReplicaHosts {
+		host, port, err := splitHostPort(h, cfg.DBPort)
+		if err != nil {
+			_ = c.Close()
+			return nil, func() {}, err
 		}
+		conn, err := connector(cfg, host, port, "")
+		if err != nil {
+			_ = c.Close()
+			return nil, func() {}, fmt.Errorf("cannot open replica %s: %w", h, err)
+		}
+		// レプリカに接続できなくてもプライマリで読み出せるので、ここでは疎通を確認しない
+		rdb := sql.OpenDB(conn)
+		setPoolLimits(rdb, cfg)
+		c.Replicas = append(c.Replicas, &Replica{DB: sqlx.NewDb(rdb, driverName), Name: h})
 	}
-	
-	// *sqlx.DB に変換して返す
-	xdb := sqlx.NewDb(db, "mysql")
-	return xdb, func() { _ = db.Close() }, nil
+	// 起動直後から振り分けられるよう、最初の確認だけはここで行う
+	c.CheckReplicas(ctx, pingTimeout)
+	return c, func() { _ = c.Close() }, nil
 }
 
 type Beginner interface {
-	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+	BeginTxx(ctx context.Context, opts *sql.TxOptions) (*sqlx.Tx, error)
 }
 
 type Preparer interface {
@@ -85,13 +78,21 @@ type Queryer interface {
 	SelectContext(ctx context.Context, dest interface{}, query string, args ...any) error
 }
 
+// 更新結果に応じて読み出しも行う処理のために、両方を満たすインターフェースも用意する
+type ExecQueryer interface {
+	Execer
+	Queryer
+}
+
 // インターフェースが期待通りに宣言されているかを確認
 var (
-	_ Beginner = (*sqlx.DB)(nil)
-	_ Preparer = (*sqlx.DB)(nil)
-	_ Queryer  = (*sqlx.DB)(nil)
-	_ Execer   = (*sqlx.DB)(nil)
-	_ Execer   = (*sqlx.Tx)(nil)
+	_ Beginner    = (*sqlx.DB)(nil)
+	_ Preparer    = (*sqlx.DB)(nil)
+	_ Queryer     = (*sqlx.DB)(nil)
+	_ Execer      = (*sqlx.DB)(nil)
+	_ Execer      = (*sqlx.Tx)(nil)
+	_ ExecQueryer = (*sqlx.DB)(nil)
+	_ ExecQueryer = (*sqlx.Tx)(nil)
 )
 
 type Repository struct {
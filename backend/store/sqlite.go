@@ -0,0 +1,40 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+
+	"github.com/iinuma0710/react-go-blog/backend/logger"
+	"github.com/jmoiron/sqlx"
+	// cgo を無効にしてビルドした場合は、接続時にエラーになる
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// SQLite のデータベースファイルを開く
+// MySQL のコンテナを用意せずに、手元での動作確認やテストで使う
+func openSQLite(ctx context.Context, path string) (*sqlx.DB, func(), error) {
+	q := url.Values{}
+	// 書き込み中も読み出せるように WAL にし、ロックの競合はエラーにせずに待つ
+	q.Set("_journal_mode", "WAL")
+	q.Set("_busy_timeout", "5000")
+	// トランザクションの開始時に書き込みロックを取り、途中でのロックの昇格によるデッドロックを避ける
+	q.Set("_txlock", "immediate")
+	dsn := "file:" + path + "?" + q.Encode()
+
+	db, err := sql.Open(DriverSQLite, dsn)
+	if err != nil {
+		return nil, func() {}, fmt.Errorf("cannot open sqlite database: %w", err)
+	}
+	if path == ":memory:" {
+		// インメモリのデータベースはコネクションごとに別になるので、1 つのコネクションだけを使う
+		db.SetMaxOpenConns(1)
+	}
+	if err := db.PingContext(ctx); err != nil {
+		_ = db.Close()
+		return nil, func() {}, fmt.Errorf("cannot confirm sqlite database: %w", err)
+	}
+	logger.FromContext(ctx).Info("sqlite database opened", "path", path)
+	return sqlx.NewDb(db, DriverSQLite), func() { _ = db.Close() }, nil
+}
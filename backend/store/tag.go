@@ -0,0 +1,68 @@
+package store
+
+import (
+	"context"
+
+	"github.com/iinuma0710/react-go-blog/backend/entity"
+)
+
+// 同じ名前のタグが既に存在する場合は ErrAlreadyExists を返す
+func (r *Repository) AddTag(ctx context.Context, db Execer, t *entity.Tag) error {
+	t.CreatedAt = r.Clocker.Now()
+	sql := `INSERT INTO tag (name, created_at) VALUES (?, ?)`
+
+	id, err := insertID(ctx, db, sql, t.Name, t.CreatedAt)
+	if err != nil {
+		if isDuplicate(err) {
+			return ErrAlreadyExists
+		}
+		return err
+	}
+
+	t.ID = entity.TagID(id)
+	return nil
+}
+
+// 記事にタグを付ける
+func (r *Repository) AddArticleTag(ctx context.Context, db Execer, articleID entity.ArticleID, tagID entity.TagID) error {
+	sql := `INSERT INTO article_tag (article_id, tag_id) VALUES (?, ?)`
+	_, err := db.ExecContext(ctx, rebind(db, sql), articleID, tagID)
+	return err
+}
+
+// 記事ごとのタグ名を名前順に取得する
+func (r *Repository) ListArticleTags(ctx context.Context, db Queryer) (map[entity.ArticleID][]string, error) {
+	var rows []struct {
+		ArticleID entity.ArticleID `db:"article_id"`
+		Name      string           `db:"name"`
+	}
+	query := `SELECT at.article_id, t.name
+		FROM article_tag AS at JOIN tag AS t ON t.id = at.tag_id
+		ORDER BY at.article_id, t.name;`
+	if err := db.SelectContext(ctx, &rows, rebind(db, query)); err != nil {
+		return nil, err
+	}
+
+	tags := make(map[entity.ArticleID][]string)
+	for _, row := range rows {
+		tags[row.ArticleID] = append(tags[row.ArticleID], row.Name)
+	}
+	return tags, nil
+}
+
+func (r *Repository) ListTags(ctx context.Context, db Queryer) ([]*entity.Tag, error) {
+	tags := []*entity.Tag{}
+	query := `SELECT id, name, created_at FROM tag ORDER BY id;`
+
+	if err := db.SelectContext(ctx, &tags, rebind(db, query)); err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// 記事に付いているタグをすべて外す
+func (r *Repository) DeleteArticleTags(ctx context.Context, db Execer, articleID entity.ArticleID) error {
+	query := `DELETE FROM article_tag WHERE article_id = ?`
+	_, err := db.ExecContext(ctx, rebind(db, query), articleID)
+	return err
+}
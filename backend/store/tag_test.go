@@ -0,0 +1,93 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
+	"github.com/google/go-cmp/cmp"
+	"github.com/iinuma0710/react-go-blog/backend/clock"
+	"github.com/iinuma0710/react-go-blog/backend/entity"
+	"github.com/jmoiron/sqlx"
+)
+
+func TestRepository_AddTag(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		execErr error
+		wantErr error
+		wantID  entity.TagID
+	}{
+		"ok":        {wantID: 5},
+		"duplicate": {execErr: &mysql.MySQLError{Number: mysqlErrDupEntry}, wantErr: ErrAlreadyExists},
+	}
+
+	for n, tt := range tests {
+		tt := tt
+		t.Run(n, func(t *testing.T) {
+			t.Parallel()
+			ctx := context.Background()
+
+			c := clock.FixedClocker{}
+			tg := &entity.Tag{Name: "Go"}
+
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatal(err)
+			}
+			t.Cleanup(func() { db.Close() })
+
+			e := mock.ExpectExec(`INSERT INTO tag`).WithArgs(tg.Name, c.Now())
+			if tt.execErr != nil {
+				e.WillReturnError(tt.execErr)
+			} else {
+				e.WillReturnResult(sqlmock.NewResult(int64(tt.wantID), 1))
+			}
+
+			xdb := sqlx.NewDb(db, "mysql")
+			r := &Repository{Clocker: c}
+			if err := r.AddTag(ctx, xdb, tg); !errors.Is(err, tt.wantErr) {
+				t.Errorf("want error %v, but got %v", tt.wantErr, err)
+			}
+			if tg.ID != tt.wantID {
+				t.Errorf("want id %d, but got %d", tt.wantID, tg.ID)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestRepository_ListArticleTags(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	mock.ExpectQuery(`SELECT at.article_id, t.name FROM article_tag`).
+		WillReturnRows(sqlmock.NewRows([]string{"article_id", "name"}).
+			AddRow(1, "Docker").
+			AddRow(1, "Go").
+			AddRow(3, "React"))
+
+	xdb := sqlx.NewDb(db, "mysql")
+	r := &Repository{}
+	got, err := r.ListArticleTags(ctx, xdb)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[entity.ArticleID][]string{1: {"Docker", "Go"}, 3: {"React"}}
+	if d := cmp.Diff(got, want); d != "" {
+		t.Errorf("differs: (-got +want)\n%s", d)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,115 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/iinuma0710/react-go-blog/backend/store")
+
+// SQL 文の実行ごとにスパンを記録する Execer / Queryer のラッパ
+type TracedDB struct {
+	DB ExecQueryer
+	// db.system 属性に設定するデータベースの種類 (mysql など)
+	System string
+}
+
+var _ ExecQueryer = (*TracedDB)(nil)
+
+// 方言を切り替えられるよう、ラップしたデータベースのドライバ名を返す
+func (t *TracedDB) DriverName() string {
+	return driverName(t.DB)
+}
+
+func (t *TracedDB) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	ctx, span := t.start(ctx, query)
+	res, err := t.DB.ExecContext(ctx, query, args...)
+	if err == nil {
+		if n, rerr := res.RowsAffected(); rerr == nil {
+			span.SetAttributes(attribute.Int64("db.rows_affected", n))
+		}
+	}
+	end(span, err)
+	return res, err
+}
+
+func (t *TracedDB) NamedExecContext(ctx context.Context, query string, arg interface{}) (sql.Result, error) {
+	ctx, span := t.start(ctx, query)
+	res, err := t.DB.NamedExecContext(ctx, query, arg)
+	end(span, err)
+	return res, err
+}
+
+func (t *TracedDB) PreparexContext(ctx context.Context, query string) (*sqlx.Stmt, error) {
+	ctx, span := t.start(ctx, query)
+	stmt, err := t.DB.PreparexContext(ctx, query)
+	end(span, err)
+	return stmt, err
+}
+
+func (t *TracedDB) QueryxContext(ctx context.Context, query string, args ...any) (*sqlx.Rows, error) {
+	ctx, span := t.start(ctx, query)
+	rows, err := t.DB.QueryxContext(ctx, query, args...)
+	end(span, err)
+	return rows, err
+}
+
+func (t *TracedDB) QueryRowxContext(ctx context.Context, query string, args ...any) *sqlx.Row {
+	ctx, span := t.start(ctx, query)
+	row := t.DB.QueryRowxContext(ctx, query, args...)
+	end(span, row.Err())
+	return row
+}
+
+func (t *TracedDB) GetContext(ctx context.Context, dest interface{}, query string, args ...any) error {
+	ctx, span := t.start(ctx, query)
+	err := t.DB.GetContext(ctx, dest, query, args...)
+	end(span, err)
+	return err
+}
+
+func (t *TracedDB) SelectContext(ctx context.Context, dest interface{}, query string, args ...any) error {
+	ctx, span := t.start(ctx, query)
+	err := t.DB.SelectContext(ctx, dest, query, args...)
+	end(span, err)
+	return err
+}
+
+func (t *TracedDB) start(ctx context.Context, query string) (context.Context, trace.Span) {
+	op := operation(query)
+	return tracer.Start(ctx, "sql "+op,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemKey.String(t.System),
+			semconv.DBOperationName(op),
+			semconv.DBQueryText(strings.Join(strings.Fields(query), " ")),
+		),
+	)
+}
+
+func end(span trace.Span, err error) {
+	// 該当する行がないのは正常系として扱う
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// SQL 文の先頭のキーワード (SELECT や INSERT など) を返す
+func operation(query string) string {
+	fs := strings.Fields(query)
+	if len(fs) == 0 {
+		return "UNKNOWN"
+	}
+	return strings.ToUpper(fs[0])
+}
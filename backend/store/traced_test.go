@@ -0,0 +1,69 @@
+package store
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/iinuma0710/react-go-blog/backend/clock"
+	"github.com/iinuma0710/react-go-blog/backend/entity"
+	"github.com/jmoiron/sqlx"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracedDB(t *testing.T) {
+	ctx := context.Background()
+
+	sr := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr)))
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	query := `SELECT ` + articleColumns + ` FROM article WHERE id = ?;`
+	now := clock.FixedClocker{}.Now()
+	mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "content", "slug", "status", "version", "author_id", "created_at", "updated_at"}).
+			AddRow(1, "title", "content", "", "draft", 1, 0, now, now))
+	mock.ExpectExec(`UPDATE article`).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	sut := &TracedDB{DB: sqlx.NewDb(db, "mysql"), System: "mysql"}
+	r := &Repository{Clocker: clock.FixedClocker{}}
+	if _, err := r.GetArticle(ctx, sut, 1); err != nil {
+		t.Fatal(err)
+	}
+	_ = r.UpdateArticle(ctx, sut, &entity.Article{ID: 1, Version: 1})
+
+	spans := sr.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("want 2 spans, but got %d", len(spans))
+	}
+	if got := spans[0].Name(); got != "sql SELECT" {
+		t.Errorf("want span name %q, but got %q", "sql SELECT", got)
+	}
+	var stmt string
+	for _, a := range spans[0].Attributes() {
+		if a.Key == "db.query.text" {
+			stmt = a.Value.AsString()
+		}
+	}
+	// 改行とインデントは空白 1 つにまとめる
+	if want := "SELECT id, title, content, COALESCE(slug, '') AS slug, status, version, COALESCE(author_id, 0) AS author_id, created_at, updated_at FROM article WHERE id = ?;"; stmt != want {
+		t.Errorf("unexpected db.query.text %q", stmt)
+	}
+	if got := spans[1].Name(); got != "sql UPDATE" {
+		t.Errorf("want span name %q, but got %q", "sql UPDATE", got)
+	}
+	// 更新件数が 0 件なのは SQL のエラーではない
+	if spans[1].Status().Code == codes.Error {
+		t.Errorf("want no error status, but got %v", spans[1].Status())
+	}
+}
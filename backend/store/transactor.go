@@ -0,0 +1,136 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/iinuma0710/react-go-blog/backend/logger"
+	"github.com/jmoiron/sqlx"
+)
+
+// トランザクションの開始時の設定
+type TxOption func(opts *sql.TxOptions)
+
+// 分離レベルを指定する
+func WithIsolation(level sql.IsolationLevel) TxOption {
+	return func(opts *sql.TxOptions) { opts.Isolation = level }
+}
+
+// 読み出し専用のトランザクションにする
+func ReadOnly() TxOption {
+	return func(opts *sql.TxOptions) { opts.ReadOnly = true }
+}
+
+// fn をトランザクションの中で実行する
+// デッドロックなどで失敗した場合は、トランザクションごと fn を実行し直す
+type Transactor struct {
+	DB Beginner
+	// 分離レベルの既定値 (sql.LevelDefault の場合はデータベースの既定)
+	Isolation sql.IsolationLevel
+	// 実行し直す最大の回数と、1 回目の待ち時間 (実行し直すごとに倍にする)
+	MaxRetries   int
+	RetryBackoff time.Duration
+	// fn に渡す前にトランザクションを包む (TracedDB でスパンを記録する場合など、nil の場合はそのまま渡す)
+	Wrap func(tx ExecQueryer) ExecQueryer
+}
+
+func NewTransactor(db Beginner) *Transactor {
+	return &Transactor{DB: db, MaxRetries: 3, RetryBackoff: 50 * time.Millisecond}
+}
+
+// 実行中のトランザクション
+type txState struct {
+	owner *Transactor
+	tx    *sqlx.Tx
+	depth int
+}
+
+type txKey struct{}
+
+// fn をトランザクションの中で実行し、エラーを返さなければコミットする
+// fn がエラーを返すかパニックした場合はロールバックする
+// fn に渡したコンテキストで入れ子に呼び出した場合は、同じトランザクションの中にセーブポイントを作り、
+// 失敗した場合はそのセーブポイントまでを取り消す (opts は外側のトランザクションのものを使う)
+// 実行し直すことがあるので、fn はデータベース以外への副作用を繰り返しても問題ないようにする
+func (t *Transactor) InTx(ctx context.Context, fn func(ctx context.Context, tx ExecQueryer) error, opts ...TxOption) error {
+	if st, ok := ctx.Value(txKey{}).(*txState); ok && st.owner == t {
+		return t.savepoint(ctx, st, fn)
+	}
+
+	txOpts := &sql.TxOptions{Isolation: t.Isolation}
+	for _, opt := range opts {
+		opt(txOpts)
+	}
+
+	backoff := t.RetryBackoff
+	for i := 0; ; i++ {
+		err := t.run(ctx, txOpts, fn)
+		if err == nil || !isRetryable(err) || i >= t.MaxRetries {
+			return err
+		}
+		logger.FromContext(ctx).Warn("transaction retrying", "error", err, "retry", i+1)
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+		backoff *= 2
+	}
+}
+
+func (t *Transactor) run(ctx context.Context, opts *sql.TxOptions, fn func(ctx context.Context, tx ExecQueryer) error) (err error) {
+	tx, err := t.DB.BeginTxx(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			_ = tx.Rollback()
+		}
+	}()
+
+	if err := fn(context.WithValue(ctx, txKey{}, &txState{owner: t, tx: tx}), t.wrap(tx)); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	committed = true
+	return nil
+}
+
+func (t *Transactor) savepoint(ctx context.Context, st *txState, fn func(ctx context.Context, tx ExecQueryer) error) (err error) {
+	nested := &txState{owner: t, tx: st.tx, depth: st.depth + 1}
+	name := fmt.Sprintf("sp_%d", nested.depth)
+	if _, err := st.tx.ExecContext(ctx, "SAVEPOINT "+name); err != nil {
+		return fmt.Errorf("failed to create savepoint: %w", err)
+	}
+	released := false
+	defer func() {
+		if !released {
+			_, _ = st.tx.ExecContext(context.WithoutCancel(ctx), "ROLLBACK TO SAVEPOINT "+name)
+		}
+	}()
+
+	if err := fn(context.WithValue(ctx, txKey{}, nested), t.wrap(st.tx)); err != nil {
+		return err
+	}
+	if _, err := st.tx.ExecContext(ctx, "RELEASE SAVEPOINT "+name); err != nil {
+		return err
+	}
+	released = true
+	return nil
+}
+
+func (t *Transactor) wrap(tx *sqlx.Tx) ExecQueryer {
+	if t.Wrap == nil {
+		return tx
+	}
+	return t.Wrap(tx)
+}
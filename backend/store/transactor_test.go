@@ -0,0 +1,172 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
+	"github.com/jmoiron/sqlx"
+)
+
+// 開始時の設定を記録する Beginner
+type recordingBeginner struct {
+	db   *sqlx.DB
+	opts []*sql.TxOptions
+}
+
+func (b *recordingBeginner) BeginTxx(ctx context.Context, opts *sql.TxOptions) (*sqlx.Tx, error) {
+	b.opts = append(b.opts, opts)
+	return b.db.BeginTxx(ctx, opts)
+}
+
+func TestTransactor_InTx(t *testing.T) {
+	t.Parallel()
+
+	errFail := errors.New("fail")
+	deadlock := &mysql.MySQLError{Number: mysqlErrLockDeadlock, Message: "Deadlock found"}
+	insert := func(ctx context.Context, tx ExecQueryer) error {
+		_, err := tx.ExecContext(ctx, "INSERT INTO tag (name) VALUES (?)", "go")
+		return err
+	}
+
+	tests := map[string]struct {
+		expect    func(mock sqlmock.Sqlmock)
+		fn        func(tr *Transactor) func(ctx context.Context, tx ExecQueryer) error
+		wantErr   error
+		wantPanic bool
+		wantTries int
+	}{
+		"commit": {
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(`INSERT INTO tag`).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			fn:        func(*Transactor) func(context.Context, ExecQueryer) error { return insert },
+			wantTries: 1,
+		},
+		"rollbackOnError": {
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(`INSERT INTO tag`).WillReturnError(errFail)
+				mock.ExpectRollback()
+			},
+			fn:        func(*Transactor) func(context.Context, ExecQueryer) error { return insert },
+			wantErr:   errFail,
+			wantTries: 1,
+		},
+		"rollbackOnPanic": {
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectRollback()
+			},
+			fn: func(*Transactor) func(context.Context, ExecQueryer) error {
+				return func(context.Context, ExecQueryer) error { panic("boom") }
+			},
+			wantPanic: true,
+			wantTries: 1,
+		},
+		// デッドロックの場合はトランザクションごと実行し直す
+		"retryOnDeadlock": {
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(`INSERT INTO tag`).WillReturnError(deadlock)
+				mock.ExpectRollback()
+				mock.ExpectBegin()
+				mock.ExpectExec(`INSERT INTO tag`).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			fn:        func(*Transactor) func(context.Context, ExecQueryer) error { return insert },
+			wantTries: 2,
+		},
+		"giveUpRetrying": {
+			expect: func(mock sqlmock.Sqlmock) {
+				for range 3 {
+					mock.ExpectBegin()
+					mock.ExpectExec(`INSERT INTO tag`).WillReturnError(deadlock)
+					mock.ExpectRollback()
+				}
+			},
+			fn:        func(*Transactor) func(context.Context, ExecQueryer) error { return insert },
+			wantErr:   deadlock,
+			wantTries: 3,
+		},
+		// 入れ子の呼び出しはセーブポイントにする
+		"nested": {
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(`SAVEPOINT sp_1`)).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(`INSERT INTO tag`).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(regexp.QuoteMeta(`RELEASE SAVEPOINT sp_1`)).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+			},
+			fn: func(tr *Transactor) func(context.Context, ExecQueryer) error {
+				return func(ctx context.Context, tx ExecQueryer) error {
+					return tr.InTx(ctx, insert)
+				}
+			},
+			wantTries: 1,
+		},
+		// 内側が失敗した場合はセーブポイントまで戻し、外側はコミットできる
+		"nestedRollback": {
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(`SAVEPOINT sp_1`)).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(`INSERT INTO tag`).WillReturnError(errFail)
+				mock.ExpectExec(regexp.QuoteMeta(`ROLLBACK TO SAVEPOINT sp_1`)).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+			},
+			fn: func(tr *Transactor) func(context.Context, ExecQueryer) error {
+				return func(ctx context.Context, tx ExecQueryer) error {
+					if err := tr.InTx(ctx, insert); !errors.Is(err, errFail) {
+						return errors.New("want inner error")
+					}
+					return nil
+				}
+			},
+			wantTries: 1,
+		},
+	}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			t.Parallel()
+
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatal(err)
+			}
+			t.Cleanup(func() { db.Close() })
+			tt.expect(mock)
+
+			b := &recordingBeginner{db: sqlx.NewDb(db, DriverMySQL)}
+			tr := &Transactor{DB: b, Isolation: sql.LevelReadCommitted, MaxRetries: 2}
+			func() {
+				defer func() {
+					if r := recover(); (r != nil) != tt.wantPanic {
+						t.Errorf("want panic %v, but got %v", tt.wantPanic, r)
+					}
+				}()
+				err := tr.InTx(context.Background(), tt.fn(tr), WithIsolation(sql.LevelSerializable))
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("want error %v, but got %v", tt.wantErr, err)
+				}
+			}()
+
+			if len(b.opts) != tt.wantTries {
+				t.Errorf("want %d transactions, but got %d", tt.wantTries, len(b.opts))
+			}
+			for _, opts := range b.opts {
+				if opts.Isolation != sql.LevelSerializable {
+					t.Errorf("want isolation %v, but got %v", sql.LevelSerializable, opts.Isolation)
+				}
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
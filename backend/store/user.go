@@ -0,0 +1,40 @@
+package store
+
+import (
+	"context"
+
+	"github.com/iinuma0710/react-go-blog/backend/entity"
+)
+
+// user は PostgreSQL の予約語なので、識別子として引用符で囲む (rebind で方言に合わせる)
+const userTable = "`user`"
+
+// 同じ名前のユーザが既に存在する場合は ErrAlreadyExists を返す
+func (r *Repository) RegisterUser(ctx context.Context, db Execer, u *entity.User) error {
+	u.CreatedAt = r.Clocker.Now()
+	u.UpdatedAt = r.Clocker.Now()
+	sql := `INSERT INTO ` + userTable + `
+		(name, email, password, role, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?)`
+
+	id, err := insertID(ctx, db, sql, u.Name, u.Email, u.Password, u.Role, u.CreatedAt, u.UpdatedAt)
+	if err != nil {
+		if isDuplicate(err) {
+			return ErrAlreadyExists
+		}
+		return err
+	}
+
+	u.ID = entity.UserID(id)
+	return nil
+}
+
+func (r *Repository) ListUsers(ctx context.Context, db Queryer) ([]*entity.User, error) {
+	users := []*entity.User{}
+	sql := `SELECT id, name, email, password, role, created_at, updated_at FROM ` + userTable + ` ORDER BY id;`
+
+	if err := db.SelectContext(ctx, &users, rebind(db, sql)); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
@@ -0,0 +1,63 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
+	"github.com/iinuma0710/react-go-blog/backend/clock"
+	"github.com/iinuma0710/react-go-blog/backend/entity"
+	"github.com/jmoiron/sqlx"
+)
+
+func TestRepository_RegisterUser(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		execErr error
+		wantErr error
+		wantID  entity.UserID
+	}{
+		"ok":        {wantID: 20},
+		"duplicate": {execErr: &mysql.MySQLError{Number: mysqlErrDupEntry}, wantErr: ErrAlreadyExists},
+	}
+
+	for n, tt := range tests {
+		tt := tt
+		t.Run(n, func(t *testing.T) {
+			t.Parallel()
+			ctx := context.Background()
+
+			c := clock.FixedClocker{}
+			u := &entity.User{Name: "admin", Email: "admin@example.com", Password: "hashed", Role: entity.RoleAdmin}
+
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatal(err)
+			}
+			t.Cleanup(func() { db.Close() })
+
+			e := mock.ExpectExec(`INSERT INTO .user.`).
+				WithArgs(u.Name, u.Email, u.Password, u.Role, c.Now(), c.Now())
+			if tt.execErr != nil {
+				e.WillReturnError(tt.execErr)
+			} else {
+				e.WillReturnResult(sqlmock.NewResult(int64(tt.wantID), 1))
+			}
+
+			xdb := sqlx.NewDb(db, "mysql")
+			r := &Repository{Clocker: c}
+			if err := r.RegisterUser(ctx, xdb, u); !errors.Is(err, tt.wantErr) {
+				t.Errorf("want error %v, but got %v", tt.wantErr, err)
+			}
+			if u.ID != tt.wantID {
+				t.Errorf("want id %d, but got %d", tt.wantID, u.ID)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/iinuma0710/react-go-blog/backend/config"
+	"github.com/iinuma0710/react-go-blog/backend/logger"
+)
+
+// 証明書のファイルが更新されたかを確認する間隔
+const checkInterval = time.Second
+
+var versions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// 設定に応じた TLS の設定を作成する
+// 証明書は接続のたびにファイルの更新を確認し、更新されていれば読み込み直す
+func New(cfg *config.Config) (*tls.Config, error) {
+	if cfg.TLSCertFile == "" || cfg.TLSKeyFile == "" {
+		return nil, errors.New("both TLS_CERT_FILE and TLS_KEY_FILE are required")
+	}
+	v, ok := versions[cfg.TLSMinVersion]
+	if !ok {
+		return nil, fmt.Errorf("unknown TLS version: %q", cfg.TLSMinVersion)
+	}
+	r, err := NewReloader(cfg.TLSCertFile, cfg.TLSKeyFile)
+	if err != nil {
+		return nil, err
+	}
+	return &tls.Config{
+		MinVersion:     v,
+		GetCertificate: r.GetCertificate,
+	}, nil
+}
+
+// 証明書と秘密鍵のファイルを監視し、更新されたら読み込み直す
+type Reloader struct {
+	certFile, keyFile string
+
+	mu      sync.RWMutex
+	cert    *tls.Certificate
+	modTime time.Time
+	checked time.Time
+}
+
+func NewReloader(certFile, keyFile string) (*Reloader, error) {
+	r := &Reloader{certFile: certFile, keyFile: keyFile}
+	mt, err := r.modTimeOfFiles()
+	if err != nil {
+		return nil, err
+	}
+	if err := r.load(mt); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// tls.Config の GetCertificate に渡す関数
+// 読み込み直しに失敗した場合は、それまでの証明書を使い続ける
+func (r *Reloader) GetCertificate(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	if err := r.reloadIfModified(time.Now()); err != nil {
+		logger.FromContext(hello.Context()).Error("failed to reload certificate", "error", err)
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.cert, nil
+}
+
+func (r *Reloader) reloadIfModified(now time.Time) error {
+	r.mu.Lock()
+	if now.Sub(r.checked) < checkInterval {
+		r.mu.Unlock()
+		return nil
+	}
+	r.checked = now
+	r.mu.Unlock()
+
+	mt, err := r.modTimeOfFiles()
+	if err != nil {
+		return err
+	}
+	r.mu.RLock()
+	modified := mt.After(r.modTime)
+	r.mu.RUnlock()
+	if !modified {
+		return nil
+	}
+	return r.load(mt)
+}
+
+func (r *Reloader) load(mt time.Time) error {
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load key pair: %w", err)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cert = &cert
+	r.modTime = mt
+	return nil
+}
+
+// 証明書と秘密鍵のファイルのうち、新しい方の更新日時を返す
+func (r *Reloader) modTimeOfFiles() (time.Time, error) {
+	var latest time.Time
+	for _, f := range []string{r.certFile, r.keyFile} {
+		fi, err := os.Stat(f)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if fi.ModTime().After(latest) {
+			latest = fi.ModTime()
+		}
+	}
+	return latest, nil
+}
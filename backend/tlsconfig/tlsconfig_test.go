@@ -0,0 +1,150 @@
+package tlsconfig
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/iinuma0710/react-go-blog/backend/config"
+)
+
+// 自己署名の証明書と秘密鍵をファイルに書き出す
+func writeKeyPair(t *testing.T, dir, cn string, mt time.Time) (string, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	kder, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	files := map[string]*pem.Block{
+		certFile: {Type: "CERTIFICATE", Bytes: der},
+		keyFile:  {Type: "EC PRIVATE KEY", Bytes: kder},
+	}
+	for f, b := range files {
+		if err := os.WriteFile(f, pem.EncodeToMemory(b), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(f, mt, mt); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return certFile, keyFile
+}
+
+// TLS のハンドシェイクを行い、サーバから提示された証明書の CN を返す
+func commonName(t *testing.T, r *Reloader) string {
+	t.Helper()
+
+	sc, cc := net.Pipe()
+	defer sc.Close()
+	defer cc.Close()
+	go func() {
+		_ = tls.Server(sc, &tls.Config{GetCertificate: r.GetCertificate}).Handshake()
+	}()
+	c := tls.Client(cc, &tls.Config{InsecureSkipVerify: true})
+	if err := c.Handshake(); err != nil {
+		t.Fatal(err)
+	}
+	return c.ConnectionState().PeerCertificates[0].Subject.CommonName
+}
+
+func TestReloader(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	base := time.Now().Add(-time.Hour)
+	certFile, keyFile := writeKeyPair(t, dir, "old", base)
+
+	r, err := NewReloader(certFile, keyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := commonName(t, r); got != "old" {
+		t.Errorf("want %q, but got %q", "old", got)
+	}
+
+	// ファイルが更新されたら読み込み直す
+	writeKeyPair(t, dir, "new", base.Add(time.Minute))
+	r.checked = time.Time{}
+	if got := commonName(t, r); got != "new" {
+		t.Errorf("want %q, but got %q", "new", got)
+	}
+
+	// 読み込みに失敗した場合はそれまでの証明書を使い続ける
+	if err := os.WriteFile(certFile, []byte("broken"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	r.checked = time.Time{}
+	if err := r.reloadIfModified(time.Now()); err == nil {
+		t.Error("want error for broken certificate")
+	}
+	r.checked = time.Time{}
+	if got := commonName(t, r); got != "new" {
+		t.Errorf("want %q, but got %q", "new", got)
+	}
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	certFile, keyFile := writeKeyPair(t, t.TempDir(), "blog", time.Now())
+
+	tests := map[string]struct {
+		cfg     config.Config
+		wantErr bool
+		wantMin uint16
+	}{
+		"ok": {
+			cfg:     config.Config{TLSCertFile: certFile, TLSKeyFile: keyFile, TLSMinVersion: "1.3"},
+			wantMin: tls.VersionTLS13,
+		},
+		"noKey": {
+			cfg:     config.Config{TLSCertFile: certFile, TLSMinVersion: "1.2"},
+			wantErr: true,
+		},
+		"unknownVersion": {
+			cfg:     config.Config{TLSCertFile: certFile, TLSKeyFile: keyFile, TLSMinVersion: "1.4"},
+			wantErr: true,
+		},
+	}
+	for n, tt := range tests {
+		tt := tt
+		t.Run(n, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := New(&tt.cfg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("want error %v, but got %v", tt.wantErr, err)
+			}
+			if err == nil && got.MinVersion != tt.wantMin {
+				t.Errorf("want min version %x, but got %x", tt.wantMin, got.MinVersion)
+			}
+		})
+	}
+}
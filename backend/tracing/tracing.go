@@ -0,0 +1,88 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/iinuma0710/react-go-blog/backend/config"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+const serviceName = "blog-backend"
+
+// 設定に応じたエクスポータで TracerProvider を作成し、グローバルに登録する
+// W3C Trace Context 形式の traceparent ヘッダを伝搬するようにプロパゲータも登録する
+// 戻り値の関数でバッファに残っているスパンを出力して終了する
+func Setup(ctx context.Context, cfg *config.Config) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	exp, closeExp, err := newExporter(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if exp == nil {
+		// エクスポータが指定されていない場合はグローバルの no-op の TracerProvider のままにする
+		return func(context.Context) error { return nil }, nil
+	}
+
+	res := resource.NewSchemaless(
+		semconv.ServiceName(serviceName),
+		attribute.String("deployment.environment", cfg.BackendEnv),
+	)
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exp),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.TraceSampleRatio))),
+	)
+	otel.SetTracerProvider(tp)
+
+	return func(ctx context.Context) error {
+		err := tp.Shutdown(ctx)
+		if cerr := closeExp(); err == nil {
+			err = cerr
+		}
+		return err
+	}, nil
+}
+
+func newExporter(ctx context.Context, cfg *config.Config) (sdktrace.SpanExporter, func() error, error) {
+	noop := func() error { return nil }
+
+	switch cfg.TraceExporter {
+	case "", "none":
+		return nil, noop, nil
+	case "stdout":
+		exp, err := stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+		return exp, noop, err
+	case "file":
+		// オフラインの開発環境やテストでも確認できるよう、1 行 1 スパンの JSON でファイルに追記する
+		f, err := os.OpenFile(cfg.TraceFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to open trace file: %w", err)
+		}
+		exp, err := stdouttrace.New(stdouttrace.WithWriter(io.Writer(f)))
+		if err != nil {
+			_ = f.Close()
+			return nil, nil, err
+		}
+		return exp, f.Close, nil
+	case "otlp":
+		// 送信先などは OTEL_EXPORTER_OTLP_ENDPOINT などの標準の環境変数で指定する
+		exp, err := otlptracehttp.New(ctx)
+		return exp, noop, err
+	default:
+		return nil, nil, fmt.Errorf("unknown trace exporter: %q", cfg.TraceExporter)
+	}
+}
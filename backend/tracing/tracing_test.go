@@ -0,0 +1,47 @@
+package tracing
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/iinuma0710/react-go-blog/backend/config"
+	"go.opentelemetry.io/otel"
+)
+
+func TestSetup_file(t *testing.T) {
+	ctx := context.Background()
+
+	path := filepath.Join(t.TempDir(), "traces.jsonl")
+	shutdown, err := Setup(ctx, &config.Config{
+		BackendEnv:       "dev",
+		TraceExporter:    "file",
+		TraceFile:        path,
+		TraceSampleRatio: 1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, span := otel.Tracer("test").Start(ctx, "test span")
+	span.End()
+	if err := shutdown(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(got), `"Name":"test span"`) {
+		t.Errorf("want span in trace file, but got %q", got)
+	}
+}
+
+func TestSetup_unknown(t *testing.T) {
+	if _, err := Setup(context.Background(), &config.Config{TraceExporter: "zipkin"}); err == nil {
+		t.Error("want error for unknown exporter")
+	}
+}
@@ -0,0 +1,121 @@
+package wxr
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// WordPress のエクスポートファイル (WXR) の内容
+// wp: 名前空間の URL は WXR のバージョンごとに異なるので、要素名だけで対応付ける
+type Export struct {
+	Title   string   `xml:"title"`
+	Link    string   `xml:"link"`
+	Authors []Author `xml:"author"`
+	Items   []Item   `xml:"item"`
+}
+
+type Author struct {
+	Login       string `xml:"author_login"`
+	Email       string `xml:"author_email"`
+	DisplayName string `xml:"author_display_name"`
+}
+
+type Item struct {
+	Title       string     `xml:"title"`
+	Link        string     `xml:"link"`
+	GUID        string     `xml:"guid"`
+	Creator     string     `xml:"http://purl.org/dc/elements/1.1/ creator"`
+	Content     string     `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+	PostID      int64      `xml:"post_id"`
+	PostDate    string     `xml:"post_date"`
+	PostDateGMT string     `xml:"post_date_gmt"`
+	PostName    string     `xml:"post_name"`
+	Status      string     `xml:"status"`
+	PostType    string     `xml:"post_type"`
+	Categories  []Category `xml:"category"`
+	Comments    []Comment  `xml:"comment"`
+}
+
+// カテゴリ (domain が category) とタグ (domain が post_tag)
+type Category struct {
+	Domain   string `xml:"domain,attr"`
+	Nicename string `xml:"nicename,attr"`
+	Name     string `xml:",chardata"`
+}
+
+type Comment struct {
+	ID          int64  `xml:"comment_id"`
+	Author      string `xml:"comment_author"`
+	AuthorEmail string `xml:"comment_author_email"`
+	Date        string `xml:"comment_date"`
+	DateGMT     string `xml:"comment_date_gmt"`
+	Content     string `xml:"comment_content"`
+	// 1 が承認済みで、0 (未承認)、spam、trash などがある
+	Approved string `xml:"comment_approved"`
+	// 空または comment が通常のコメントで、pingback や trackback もある
+	Type string `xml:"comment_type"`
+}
+
+func Parse(r io.Reader) (*Export, error) {
+	var rss struct {
+		Channel Export `xml:"channel"`
+	}
+	if err := xml.NewDecoder(r).Decode(&rss); err != nil {
+		return nil, fmt.Errorf("invalid WXR: %w", err)
+	}
+	return &rss.Channel, nil
+}
+
+// 投稿日時 (下書きなどで GMT の日時がない場合はローカルタイムゾーンの日時)
+func (i *Item) Date() time.Time {
+	return parseDate(i.PostDateGMT, i.PostDate)
+}
+
+// URL エンコードされたスラッグを元に戻す
+func (i *Item) Slug() string {
+	s, err := url.PathUnescape(i.PostName)
+	if err != nil {
+		return i.PostName
+	}
+	return s
+}
+
+// 取り込み元を識別するキー
+func (i *Item) Source() string {
+	key := strings.TrimSpace(i.GUID)
+	if key == "" {
+		key = fmt.Sprintf("%s?p=%d", i.Link, i.PostID)
+	}
+	return "wordpress:" + key
+}
+
+// カテゴリとタグの名前 (未分類は除く)
+func (i *Item) Tags() []string {
+	var tags []string
+	for _, c := range i.Categories {
+		if (c.Domain == "category" || c.Domain == "post_tag") && c.Nicename != "uncategorized" {
+			tags = append(tags, strings.TrimSpace(c.Name))
+		}
+	}
+	return tags
+}
+
+func (c *Comment) CreatedAt() time.Time {
+	return parseDate(c.DateGMT, c.Date)
+}
+
+const dateLayout = "2006-01-02 15:04:05"
+
+func parseDate(gmt, local string) time.Time {
+	if t, err := time.Parse(dateLayout, gmt); err == nil && !t.IsZero() && t.Year() > 1 {
+		return t
+	}
+	if t, err := time.ParseInLocation(dateLayout, local, time.Local); err == nil && t.Year() > 1 {
+		return t
+	}
+	return time.Time{}
+}
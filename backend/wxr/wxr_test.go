@@ -0,0 +1,60 @@
+package wxr
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	f, err := os.Open("testdata/export.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	exp, err := Parse(f)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(exp.Authors) != 2 || exp.Authors[0].Login != "hanako" || exp.Authors[0].Email != "hanako@example.com" {
+		t.Errorf("unexpected authors: %+v", exp.Authors)
+	}
+	if len(exp.Items) != 5 {
+		t.Fatalf("want 5 items, but got %d", len(exp.Items))
+	}
+
+	post := exp.Items[0]
+	if post.Creator != "hanako" || post.Status != "publish" || post.PostType != "post" {
+		t.Errorf("unexpected item: %+v", post)
+	}
+	// 抜粋 (excerpt:encoded) ではなく本文を読み込む
+	if post.Content != "<p><strong>net/http</strong> を使います。</p><table><tr><td>1</td></tr></table>" {
+		t.Errorf("unexpected content: %q", post.Content)
+	}
+	if want := time.Date(2020, 4, 1, 0, 0, 0, 0, time.UTC); !post.Date().Equal(want) {
+		t.Errorf("want date %v, but got %v", want, post.Date())
+	}
+	if d := cmp.Diff(post.Tags(), []string{"Go", "HTTP"}); d != "" {
+		t.Errorf("differs: (-got +want)\n%s", d)
+	}
+	if post.Source() != "wordpress:https://old.example.com/?p=10" {
+		t.Errorf("unexpected source: %q", post.Source())
+	}
+	if len(post.Comments) != 3 || post.Comments[1].Approved != "spam" {
+		t.Errorf("unexpected comments: %+v", post.Comments)
+	}
+
+	// 下書きは GMT の日時を持たない
+	draft := exp.Items[1]
+	if want := time.Date(2020, 5, 1, 9, 0, 0, 0, time.Local); !draft.Date().Equal(want) {
+		t.Errorf("want date %v, but got %v", want, draft.Date())
+	}
+	if draft.Slug() != "下書き" {
+		t.Errorf("want decoded slug, but got %q", draft.Slug())
+	}
+}